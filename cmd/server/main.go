@@ -2,22 +2,27 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/Kilat-Pet-Delivery/lib-common/auth"
 	"github.com/Kilat-Pet-Delivery/lib-common/database"
 	"github.com/Kilat-Pet-Delivery/lib-common/health"
 	"github.com/Kilat-Pet-Delivery/lib-common/logger"
 	"github.com/Kilat-Pet-Delivery/lib-common/middleware"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 	svcconfig "github.com/Kilat-Pet-Delivery/service-identity/internal/config"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/federation"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/passwords"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/webauthn"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -57,7 +62,7 @@ func main() {
 		// conventional unique-constraint name (uni_runner_applications_ic_number)
 		// which doesn't match the SQL migration's name (runner_applications_ic_number_key).
 		// SQL migrations own this table.
-		if err := db.AutoMigrate(&repository.UserModel{}, &repository.RefreshTokenModel{}, &repository.PasswordResetModel{}, &repository.ReferralModel{}, &repository.UserReferralCodeModel{}); err != nil {
+		if err := db.AutoMigrate(&repository.UserModel{}, &repository.RefreshTokenModel{}, &repository.SessionModel{}, &repository.PasswordResetModel{}, &repository.EmailVerificationModel{}, &repository.EmailChangeModel{}, &repository.PhoneOTPModel{}, &repository.InvitationModel{}, &repository.TOTPFactorModel{}, &repository.MFAPolicyRoleModel{}, &repository.RecoveryCaseModel{}, &repository.PasskeyModel{}, &repository.WebAuthnChallengeModel{}, &repository.LoginFailureModel{}, &repository.RequestCounterModel{}, &repository.TokenRevocationModel{}, &repository.OAuthClientModel{}, &repository.AuthorizationCodeModel{}, &repository.ExternalIdentityModel{}, &repository.RecoveryEventModel{}, &repository.ReferralModel{}, &repository.UserReferralCodeModel{}); err != nil {
 			zapLogger.Fatal("failed to auto-migrate", zap.Error(err))
 		}
 		zapLogger.Info("database migration completed (dev auto-migrate)")
@@ -68,7 +73,7 @@ func main() {
 		}
 	}
 
-	// 5. Initialize JWT manager with durations parsed from config
+	// 5. Initialize token manager with signing keys and durations parsed from config
 	accessExpiry, err := time.ParseDuration(cfg.JWTConfig.AccessExpiry)
 	if err != nil {
 		accessExpiry = 15 * time.Minute
@@ -81,22 +86,126 @@ func main() {
 		zapLogger.Warn("invalid JWT_REFRESH_EXPIRY, using default 7d", zap.Error(err))
 	}
 
-	jwtSecret := cfg.JWTConfig.Secret
-	if jwtSecret == "" {
-		jwtSecret = "default-secret-change-me"
-		zapLogger.Warn("JWT_SECRET not set, using insecure default")
+	keysDir := cfg.SigningKeysDir
+	if keysDir == "" {
+		keysDir = "keys"
 	}
+	signingAlg := cfg.SigningAlgorithm
+	if signingAlg == "" {
+		signingAlg = tokens.AlgRS256
+	}
+
+	// Keys are generated on first start in development only; elsewhere they must be provisioned.
+	var keySet *tokens.KeySet
+	if cfg.AppEnv == "development" {
+		keySet, err = tokens.LoadOrGenerateKeySet(keysDir, cfg.ActiveKeyID, signingAlg)
+	} else {
+		keySet, err = tokens.LoadKeySet(keysDir, cfg.ActiveKeyID)
+	}
+	if err != nil {
+		zapLogger.Fatal("failed to load signing keys", zap.Error(err))
+	}
+	zapLogger.Info("signing keys loaded",
+		zap.String("active_kid", keySet.Active().ID()),
+		zap.String("alg", keySet.Active().Algorithm()),
+		zap.Int("total_keys", len(keySet.Keys())),
+	)
+
+	issuer := cfg.TokenIssuer
+	if issuer == "" {
+		issuer = "service-identity"
+	}
+
+	tokenManager := tokens.NewManager(keySet, issuer, accessExpiry, refreshExpiry)
+
+	// Every stored token digest is keyed with the pepper; a known one is for development only.
+	tokenPepper := cfg.TokenPepper
+	if tokenPepper == "" {
+		if cfg.AppEnv != "development" {
+			zapLogger.Fatal("TOKEN_PEPPER must be set outside development")
+		}
+		tokenPepper = "default-pepper-change-me"
+		zapLogger.Warn("TOKEN_PEPPER not set, using insecure development default")
+	}
+	tokenHasher := repository.NewTokenHasher(tokenPepper)
 
-	jwtManager := auth.NewJWTManager(jwtSecret, accessExpiry, refreshExpiry)
+	// Authenticator secrets are encrypted under a known key in development only.
+	mfaKey := cfg.MFAEncryptionKey
+	if mfaKey == "" {
+		if cfg.AppEnv != "development" {
+			zapLogger.Fatal("MFA_ENCRYPTION_KEY must be set outside development")
+		}
+		mfaKey = "default-mfa-key-change-me"
+		zapLogger.Warn("MFA_ENCRYPTION_KEY not set, using insecure development default")
+	}
+	secretBox, err := repository.NewSecretBox(mfaKey)
+	if err != nil {
+		zapLogger.Fatal("failed to initialize secret encryption", zap.Error(err))
+	}
 
 	// 6. Create repositories
 	userRepo := repository.NewGormUserRepository(db)
-	tokenRepo := repository.NewGormTokenRepository(db)
-	passwordResetRepo := repository.NewGormPasswordResetRepository(db)
+	tokenRepo := repository.NewGormTokenRepository(db, tokenHasher)
+	sessionRepo := repository.NewGormSessionRepository(db)
+	passwordResetRepo := repository.NewGormPasswordResetRepository(db, tokenHasher)
+	invitationRepo := repository.NewGormInvitationRepository(db, tokenHasher)
+	emailVerificationRepo := repository.NewGormEmailVerificationRepository(db, tokenHasher)
+	emailChangeRepo := repository.NewGormEmailChangeRepository(db, tokenHasher)
+	phoneOTPRepo := repository.NewGormPhoneOTPRepository(db)
+	totpFactorRepo := repository.NewGormTOTPFactorRepository(db, secretBox)
+	mfaPolicyRepo := repository.NewGormMFAPolicyRepository(db)
+	recoveryCaseRepo := repository.NewGormRecoveryCaseRepository(db, tokenHasher)
+	passkeyRepo := repository.NewGormPasskeyRepository(db)
+	webAuthnChallengeRepo := repository.NewGormWebAuthnChallengeRepository(db)
+	loginFailureRepo := repository.NewGormLoginFailureRepository(db)
+	requestCounterRepo := repository.NewGormRequestCounterRepository(db)
+	tokenRevocationRepo := repository.NewGormTokenRevocationRepository(db)
+	oauthClientRepo := repository.NewGormOAuthClientRepository(db, tokenHasher)
+	authorizationCodeRepo := repository.NewGormAuthorizationCodeRepository(db, tokenHasher)
+	externalIdentityRepo := repository.NewGormExternalIdentityRepository(db)
 
 	// 7. Create auth service
 	notifier := application.NewLogOnlyPasswordResetNotifier(zapLogger)
-	authService := application.NewAuthService(userRepo, tokenRepo, passwordResetRepo, notifier, jwtManager, zapLogger)
+	securityEvents := application.NewLogOnlySecurityEventPublisher(zapLogger)
+	emailVerificationNotifier := application.NewLogOnlyEmailVerificationNotifier(zapLogger)
+	emailVerificationService := application.NewEmailVerificationService(emailVerificationRepo, userRepo, emailVerificationNotifier, zapLogger)
+	verificationPolicy := application.NewEmailVerificationPolicy(cfg.EmailVerificationRequiredRoles)
+	registrationMode, err := application.ParseRegistrationMode(cfg.RegistrationMode)
+	if err != nil {
+		zapLogger.Fatal("invalid REGISTRATION_MODE", zap.Error(err))
+	}
+	smsSender := application.NewLogOnlySMSSender(zapLogger, cfg.AppEnv == "development")
+	otpService := application.NewOTPService(phoneOTPRepo, tokenHasher, smsSender, zapLogger)
+	mfaIssuer := cfg.MFAIssuer
+	if mfaIssuer == "" {
+		mfaIssuer = "Kilat Pet"
+	}
+	loginThrottle := application.NewLoginThrottle(loginFailureRepo, application.NewLogOnlyAccountLockedNotifier(zapLogger), securityEvents, zapLogger)
+	mfaService := application.NewMFAService(totpFactorRepo, mfaPolicyRepo, userRepo, loginThrottle, securityEvents, mfaIssuer, zapLogger)
+	relyingParty := newRelyingParty(cfg, mfaIssuer, zapLogger)
+	passkeyService := application.NewPasskeyService(passkeyRepo, webAuthnChallengeRepo, userRepo, relyingParty, securityEvents, zapLogger)
+	passwordHasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		zapLogger.Fatal("invalid password hashing configuration", zap.Error(err))
+	}
+	passwordPolicy, err := newPasswordPolicy(cfg, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("invalid password policy configuration", zap.Error(err))
+	}
+	// Revoked access tokens are rejected by every authenticated route; the list is kept
+	// in memory and picks up revocations made by other replicas in the background.
+	tokenRevocations := application.NewTokenRevocationList(tokenRevocationRepo, accessExpiry, zapLogger)
+	if err := tokenRevocations.Sync(context.Background()); err != nil {
+		zapLogger.Fatal("failed to load token revocations", zap.Error(err))
+	}
+	revocationCtx, stopRevocationSync := context.WithCancel(context.Background())
+	defer stopRevocationSync()
+	go tokenRevocations.Run(revocationCtx, application.DefaultTokenRevocationSyncInterval)
+	accessTokens := handler.WithRevocationCheck(tokenManager, tokenRevocations)
+
+	socialLoginService := application.NewSocialLoginService(newIdentityProviders(cfg, zapLogger), externalIdentityRepo, userRepo, passwordHasher, securityEvents, zapLogger)
+
+	authService := application.NewAuthService(userRepo, tokenRepo, sessionRepo, passwordResetRepo, requestCounterRepo, emailVerificationService, otpService, mfaService, passkeyService, socialLoginService, loginThrottle, passwordHasher, passwordPolicy, notifier, securityEvents, tokenManager, tokenRevocations, registrationMode, zapLogger)
 
 	// 8. Create Gin router with global middleware
 	gin.SetMode(gin.ReleaseMode)
@@ -114,6 +223,9 @@ func main() {
 	healthHandler := health.NewHandler(db, "service-identity")
 	healthHandler.RegisterRoutes(router)
 
+	jwksHandler := handler.NewJWKSHandler(tokenManager)
+	jwksHandler.RegisterRoutes(&router.RouterGroup)
+
 	// Initialize referral service and handler
 	referralRepo := repository.NewGormReferralRepository(db)
 	referralService := application.NewReferralService(referralRepo, zapLogger)
@@ -122,8 +234,35 @@ func main() {
 	// 10. Register auth handler routes
 	apiV1 := router.Group("/api/v1")
 	authHandler := handler.NewAuthHandler(authService, zapLogger)
-	authHandler.RegisterRoutes(apiV1, jwtManager)
-	referralHandler.RegisterRoutes(&router.RouterGroup, jwtManager)
+	authHandler.RegisterRoutes(apiV1, accessTokens)
+	sessionHandler := handler.NewSessionHandler(authService, zapLogger)
+	sessionHandler.RegisterRoutes(apiV1, accessTokens)
+	referralHandler.RegisterRoutes(&router.RouterGroup, accessTokens, verificationPolicy)
+
+	emailVerificationHandler := handler.NewEmailVerificationHandler(emailVerificationService, zapLogger)
+	emailVerificationHandler.RegisterRoutes(apiV1, accessTokens)
+
+	emailChangeNotifier := application.NewLogOnlyEmailChangeNotifier(zapLogger)
+	emailChangeService := application.NewEmailChangeService(emailChangeRepo, userRepo, requestCounterRepo, passwordHasher, loginThrottle, emailChangeNotifier, securityEvents, zapLogger)
+	emailChangeHandler := handler.NewEmailChangeHandler(emailChangeService, zapLogger)
+	emailChangeHandler.RegisterRoutes(apiV1, accessTokens)
+
+	phoneVerificationService := application.NewPhoneVerificationService(userRepo, otpService, zapLogger)
+	phoneVerificationHandler := handler.NewPhoneVerificationHandler(phoneVerificationService, zapLogger)
+	phoneVerificationHandler.RegisterRoutes(apiV1, accessTokens)
+
+	otpLoginHandler := handler.NewOTPLoginHandler(authService, zapLogger)
+	otpLoginHandler.RegisterRoutes(apiV1)
+
+	mfaHandler := handler.NewMFAHandler(mfaService, zapLogger)
+	mfaHandler.RegisterRoutes(&router.RouterGroup, accessTokens)
+
+	passkeyHandler := handler.NewPasskeyHandler(passkeyService, zapLogger)
+	passkeyHandler.RegisterRoutes(apiV1, accessTokens)
+	passkeyLoginHandler := handler.NewPasskeyLoginHandler(authService, zapLogger)
+	passkeyLoginHandler.RegisterRoutes(apiV1)
+	socialLoginHandler := handler.NewSocialLoginHandler(authService, zapLogger)
+	socialLoginHandler.RegisterRoutes(apiV1)
 
 	forgotPasswordHandler := handler.NewForgotPasswordHandler(authService, zapLogger)
 	forgotPasswordHandler.RegisterRoutes(apiV1)
@@ -138,7 +277,41 @@ func main() {
 
 	// Register admin handler routes
 	adminHandler := handler.NewAdminHandler(authService)
-	adminHandler.RegisterRoutes(&router.RouterGroup, jwtManager)
+	adminHandler.RegisterRoutes(&router.RouterGroup, accessTokens, mfaService)
+	accountStatusHandler := handler.NewAccountStatusHandler(authService, zapLogger)
+	accountStatusHandler.RegisterRoutes(&router.RouterGroup, accessTokens, mfaService)
+
+	invitationNotifier := application.NewLogOnlyInvitationNotifier(zapLogger)
+	invitationService := application.NewInvitationService(invitationRepo, userRepo, passwordHasher, passwordPolicy, invitationNotifier, zapLogger)
+	invitationHandler := handler.NewInvitationHandler(invitationService, zapLogger)
+	invitationHandler.RegisterRoutes(&router.RouterGroup, accessTokens, mfaService)
+
+	recoveryNotifier := application.NewLogOnlyRecoveryNotifier(zapLogger)
+	recoveryService := application.NewRecoveryService(recoveryCaseRepo, userRepo, passwordHasher, passwordPolicy, recoveryNotifier, securityEvents, tokenRevocations, zapLogger)
+	recoveryHandler := handler.NewRecoveryHandler(recoveryService, zapLogger)
+	recoveryHandler.RegisterRoutes(&router.RouterGroup, accessTokens, mfaService)
+
+	oauthClientService := application.NewOAuthClientService(oauthClientRepo, tokenManager, securityEvents, zapLogger)
+	// The authorization code grant is only served when the OpenID Connect provider is on.
+	var authorizationCodes handler.AuthorizationCodeExchanger
+	if oidcIssuer := newOIDCIssuer(cfg, zapLogger); oidcIssuer != "" {
+		oidcService := application.NewOIDCService(oauthClientRepo, authorizationCodeRepo, userRepo, authService, tokenManager, securityEvents, oidcIssuer, zapLogger)
+		oidcHandler := handler.NewOIDCHandler(oidcService, zapLogger)
+		oidcHandler.RegisterRoutes(&router.RouterGroup, accessTokens)
+		authorizationCodes = oidcService
+	}
+	oauthHandler := handler.NewOAuthHandler(oauthClientService, authorizationCodes, zapLogger)
+	oauthHandler.RegisterRoutes(&router.RouterGroup, accessTokens, mfaService)
+
+	introspectionCacheTTL := application.DefaultIntrospectionCacheTTL
+	if cfg.IntrospectionCacheTTL != "" {
+		if introspectionCacheTTL, err = time.ParseDuration(cfg.IntrospectionCacheTTL); err != nil {
+			zapLogger.Fatal("invalid INTROSPECTION_CACHE_TTL", zap.Error(err))
+		}
+	}
+	introspectionService := application.NewIntrospectionService(tokenManager, tokenRevocations, userRepo, sessionRepo, introspectionCacheTTL, zapLogger)
+	introspectionHandler := handler.NewIntrospectionHandler(introspectionService, zapLogger)
+	introspectionHandler.RegisterRoutes(apiV1, oauthClientService)
 
 	// 11. Start HTTP server
 	srv := &http.Server{
@@ -171,3 +344,99 @@ func main() {
 
 	zapLogger.Info("server stopped gracefully")
 }
+
+// newRelyingParty builds the passkey relying party from config. Outside development,
+// leaving WEBAUTHN_ORIGINS unset disables passkeys: no ceremony origin is accepted.
+func newRelyingParty(cfg *svcconfig.ServiceConfig, defaultName string, zapLogger *zap.Logger) *webauthn.RelyingParty {
+	rpID := cfg.WebAuthnRPID
+	if rpID == "" {
+		rpID = "localhost"
+		zapLogger.Warn("WEBAUTHN_RP_ID not set, using localhost")
+	}
+	rpName := cfg.WebAuthnRPName
+	if rpName == "" {
+		rpName = defaultName
+	}
+
+	origins := splitList(cfg.WebAuthnOrigins)
+	if len(origins) == 0 {
+		if cfg.AppEnv == "development" {
+			origins = []string{"http://localhost:3000"}
+		} else {
+			zapLogger.Warn("WEBAUTHN_ORIGINS not set, passkey registration and sign-in will fail")
+		}
+	}
+	return webauthn.NewRelyingParty(rpID, rpName, origins)
+}
+
+// newIdentityProviders returns the external identity providers users can sign in
+// with: each provider is on once the client IDs of our apps there are configured.
+func newIdentityProviders(cfg *svcconfig.ServiceConfig, zapLogger *zap.Logger) []application.IdentityProvider {
+	var providers []application.IdentityProvider
+	if clientIDs := splitList(cfg.GoogleClientIDs); len(clientIDs) > 0 {
+		providers = append(providers, federation.Google(clientIDs))
+	}
+	if clientIDs := splitList(cfg.AppleClientIDs); len(clientIDs) > 0 {
+		providers = append(providers, federation.Apple(clientIDs))
+	}
+	if len(providers) == 0 {
+		zapLogger.Info("no GOOGLE_CLIENT_IDS or APPLE_CLIENT_IDS configured, social sign-in is disabled")
+	}
+	return providers
+}
+
+// splitList splits a comma-separated config value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// newOIDCIssuer returns the OpenID Connect issuer from config. Outside development,
+// leaving OIDC_ISSUER unset disables the provider and the authorization code grant.
+func newOIDCIssuer(cfg *svcconfig.ServiceConfig, zapLogger *zap.Logger) string {
+	if cfg.OIDCIssuer != "" {
+		return cfg.OIDCIssuer
+	}
+	if cfg.AppEnv == "development" {
+		return "http://localhost" + cfg.Port
+	}
+	zapLogger.Warn("OIDC_ISSUER not set, OpenID Connect sign-in is disabled")
+	return ""
+}
+
+// newPasswordHasher builds the hasher for new password hashes from configuration.
+// Existing hashes of either algorithm keep verifying whichever one is chosen.
+func newPasswordHasher(cfg *svcconfig.ServiceConfig) (application.PasswordHasher, error) {
+	switch cfg.PasswordHashAlgorithm {
+	case "", passwords.AlgorithmBcrypt:
+		return passwords.NewBcryptHasher(cfg.BcryptCost)
+	case passwords.AlgorithmArgon2id:
+		return passwords.NewArgon2idHasher(passwords.Argon2idParams{
+			Memory:      uint32(cfg.Argon2idMemoryKiB),
+			Iterations:  uint32(cfg.Argon2idIterations),
+			Parallelism: uint8(cfg.Argon2idParallelism),
+		})
+	default:
+		return nil, fmt.Errorf("unknown PASSWORD_HASH_ALGORITHM %q", cfg.PasswordHashAlgorithm)
+	}
+}
+
+// newPasswordPolicy builds the password policy, loading the breached-password list
+// if one is configured.
+func newPasswordPolicy(cfg *svcconfig.ServiceConfig, zapLogger *zap.Logger) (*passwords.Policy, error) {
+	var breached *passwords.BreachedList
+	if cfg.BreachedPasswordsFile != "" {
+		list, err := passwords.LoadBreachedList(cfg.BreachedPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+		zapLogger.Info("breached password list loaded", zap.Int("entries", list.Len()))
+		breached = list
+	}
+	return passwords.NewPolicy(cfg.PasswordMinLength, breached)
+}
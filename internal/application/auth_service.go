@@ -4,16 +4,19 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/Kilat-Pet-Delivery/lib-common/auth"
 	"github.com/Kilat-Pet-Delivery/lib-common/domain"
 	"github.com/Kilat-Pet-Delivery/lib-proto/dto"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // RegisterRequest represents a user registration request.
@@ -21,8 +24,41 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Phone    string `json:"phone"`
 	FullName string `json:"full_name" binding:"required"`
-	Password string `json:"password" binding:"required,min=8"`
-	Role     string `json:"role" binding:"required,oneof=owner runner admin shop"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=owner shop"`
+}
+
+// publicRoles are the roles anyone may self-register with. Runners join through
+// runner applications and admins only through invitations.
+var publicRoles = map[auth.UserRole]bool{
+	auth.RoleOwner:        true,
+	auth.UserRole("shop"): true,
+}
+
+// RegistrationMode controls what a sign-up with an email that already has an account
+// tells the caller.
+type RegistrationMode string
+
+const (
+	// RegistrationModeImmediate signs new users in at once and rejects taken emails
+	// with a conflict error.
+	RegistrationModeImmediate RegistrationMode = "immediate"
+	// RegistrationModeVerifyFirst answers every sign-up the same way and signs no one
+	// in: new users verify their email and then log in, and the owner of a taken
+	// email is written to instead, so the response does not reveal who has an account.
+	RegistrationModeVerifyFirst RegistrationMode = "verify_first"
+)
+
+// ParseRegistrationMode parses a REGISTRATION_MODE value. Empty means immediate.
+func ParseRegistrationMode(value string) (RegistrationMode, error) {
+	switch mode := RegistrationMode(value); mode {
+	case "":
+		return RegistrationModeImmediate, nil
+	case RegistrationModeImmediate, RegistrationModeVerifyFirst:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown registration mode %q", value)
+	}
 }
 
 // LoginRequest represents a login request.
@@ -31,11 +67,75 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// AuthResponse represents the response for authentication operations.
+// OTPLoginRequest represents a request for a passwordless sign-in code.
+type OTPLoginRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// OTPVerifyRequest represents a passwordless sign-in with a phone number and SMS code.
+type OTPVerifyRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// MFAVerifyRequest completes a sign-in that requires a second factor.
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// AuthResponse represents the response for authentication operations. When the user
+// has two-factor authentication enabled, the first step of a sign-in returns only
+// MFARequired and a short-lived MFAToken to present with the code.
 type AuthResponse struct {
-	AccessToken  string      `json:"access_token"`
-	RefreshToken string      `json:"refresh_token"`
-	User         dto.UserDTO `json:"user"`
+	AccessToken  string   `json:"access_token,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+	User         *UserDTO `json:"user,omitempty"`
+	MFARequired  bool     `json:"mfa_required,omitempty"`
+	MFAToken     string   `json:"mfa_token,omitempty"`
+	// MFAEnrollmentRequired tells users whose role requires two-factor authentication
+	// to enroll an authenticator app; until they do, gated endpoints refuse them.
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
+	// VerificationRequired is set instead of tokens when a verify-first registration
+	// was accepted; the user has to confirm their email and then log in.
+	VerificationRequired bool `json:"verification_required,omitempty"`
+	// SessionID is the session the tokens belong to. It is not sent to clients, who
+	// find it in the access token's "sid" claim.
+	SessionID uuid.UUID `json:"-"`
+}
+
+// Authentication is the outcome of a sign-in that does not start a session by itself,
+// as when a user signs in to authorize an OpenID Connect client: the authenticated
+// user, or an MFA challenge to complete first.
+type Authentication struct {
+	UserID      uuid.UUID
+	AuthMethods []string
+	AuthTime    time.Time
+	MFARequired bool
+	MFAToken    string
+}
+
+// UserDTO extends the shared user representation with identity-specific account state.
+type UserDTO struct {
+	dto.UserDTO
+	PhoneVerified  bool       `json:"phone_verified"`
+	Status         string     `json:"status"`
+	StatusReason   string     `json:"status_reason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+}
+
+// LogoutRequest represents a logout request. By default only the current session is
+// ended; AllDevices ends every session of the user.
+type LogoutRequest struct {
+	AllDevices bool `json:"all_devices"`
+}
+
+// ClientInfo describes the device and network an authentication request comes from.
+type ClientInfo struct {
+	DeviceName string
+	Platform   string
+	UserAgent  string
+	IPAddress  string
 }
 
 // UpdateProfileRequest represents a profile update request.
@@ -45,53 +145,145 @@ type UpdateProfileRequest struct {
 	AvatarURL string `json:"avatar_url"`
 }
 
+// ChangePasswordRequest represents a signed-in user's request to change their password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// BanUserRequest represents an admin request to ban a user.
+type BanUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+// SuspendUserRequest represents an admin request to suspend a user. A nil Until
+// suspends the account until it is explicitly reinstated.
+type SuspendUserRequest struct {
+	Reason string     `json:"reason" binding:"required"`
+	Until  *time.Time `json:"until"`
+}
+
+// SessionDTO represents a signed-in device in API responses.
+type SessionDTO struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	Platform   string    `json:"platform"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
 // AuthService implements authentication and user management use cases.
 type AuthService struct {
 	userRepo          identity.UserRepository
 	tokenRepo         identity.TokenRepository
+	sessionRepo       identity.SessionRepository
 	passwordResetRepo identity.PasswordResetRepository
+	requestCounters   identity.RequestCounterRepository
+	verifications     *EmailVerificationService
+	otp               *OTPService
+	mfa               *MFAService
+	passkeys          *PasskeyService
+	social            *SocialLoginService
+	throttle          *LoginThrottle
+	hasher            PasswordHasher
+	passwordPolicy    PasswordPolicy
 	notifier          PasswordResetNotifier
-	jwt               *auth.JWTManager
+	events            SecurityEventPublisher
+	tokens            *tokens.Manager
+	revocations       *TokenRevocationList
+	registrationMode  RegistrationMode
 	logger            *zap.Logger
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
 // NewAuthService creates a new AuthService.
 func NewAuthService(
 	userRepo identity.UserRepository,
 	tokenRepo identity.TokenRepository,
+	sessionRepo identity.SessionRepository,
 	passwordResetRepo identity.PasswordResetRepository,
+	requestCounters identity.RequestCounterRepository,
+	verifications *EmailVerificationService,
+	otp *OTPService,
+	mfa *MFAService,
+	passkeys *PasskeyService,
+	social *SocialLoginService,
+	throttle *LoginThrottle,
+	hasher PasswordHasher,
+	passwordPolicy PasswordPolicy,
 	notifier PasswordResetNotifier,
-	jwt *auth.JWTManager,
+	events SecurityEventPublisher,
+	tokenManager *tokens.Manager,
+	revocations *TokenRevocationList,
+	registrationMode RegistrationMode,
 	logger *zap.Logger,
 ) *AuthService {
 	return &AuthService{
 		userRepo:          userRepo,
 		tokenRepo:         tokenRepo,
+		sessionRepo:       sessionRepo,
 		passwordResetRepo: passwordResetRepo,
+		requestCounters:   requestCounters,
+		verifications:     verifications,
+		otp:               otp,
+		mfa:               mfa,
+		passkeys:          passkeys,
+		social:            social,
+		throttle:          throttle,
+		hasher:            hasher,
+		passwordPolicy:    passwordPolicy,
 		notifier:          notifier,
-		jwt:               jwt,
+		events:            events,
+		tokens:            tokenManager,
+		revocations:       revocations,
+		registrationMode:  registrationMode,
 		logger:            logger,
 	}
 }
 
-// Register creates a new user account and returns authentication tokens.
-func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*AuthResponse, error) {
-	// Check if email is already taken
-	existing, _ := s.userRepo.FindByEmail(ctx, req.Email)
-	if existing != nil {
-		return nil, domain.NewAlreadyExistsError("User", "email", req.Email)
+// Register creates a new user account and returns authentication tokens. In
+// verify-first mode it signs no one in and answers a taken email exactly like a new
+// one, writing to the address's owner instead; see RegistrationModeVerifyFirst.
+func (s *AuthService) Register(ctx context.Context, req RegisterRequest, client ClientInfo) (*AuthResponse, error) {
+	role := auth.UserRole(req.Role)
+	if !publicRoles[role] {
+		return nil, domain.NewValidationError(fmt.Sprintf("role %q cannot self-register", req.Role))
+	}
+	email, err := identity.NewEmail(req.Email)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	if err := checkPassword(s.passwordPolicy, req.Password, email.String(), req.FullName); err != nil {
+		return nil, err
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// Hash password before the lookup so a taken email takes as long as a new one.
+	hashedPassword, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		s.logger.Error("failed to hash password", zap.Error(err))
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
+	// Check if email is already taken
+	existing, _ := s.userRepo.FindByEmail(ctx, email)
+	if existing != nil {
+		if s.registrationMode != RegistrationModeVerifyFirst {
+			return nil, domain.NewAlreadyExistsError("User", "email", email.String())
+		}
+		if err := s.verifications.SendRegistrationAttempt(ctx, existing); err != nil {
+			s.logger.Warn("failed to notify owner of registration attempt", zap.Error(err), zap.String("user_id", existing.ID().String()))
+		}
+		s.logger.Info("registration attempted with existing email", zap.String("user_id", existing.ID().String()))
+		return &AuthResponse{VerificationRequired: true}, nil
+	}
+
 	// Create domain user
-	role := auth.UserRole(req.Role)
-	user, err := identity.NewUser(req.Email, req.Phone, req.FullName, string(hashedPassword), role)
+	user, err := identity.NewUser(email.String(), req.Phone, req.FullName, hashedPassword, role)
 	if err != nil {
 		return nil, domain.NewValidationError(err.Error())
 	}
@@ -101,145 +293,676 @@ func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*AuthR
 		s.logger.Error("failed to save user", zap.Error(err))
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
+	s.flagSharedMailbox(ctx, user)
 
-	// Generate tokens
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID(), user.Email(), user.Role())
+	if err := s.verifications.SendVerification(ctx, user); err != nil {
+		// The user can request another email; registration itself succeeded.
+		s.logger.Warn("failed to send verification email", zap.Error(err), zap.String("user_id", user.ID().String()))
+	}
+
+	if s.registrationMode == RegistrationModeVerifyFirst {
+		s.logger.Info("user registered, awaiting email verification", zap.String("user_id", user.ID().String()))
+		return &AuthResponse{VerificationRequired: true}, nil
+	}
+
+	resp, err := s.signIn(ctx, user, client, []string{tokens.AMRPassword})
 	if err != nil {
-		s.logger.Error("failed to generate access token", zap.Error(err))
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+		return nil, err
 	}
 
-	refreshTokenStr, err := s.jwt.GenerateRefreshToken(user.ID())
+	s.logger.Info("user registered", zap.String("user_id", user.ID().String()), zap.String("email", user.Email()))
+	return resp, nil
+}
+
+// Login authenticates a user by email and password and starts a new session, or
+// returns an MFA challenge when the user has two-factor authentication enabled.
+// Repeated failures are throttled and eventually lock the account for a while.
+func (s *AuthService) Login(ctx context.Context, req LoginRequest, client ClientInfo) (*AuthResponse, error) {
+	user, err := s.checkPassword(ctx, req, client)
 	if err != nil {
-		s.logger.Error("failed to generate refresh token", zap.Error(err))
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, err
 	}
 
-	// Store refresh token
-	refreshToken := identity.NewRefreshToken(user.ID(), refreshTokenStr, time.Now().Add(7*24*time.Hour))
-	if err := s.tokenRepo.Save(ctx, refreshToken); err != nil {
-		s.logger.Error("failed to save refresh token", zap.Error(err))
-		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	resp, err := s.signIn(ctx, user, client, []string{tokens.AMRPassword})
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("user registered", zap.String("user_id", user.ID().String()), zap.String("email", user.Email()))
+	s.logger.Info("user logged in", zap.String("user_id", user.ID().String()), zap.String("email", user.Email()), zap.Bool("mfa_required", resp.MFARequired))
+	return resp, nil
+}
 
-	return &AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshTokenStr,
-		User:         toUserDTO(user),
-	}, nil
+// Authenticate checks a user's email and password like Login but starts no session.
+// It returns the authenticated user, or an MFA challenge to complete with
+// AuthenticateMFA when the user has two-factor authentication enabled.
+func (s *AuthService) Authenticate(ctx context.Context, req LoginRequest, client ClientInfo) (*Authentication, error) {
+	user, err := s.checkPassword(ctx, req, client)
+	if err != nil {
+		return nil, err
+	}
+	return s.authenticated(ctx, user, []string{tokens.AMRPassword})
 }
 
-// Login authenticates a user by email and password.
-func (s *AuthService) Login(ctx context.Context, req LoginRequest) (*AuthResponse, error) {
-	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+// AuthenticateMFA completes an Authenticate challenge with an authenticator app code.
+func (s *AuthService) AuthenticateMFA(ctx context.Context, req MFAVerifyRequest, client ClientInfo) (*Authentication, error) {
+	user, methods, err := s.checkMFA(ctx, req, client)
 	if err != nil {
+		return nil, err
+	}
+	return s.authenticated(ctx, user, methods)
+}
+
+// authenticated finishes an Authenticate or AuthenticateMFA step like signIn does,
+// without starting a session.
+func (s *AuthService) authenticated(ctx context.Context, user *identity.User, authMethods []string) (*Authentication, error) {
+	if err := checkCanAuthenticate(user); err != nil {
+		return nil, err
+	}
+	challenge, err := s.mfaChallenge(ctx, user, authMethods)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != "" {
+		return &Authentication{MFARequired: true, MFAToken: challenge}, nil
+	}
+	s.throttle.RecordSuccess(ctx, user.Email())
+	return &Authentication{UserID: user.ID(), AuthMethods: authMethods, AuthTime: time.Now().UTC()}, nil
+}
+
+// StartAuthorizedSession starts a session for a user who authenticated earlier with
+// Authenticate, as when an OpenID Connect client redeems its authorization code.
+func (s *AuthService) StartAuthorizedSession(ctx context.Context, userID uuid.UUID, authMethods []string, client ClientInfo) (*AuthResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("user no longer exists")
+	}
+	return s.startSession(ctx, user, client, authMethods)
+}
+
+// checkPassword authenticates a user by email and password. Repeated failures are
+// throttled and eventually lock the account for a while.
+func (s *AuthService) checkPassword(ctx context.Context, req LoginRequest, client ClientInfo) (*identity.User, error) {
+	if err := s.throttle.Check(ctx, req.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := s.findUserByEmail(ctx, req.Email)
+	if err != nil {
+		// Spend the same hashing work as a wrong password so response times do not
+		// reveal whether the email has an account.
+		_, _ = s.hasher.Verify(s.dummyPasswordHash(), req.Password)
+		s.throttle.RecordFailure(ctx, req.Email, client.IPAddress, nil)
 		return nil, domain.NewUnauthorizedError("invalid email or password")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash()), []byte(req.Password)); err != nil {
+	match, err := s.hasher.Verify(user.PasswordHash(), req.Password)
+	if err != nil {
+		s.logger.Error("failed to verify password hash", zap.Error(err), zap.String("user_id", user.ID().String()))
+	}
+	if !match {
+		s.throttle.RecordFailure(ctx, req.Email, client.IPAddress, user)
 		return nil, domain.NewUnauthorizedError("invalid email or password")
 	}
+	s.rehashPassword(ctx, user, req.Password)
+	return user, nil
+}
+
+// findUserByEmail looks up the user with a submitted address. Input that is not a
+// valid email matches no one.
+func (s *AuthService) findUserByEmail(ctx context.Context, raw string) (*identity.User, error) {
+	email, err := identity.NewEmail(raw)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	return s.userRepo.FindByEmail(ctx, email)
+}
+
+// flagSharedMailbox logs a warning when a new account's address has the same
+// canonical form as existing ones, e.g. "a.b+promo@gmail.com" and "ab@gmail.com",
+// which often means one person farming sign-up or referral rewards.
+func (s *AuthService) flagSharedMailbox(ctx context.Context, user *identity.User) {
+	accounts, err := s.userRepo.CountByCanonicalEmail(ctx, user.EmailVO())
+	if err != nil {
+		s.logger.Warn("failed to count accounts sharing a canonical email", zap.Error(err), zap.String("user_id", user.ID().String()))
+		return
+	}
+	if accounts > 1 {
+		s.logger.Warn("new account shares a canonical email with existing accounts",
+			zap.String("user_id", user.ID().String()),
+			zap.String("canonical_email", user.EmailVO().Canonical()),
+			zap.Int64("accounts", accounts),
+		)
+	}
+}
+
+// dummyPasswordHash returns a hash, made with the configured hasher, that unknown
+// emails are checked against. It is created on first use.
+func (s *AuthService) dummyPasswordHash() string {
+	s.dummyHashOnce.Do(func() {
+		hash, err := s.hasher.Hash("kilat-dummy-password")
+		if err != nil {
+			s.logger.Error("failed to create dummy password hash", zap.Error(err))
+		}
+		s.dummyHash = hash
+	})
+	return s.dummyHash
+}
+
+// rehashPassword replaces a password hash that just verified when it was made with an
+// older algorithm or weaker parameters than the configured hasher uses. Failures are
+// logged; the old hash keeps working and the upgrade is retried at the next sign-in.
+func (s *AuthService) rehashPassword(ctx context.Context, user *identity.User, password string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash()) {
+		return
+	}
+
+	newHash, err := s.hasher.Hash(password)
+	if err != nil {
+		s.logger.Error("failed to rehash password", zap.Error(err), zap.String("user_id", user.ID().String()))
+		return
+	}
+	if err := s.userRepo.ReplacePasswordHash(ctx, user.ID(), user.PasswordHash(), newHash); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.Error("failed to store rehashed password", zap.Error(err), zap.String("user_id", user.ID().String()))
+		}
+		return
+	}
+	user.ChangePassword(newHash)
+	s.logger.Info("password rehashed", zap.String("user_id", user.ID().String()))
+}
+
+// VerifyMFA completes a sign-in with the challenge token from the first step and a
+// code from the user's authenticator app, and starts a new session.
+func (s *AuthService) VerifyMFA(ctx context.Context, req MFAVerifyRequest, client ClientInfo) (*AuthResponse, error) {
+	user, methods, err := s.checkMFA(ctx, req, client)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.startSession(ctx, user, client, methods)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user completed two-factor sign-in", zap.String("user_id", user.ID().String()))
+	return resp, nil
+}
+
+// checkMFA verifies an MFA challenge token and authenticator app code, and returns the
+// user with every method the sign-in used.
+func (s *AuthService) checkMFA(ctx context.Context, req MFAVerifyRequest, client ClientInfo) (*identity.User, []string, error) {
+	challenge, err := s.tokens.ValidateMFAChallenge(req.MFAToken)
+	if err != nil {
+		return nil, nil, domain.NewUnauthorizedError("invalid or expired two-factor challenge")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, domain.NewUnauthorizedError("invalid or expired two-factor challenge")
+	}
+	if err := checkCanAuthenticate(user); err != nil {
+		return nil, nil, err
+	}
+	if err := s.throttle.Check(ctx, user.Email(), client.IPAddress); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.mfa.VerifyCode(ctx, user.ID(), req.Code); err != nil {
+		s.logger.Info("two-factor code rejected", zap.String("user_id", user.ID().String()))
+		s.throttle.RecordFailure(ctx, user.Email(), client.IPAddress, user)
+		return nil, nil, err
+	}
+
+	methods := append(append([]string{}, challenge.AuthMethods...), tokens.AMROTP, tokens.AMRMFA)
+	return user, methods, nil
+}
+
+// loginOTPIPLimit caps sign-in code requests from one client address, whatever the
+// numbers, so one client cannot spread guesses or SMS costs across many numbers.
+var loginOTPIPLimit = requestLimit{scope: "login_otp_ip", limit: 20, window: time.Hour, message: "too many sign-in code requests"}
+
+// RequestLoginOTP texts a sign-in code to a verified phone number. Unknown, unverified
+// and blocked numbers get an undelivered decoy code instead, so the response and the
+// throttling are identical whether or not the number belongs to an account.
+func (s *AuthService) RequestLoginOTP(ctx context.Context, req OTPLoginRequest, client ClientInfo) error {
+	phone, err := identity.NewPhone(req.Phone)
+	if err != nil || phone.IsEmpty() {
+		return domain.NewValidationError("invalid phone number")
+	}
+
+	if client.IPAddress != "" {
+		if err := checkRequestLimit(ctx, s.requestCounters, loginOTPIPLimit, client.IPAddress); err != nil {
+			return err
+		}
+	}
+
+	user, _ := s.userRepo.FindByVerifiedPhone(ctx, phone.String())
+	if user == nil || user.CanAuthenticate() != nil {
+		return s.otp.SendDecoy(ctx, phone.String(), identity.OTPPurposeLogin)
+	}
+	if err := s.throttle.Check(ctx, user.Email(), client.IPAddress); err != nil {
+		return err
+	}
+
+	userID := user.ID()
+	if err := s.otp.Send(ctx, phone.String(), identity.OTPPurposeLogin, &userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("login code sent", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// LoginWithOTP signs a user in with a code sent to their verified phone number and
+// starts a new session or returns an MFA challenge, exactly like Login. Wrong codes
+// count as failed sign-ins of the number's account, so a locked account cannot be
+// entered with a code either.
+func (s *AuthService) LoginWithOTP(ctx context.Context, req OTPVerifyRequest, client ClientInfo) (*AuthResponse, error) {
+	phone, err := identity.NewPhone(req.Phone)
+	if err != nil || phone.IsEmpty() {
+		return nil, domain.NewValidationError("invalid phone number")
+	}
+
+	user, _ := s.userRepo.FindByVerifiedPhone(ctx, phone.String())
+	if user != nil {
+		if err := s.throttle.Check(ctx, user.Email(), client.IPAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	otp, err := s.otp.Redeem(ctx, phone.String(), identity.OTPPurposeLogin, req.Code)
+	if err != nil {
+		if user != nil {
+			s.throttle.RecordFailure(ctx, user.Email(), client.IPAddress, user)
+		}
+		return nil, err
+	}
+	if otp.UserID() == nil || user == nil || *otp.UserID() != user.ID() {
+		// The code was a decoy, or the number was changed or unverified after it was sent.
+		return nil, domain.NewUnauthorizedError("invalid or expired code")
+	}
+
+	resp, err := s.signIn(ctx, user, client, []string{tokens.AMRSMS})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user logged in with phone code", zap.String("user_id", user.ID().String()))
+	return resp, nil
+}
+
+// BeginPasskeyLogin issues the options for a passkey sign-in.
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context) (*PasskeyLoginOptionsDTO, error) {
+	return s.passkeys.BeginLogin(ctx)
+}
+
+// LoginWithPasskey signs a user in with a passkey and starts a new session. Passkeys
+// require user verification (a PIN or biometric on the authenticator), so the sign-in
+// already has two factors and no authenticator app code is asked for.
+func (s *AuthService) LoginWithPasskey(ctx context.Context, req PasskeyLoginRequest, client ClientInfo) (*AuthResponse, error) {
+	user, err := s.passkeys.Authenticate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.signIn(ctx, user, client, []string{tokens.AMRHardwareKey, tokens.AMRMFA})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user logged in with passkey", zap.String("user_id", user.ID().String()))
+	return resp, nil
+}
+
+// LoginWithProvider signs a user in with an ID token from an external identity
+// provider such as Google or Apple, creating or linking their account on first use,
+// and starts a new session or returns an MFA challenge exactly like Login.
+func (s *AuthService) LoginWithProvider(ctx context.Context, provider string, req SocialLoginRequest, client ClientInfo) (*AuthResponse, error) {
+	user, err := s.social.Authenticate(ctx, provider, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.signIn(ctx, user, client, []string{tokens.AMRFederated})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user logged in with external identity", zap.String("user_id", user.ID().String()), zap.String("provider", provider))
+	return resp, nil
+}
+
+// signIn finishes an authentication with the given methods. Single-factor sign-ins of
+// users with an authenticator app get an MFA challenge; everyone else gets a new session.
+func (s *AuthService) signIn(ctx context.Context, user *identity.User, client ClientInfo, authMethods []string) (*AuthResponse, error) {
+	if err := checkCanAuthenticate(user); err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user, authMethods)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != "" {
+		return &AuthResponse{MFARequired: true, MFAToken: challenge}, nil
+	}
+
+	resp, err := s.startSession(ctx, user, client, authMethods)
+	if err != nil {
+		return nil, err
+	}
+	resp.MFAEnrollmentRequired = !slices.Contains(authMethods, tokens.AMRMFA) && s.mfa.RequiresMFA(ctx, user.Role())
+	return resp, nil
+}
+
+// mfaChallenge returns a challenge token when a single-factor sign-in of a user with
+// an authenticator app has to be completed with a code, or empty when it need not.
+func (s *AuthService) mfaChallenge(ctx context.Context, user *identity.User, authMethods []string) (string, error) {
+	if slices.Contains(authMethods, tokens.AMRMFA) {
+		return "", nil
+	}
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, user.ID())
+	if err != nil {
+		return "", err
+	}
+	if !mfaEnabled {
+		return "", nil
+	}
+	challenge, err := s.tokens.GenerateMFAChallenge(user.ID(), authMethods)
+	if err != nil {
+		s.logger.Error("failed to generate mfa challenge", zap.Error(err))
+		return "", fmt.Errorf("failed to generate mfa challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// startSession creates a session for the user's device and issues the first token pair of its family.
+func (s *AuthService) startSession(ctx context.Context, user *identity.User, client ClientInfo, authMethods []string) (*AuthResponse, error) {
+	if err := checkCanAuthenticate(user); err != nil {
+		return nil, err
+	}
+
+	session := identity.NewSession(user.ID(), client.DeviceName, client.Platform, client.UserAgent, client.IPAddress, authMethods)
+	if err := s.sessionRepo.Save(ctx, session); err != nil {
+		s.logger.Error("failed to save session", zap.Error(err))
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
 
 	// Generate tokens
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID(), user.Email(), user.Role())
+	accessToken, err := s.tokens.GenerateAccessToken(accessSubject(user, session))
 	if err != nil {
 		s.logger.Error("failed to generate access token", zap.Error(err))
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshTokenStr, err := s.jwt.GenerateRefreshToken(user.ID())
+	refreshTokenStr, refreshExpiresAt, err := s.tokens.GenerateRefreshToken()
 	if err != nil {
 		s.logger.Error("failed to generate refresh token", zap.Error(err))
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// Store refresh token
-	refreshToken := identity.NewRefreshToken(user.ID(), refreshTokenStr, time.Now().Add(7*24*time.Hour))
+	// Store refresh token as the first member of the session's family
+	refreshToken := identity.NewRefreshToken(user.ID(), session.ID(), refreshTokenStr, refreshExpiresAt)
 	if err := s.tokenRepo.Save(ctx, refreshToken); err != nil {
 		s.logger.Error("failed to save refresh token", zap.Error(err))
 		return nil, fmt.Errorf("failed to save refresh token: %w", err)
 	}
+	s.throttle.RecordSuccess(ctx, user.Email())
 
-	s.logger.Info("user logged in", zap.String("user_id", user.ID().String()), zap.String("email", user.Email()))
-
+	userDTO := toUserDTO(user)
 	return &AuthResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshTokenStr,
-		User:         toUserDTO(user),
+		User:         &userDTO,
+		SessionID:    session.ID(),
 	}, nil
 }
 
 // RefreshToken validates a refresh token and issues a new token pair.
-func (s *AuthService) RefreshToken(ctx context.Context, token string) (*AuthResponse, error) {
-	// Validate the JWT signature of the refresh token
-	claims, err := s.jwt.ValidateToken(token)
+// The presented token is rotated: it is marked used and its successor joins the same family.
+// Presenting a token that was already rotated revokes the whole family.
+func (s *AuthService) RefreshToken(ctx context.Context, token string, client ClientInfo) (*AuthResponse, error) {
+	// Lookup stored refresh token
+	storedToken, err := s.tokenRepo.FindByToken(ctx, token)
 	if err != nil {
 		return nil, domain.NewUnauthorizedError("invalid refresh token")
 	}
 
-	// Lookup stored refresh token
-	storedToken, err := s.tokenRepo.FindByToken(ctx, token)
-	if err != nil {
-		return nil, domain.NewUnauthorizedError("refresh token not found")
+	if storedToken.IsUsed() {
+		s.handleRefreshTokenReuse(ctx, storedToken)
+		return nil, domain.NewUnauthorizedError("refresh token is expired or revoked")
 	}
 
 	if !storedToken.IsValid() {
 		return nil, domain.NewUnauthorizedError("refresh token is expired or revoked")
 	}
 
-	// Revoke the old token
-	storedToken.Revoke()
-
 	// Find the user
-	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	user, err := s.userRepo.FindByID(ctx, storedToken.UserID())
 	if err != nil {
-		return nil, domain.NewNotFoundError("User", claims.UserID.String())
+		return nil, domain.NewNotFoundError("User", storedToken.UserID().String())
+	}
+
+	if err := checkCanAuthenticate(user); err != nil {
+		s.logger.Info("token refresh rejected", zap.String("user_id", user.ID().String()), zap.Error(err))
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, storedToken.FamilyID())
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("invalid refresh token")
 	}
 
 	// Generate new token pair
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID(), user.Email(), user.Role())
+	accessToken, err := s.tokens.GenerateAccessToken(accessSubject(user, session))
 	if err != nil {
 		s.logger.Error("failed to generate access token", zap.Error(err))
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshTokenStr, err := s.jwt.GenerateRefreshToken(user.ID())
+	refreshTokenStr, refreshExpiresAt, err := s.tokens.GenerateRefreshToken()
 	if err != nil {
 		s.logger.Error("failed to generate refresh token", zap.Error(err))
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// Store new refresh token
-	newRefreshToken := identity.NewRefreshToken(user.ID(), refreshTokenStr, time.Now().Add(7*24*time.Hour))
-	if err := s.tokenRepo.Save(ctx, newRefreshToken); err != nil {
-		s.logger.Error("failed to save refresh token", zap.Error(err))
-		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	// Consume the old token and store its successor in the same family
+	newRefreshToken := storedToken.Rotate(refreshTokenStr, refreshExpiresAt)
+	if err := s.tokenRepo.Rotate(ctx, storedToken.ID(), newRefreshToken); err != nil {
+		if errors.Is(err, identity.ErrRefreshTokenReused) {
+			// Another request rotated this token first.
+			s.handleRefreshTokenReuse(ctx, storedToken)
+			return nil, domain.NewUnauthorizedError("refresh token is expired or revoked")
+		}
+		if errors.Is(err, identity.ErrRefreshTokenRevoked) || errors.Is(err, domain.ErrNotFound) {
+			// A logout or session revoke landed after the lookup.
+			return nil, domain.NewUnauthorizedError("refresh token is expired or revoked")
+		}
+		s.logger.Error("failed to rotate refresh token", zap.Error(err))
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	if err := s.sessionRepo.Touch(ctx, storedToken.FamilyID(), client.UserAgent, client.IPAddress); err != nil {
+		s.logger.Warn("failed to update session last-seen", zap.Error(err), zap.String("session_id", storedToken.FamilyID().String()))
 	}
 
 	s.logger.Info("token refreshed", zap.String("user_id", user.ID().String()))
 
+	userDTO := toUserDTO(user)
 	return &AuthResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshTokenStr,
-		User:         toUserDTO(user),
+		User:         &userDTO,
 	}, nil
 }
 
-// Logout revokes all refresh tokens for the specified user.
-func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID) error {
-	if err := s.tokenRepo.RevokeAllForUser(ctx, userID); err != nil {
-		s.logger.Error("failed to revoke tokens", zap.Error(err), zap.String("user_id", userID.String()))
-		return fmt.Errorf("failed to revoke tokens: %w", err)
+// handleRefreshTokenReuse ends the session that owns a replayed refresh token, which
+// revokes its entire token family, and emits a security event. Errors are logged rather
+// than returned because the caller rejects the request regardless.
+func (s *AuthService) handleRefreshTokenReuse(ctx context.Context, token *identity.RefreshToken) {
+	if err := s.sessionRepo.Revoke(ctx, token.FamilyID()); err != nil {
+		s.logger.Error("failed to revoke refresh token family", zap.Error(err), zap.String("family_id", token.FamilyID().String()))
+	}
+
+	event := NewSecurityEvent(SecurityEventRefreshTokenReuse, token.UserID(), map[string]string{
+		"family_id": token.FamilyID().String(),
+		"token_id":  token.ID().String(),
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
 	}
 
-	s.logger.Info("user logged out", zap.String("user_id", userID.String()))
+	s.logger.Warn("refresh token reuse detected",
+		zap.String("user_id", token.UserID().String()),
+		zap.String("family_id", token.FamilyID().String()),
+	)
+}
+
+// Logout ends the current session, or every session of the user when AllDevices is set,
+// and revokes the access token it was called with (or every token of the user) at once.
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID uuid.UUID, tokenID string, req LogoutRequest) error {
+	if req.AllDevices {
+		if err := s.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+			s.logger.Error("failed to revoke sessions", zap.Error(err), zap.String("user_id", userID.String()))
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		if err := s.revocations.RevokeUser(ctx, userID); err != nil {
+			s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("user_id", userID.String()))
+			return err
+		}
+		s.logger.Info("user logged out of all devices", zap.String("user_id", userID.String()))
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		s.logger.Error("failed to revoke session", zap.Error(err), zap.String("session_id", sessionID.String()))
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	// Tokens issued before jti was introduced can only be revoked with their session.
+	var revokeErr error
+	if tokenID != "" {
+		revokeErr = s.revocations.RevokeToken(ctx, userID, tokenID)
+	} else {
+		revokeErr = s.revocations.RevokeSession(ctx, userID, sessionID)
+	}
+	if revokeErr != nil {
+		s.logger.Error("failed to revoke access token", zap.Error(revokeErr), zap.String("session_id", sessionID.String()))
+		return revokeErr
+	}
+
+	s.logger.Info("user logged out", zap.String("user_id", userID.String()), zap.String("session_id", sessionID.String()))
+	return nil
+}
+
+// ListSessions returns the user's active sessions, flagging the one making the request.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]SessionDTO, error) {
+	sessions, err := s.sessionRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	dtos := make([]SessionDTO, len(sessions))
+	for i, session := range sessions {
+		dtos[i] = toSessionDTO(session)
+		dtos[i].Current = session.ID() == currentSessionID
+	}
+	return dtos, nil
+}
+
+// RevokeSession ends one of the user's sessions.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || !session.BelongsTo(userID) {
+		return domain.NewNotFoundError("Session", sessionID.String())
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		s.logger.Error("failed to revoke session", zap.Error(err), zap.String("session_id", sessionID.String()))
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if err := s.revocations.RevokeSession(ctx, userID, sessionID); err != nil {
+		s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("session_id", sessionID.String()))
+		return err
+	}
+
+	s.logger.Info("session revoked", zap.String("user_id", userID.String()), zap.String("session_id", sessionID.String()))
+	return nil
+}
+
+// RevokeOtherSessions ends every session of the user except the current one.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) error {
+	if err := s.revokeOtherSessions(ctx, userID, currentSessionID); err != nil {
+		return err
+	}
+
+	s.logger.Info("other sessions revoked", zap.String("user_id", userID.String()), zap.String("session_id", currentSessionID.String()))
+	return nil
+}
+
+// ChangePassword replaces a signed-in user's password after checking the current one,
+// ends every other session and tells the user by email. Wrong current passwords count
+// towards the sign-in lockout so a stolen access token cannot be used to guess it.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, req ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.NewNotFoundError("User", userID.String())
+	}
+	if err := s.throttle.Check(ctx, user.Email(), ""); err != nil {
+		return err
+	}
+
+	match, err := s.hasher.Verify(user.PasswordHash(), req.CurrentPassword)
+	if err != nil {
+		s.logger.Error("failed to verify password hash", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+	if !match {
+		s.throttle.RecordFailure(ctx, user.Email(), "", user)
+		return domain.NewValidationError("current password is incorrect")
+	}
+	s.throttle.RecordSuccess(ctx, user.Email())
+
+	if req.NewPassword == req.CurrentPassword {
+		return domain.NewValidationError("new password must differ from the current password")
+	}
+	if err := checkPassword(s.passwordPolicy, req.NewPassword, user.Email(), user.FullName()); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		s.logger.Error("failed to hash password", zap.Error(err))
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.ChangePassword(hashedPassword)
+	user.IncrementVersion()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		// A concurrent change to the account surfaces as a conflict; the client retries.
+		s.logger.Warn("failed to update password", zap.Error(err), zap.String("user_id", userID.String()))
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.revokeOtherSessions(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	if err := s.notifier.SendPasswordChangedEmail(ctx, user.Email()); err != nil {
+		s.logger.Warn("failed to enqueue password changed email", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+	event := NewSecurityEvent(SecurityEventPasswordChanged, userID, map[string]string{
+		"method":     "change",
+		"session_id": sessionID.String(),
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+
+	s.logger.Info("password changed", zap.String("user_id", userID.String()))
 	return nil
 }
 
 // GetProfile retrieves the user profile by ID.
-func (s *AuthService) GetProfile(ctx context.Context, userID uuid.UUID) (*dto.UserDTO, error) {
+func (s *AuthService) GetProfile(ctx context.Context, userID uuid.UUID) (*UserDTO, error) {
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, domain.NewNotFoundError("User", userID.String())
@@ -250,13 +973,15 @@ func (s *AuthService) GetProfile(ctx context.Context, userID uuid.UUID) (*dto.Us
 }
 
 // UpdateProfile updates the user's profile information.
-func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req UpdateProfileRequest) (*dto.UserDTO, error) {
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req UpdateProfileRequest) (*UserDTO, error) {
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, domain.NewNotFoundError("User", userID.String())
 	}
 
-	user.UpdateProfile(req.FullName, req.Phone, req.AvatarURL)
+	if err := user.UpdateProfile(req.FullName, req.Phone, req.AvatarURL); err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
 	user.IncrementVersion()
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
@@ -274,18 +999,18 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID uuid.UUID, req U
 
 // UserStatsDTO holds user statistics for the admin dashboard.
 type UserStatsDTO struct {
-	TotalUsers  int64            `json:"total_users"`
-	ByRole      map[string]int64 `json:"by_role"`
+	TotalUsers int64            `json:"total_users"`
+	ByRole     map[string]int64 `json:"by_role"`
 }
 
 // ListUsers returns a paginated list of all users.
-func (s *AuthService) ListUsers(ctx context.Context, page, limit int) ([]dto.UserDTO, int64, error) {
+func (s *AuthService) ListUsers(ctx context.Context, page, limit int) ([]UserDTO, int64, error) {
 	users, total, err := s.userRepo.ListAll(ctx, page, limit)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list users: %w", err)
 	}
 
-	dtos := make([]dto.UserDTO, len(users))
+	dtos := make([]UserDTO, len(users))
 	for i, u := range users {
 		dtos[i] = toUserDTO(u)
 	}
@@ -293,7 +1018,7 @@ func (s *AuthService) ListUsers(ctx context.Context, page, limit int) ([]dto.Use
 }
 
 // GetUserByID retrieves a single user by ID (admin).
-func (s *AuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*dto.UserDTO, error) {
+func (s *AuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*UserDTO, error) {
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, domain.NewNotFoundError("User", userID.String())
@@ -302,25 +1027,130 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*dto.U
 	return &result, nil
 }
 
-// BanUser deactivates a user account.
-func (s *AuthService) BanUser(ctx context.Context, userID uuid.UUID) error {
+// BanUser bans a user account until an admin lifts the ban and ends all of its sessions.
+func (s *AuthService) BanUser(ctx context.Context, actorID, userID uuid.UUID, req BanUserRequest) error {
+	return s.changeAccountStatus(ctx, actorID, userID, true, func(user *identity.User) error {
+		return user.Ban(actorID, req.Reason)
+	})
+}
+
+// SuspendUser suspends a user account, optionally until a given time, and ends all of its sessions.
+func (s *AuthService) SuspendUser(ctx context.Context, actorID, userID uuid.UUID, req SuspendUserRequest) error {
+	return s.changeAccountStatus(ctx, actorID, userID, true, func(user *identity.User) error {
+		return user.Suspend(actorID, req.Reason, req.Until)
+	})
+}
+
+// UnbanUser lifts a ban or suspension so the user can sign in again.
+func (s *AuthService) UnbanUser(ctx context.Context, actorID, userID uuid.UUID) error {
+	return s.changeAccountStatus(ctx, actorID, userID, false, func(user *identity.User) error {
+		return user.Reinstate(actorID)
+	})
+}
+
+// UnlockUser lifts a lockout earned by repeated failed sign-ins before it runs out.
+func (s *AuthService) UnlockUser(ctx context.Context, actorID, userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.NewNotFoundError("User", userID.String())
+	}
+
+	if err := s.throttle.Unlock(ctx, user.Email()); err != nil {
+		s.logger.Error("failed to unlock account", zap.Error(err), zap.String("user_id", userID.String()))
+		return err
+	}
+
+	event := NewSecurityEvent(SecurityEventAccountUnlocked, userID, map[string]string{
+		"actor_id": actorID.String(),
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+
+	s.logger.Info("account unlocked", zap.String("user_id", userID.String()), zap.String("actor_id", actorID.String()))
+	return nil
+}
+
+// revokeOtherSessions ends every session of the user except keep and revokes their
+// access tokens. The sessions are listed first, as ended ones are no longer listed.
+func (s *AuthService) revokeOtherSessions(ctx context.Context, userID, keep uuid.UUID) error {
+	sessions, err := s.sessionRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if err := s.sessionRepo.RevokeAllForUserExcept(ctx, userID, keep); err != nil {
+		s.logger.Error("failed to revoke other sessions", zap.Error(err), zap.String("user_id", userID.String()))
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+	for _, session := range sessions {
+		if session.ID() == keep {
+			continue
+		}
+		if err := s.revocations.RevokeSession(ctx, userID, session.ID()); err != nil {
+			s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("session_id", session.ID().String()))
+			return err
+		}
+	}
+	return nil
+}
+
+// changeAccountStatus applies an admin status change to a user, persists it,
+// optionally ends the user's sessions and access tokens and publishes a security event.
+func (s *AuthService) changeAccountStatus(
+	ctx context.Context,
+	actorID, userID uuid.UUID,
+	endSessions bool,
+	apply func(user *identity.User) error,
+) error {
+	if actorID == userID {
+		return domain.NewValidationError("admins cannot change the status of their own account")
+	}
+
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return domain.NewNotFoundError("User", userID.String())
 	}
 
-	user.Deactivate()
+	if err := apply(user); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
 	user.IncrementVersion()
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
-		s.logger.Error("failed to ban user", zap.Error(err))
-		return fmt.Errorf("failed to ban user: %w", err)
+		s.logger.Error("failed to update account status", zap.Error(err), zap.String("user_id", userID.String()))
+		return fmt.Errorf("failed to update account status: %w", err)
 	}
 
-	// Revoke all tokens so user is logged out
-	_ = s.tokenRepo.RevokeAllForUser(ctx, userID)
+	if endSessions {
+		if err := s.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+			s.logger.Error("failed to revoke sessions", zap.Error(err), zap.String("user_id", userID.String()))
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		if err := s.revocations.RevokeUser(ctx, userID); err != nil {
+			s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("user_id", userID.String()))
+			return err
+		}
+	}
 
-	s.logger.Info("user banned", zap.String("user_id", userID.String()))
+	standing := user.Standing()
+	metadata := map[string]string{
+		"status":   string(standing.StoredStatus()),
+		"actor_id": actorID.String(),
+		"reason":   standing.Reason(),
+	}
+	if until := standing.ExpiresAt(); until != nil {
+		metadata["until"] = until.Format(time.RFC3339)
+	}
+	event := NewSecurityEvent(SecurityEventAccountStatusChanged, userID, metadata)
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+
+	s.logger.Info("account status changed",
+		zap.String("user_id", userID.String()),
+		zap.String("actor_id", actorID.String()),
+		zap.String("status", string(standing.StoredStatus())),
+	)
 	return nil
 }
 
@@ -344,10 +1174,6 @@ func (s *AuthService) GetUserStats(ctx context.Context) (*UserStatsDTO, error) {
 
 // ResetPassword validates a reset token and updates the user's password hash atomically.
 func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
-	if len(req.NewPassword) < 8 {
-		return domain.NewValidationError("password must be at least 8 characters")
-	}
-
 	reset, err := s.passwordResetRepo.FindAnyByToken(ctx, req.Token)
 	if err != nil {
 		return domain.NewValidationError("invalid token")
@@ -361,42 +1187,84 @@ func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRe
 		return domain.NewValidationError("token expired")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	user, err := s.userRepo.FindByID(ctx, reset.UserID())
+	if err != nil {
+		return domain.NewValidationError("invalid token")
+	}
+	if err := checkPassword(s.passwordPolicy, req.NewPassword, user.Email(), user.FullName()); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(req.NewPassword)
 	if err != nil {
 		s.logger.Error("failed to hash password during reset", zap.Error(err))
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if err := s.passwordResetRepo.MarkUsedAndUpdatePassword(ctx, reset.ID(), reset.UserID(), string(hashedPassword)); err != nil {
+	if err := s.passwordResetRepo.MarkUsedAndUpdatePassword(ctx, reset.ID(), reset.UserID(), hashedPassword); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("token already used")
+		}
 		s.logger.Error("failed to complete password reset transaction", zap.Error(err))
 		return fmt.Errorf("failed to reset password: %w", err)
 	}
+	// The new password is already set, so a failure here is logged rather than returned.
+	if err := s.revocations.RevokeUser(ctx, reset.UserID()); err != nil {
+		s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("user_id", reset.UserID().String()))
+	}
+
+	if err := s.notifier.SendPasswordChangedEmail(ctx, user.Email()); err != nil {
+		s.logger.Warn("failed to enqueue password changed email", zap.Error(err), zap.String("user_id", user.ID().String()))
+	}
+	event := NewSecurityEvent(SecurityEventPasswordChanged, user.ID(), map[string]string{
+		"method": "reset",
+	})
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
 
 	s.logger.Info("password reset completed", zap.String("user_id", reset.UserID().String()))
 	return nil
 }
 
-// ForgotPassword initiates a password reset for the given email.
-// Always returns nil so the handler can respond 202 regardless of whether the email exists.
-func (s *AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+var (
+	// forgotPasswordIPLimit caps reset requests from one client address, whatever the emails.
+	forgotPasswordIPLimit = requestLimit{scope: "forgot_password_ip", limit: 20, window: time.Hour, message: "too many password reset requests"}
+	// forgotPasswordEmailLimit caps reset requests for one email address. Unknown emails
+	// are counted the same way, so the limit reveals nothing about which have accounts.
+	forgotPasswordEmailLimit = requestLimit{scope: "forgot_password_email", limit: 3, window: time.Hour, message: "too many password reset requests for this email"}
+)
+
+// ForgotPassword initiates a password reset for the given email. Issuing a link
+// invalidates the user's earlier ones. Returns nil whether or not the email exists so
+// the handler can respond 202 either way; only throttling is reported.
+func (s *AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest, client ClientInfo) error {
 	if req.Email == "" {
 		return nil
 	}
 
-	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if client.IPAddress != "" {
+		if err := checkRequestLimit(ctx, s.requestCounters, forgotPasswordIPLimit, client.IPAddress); err != nil {
+			return err
+		}
+	}
+	if err := checkRequestLimit(ctx, s.requestCounters, forgotPasswordEmailLimit, accountKey(req.Email)); err != nil {
+		return err
+	}
+
+	user, err := s.findUserByEmail(ctx, req.Email)
 	if err != nil {
 		return nil
 	}
 
-	tokenBytes := make([]byte, 32)
-	if _, err := rand.Read(tokenBytes); err != nil {
+	tokenStr, err := generateToken()
+	if err != nil {
 		s.logger.Error("failed to generate reset token", zap.Error(err))
 		return fmt.Errorf("failed to generate reset token: %w", err)
 	}
-	tokenStr := hex.EncodeToString(tokenBytes)
 
 	reset := identity.NewPasswordReset(user.ID(), tokenStr, time.Now().UTC().Add(time.Hour))
-	if err := s.passwordResetRepo.Create(ctx, reset); err != nil {
+	if err := s.passwordResetRepo.ReplaceOutstanding(ctx, reset); err != nil {
 		s.logger.Error("failed to persist password reset token", zap.Error(err))
 		return fmt.Errorf("failed to persist password reset token: %w", err)
 	}
@@ -408,16 +1276,69 @@ func (s *AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPassword
 	return nil
 }
 
+// generateToken returns a random 256-bit token, hex encoded, for single-use links.
+func generateToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// accessSubject describes the user and session an access token is issued for.
+func accessSubject(user *identity.User, session *identity.Session) tokens.Subject {
+	return tokens.Subject{
+		UserID:        user.ID(),
+		Email:         user.Email(),
+		EmailVerified: user.IsVerified(),
+		PhoneVerified: user.PhoneVerified(),
+		Role:          user.Role(),
+		SessionID:     session.ID(),
+		AuthMethods:   session.AuthMethods(),
+	}
+}
+
+// toSessionDTO converts a domain Session to a SessionDTO.
+func toSessionDTO(session *identity.Session) SessionDTO {
+	return SessionDTO{
+		ID:         session.ID(),
+		DeviceName: session.DeviceName(),
+		Platform:   session.Platform(),
+		UserAgent:  session.UserAgent(),
+		IPAddress:  session.IPAddress(),
+		CreatedAt:  session.CreatedAt(),
+		LastSeenAt: session.LastSeenAt(),
+	}
+}
+
+// checkCanAuthenticate rejects users whose account status forbids signing in or obtaining tokens.
+func checkCanAuthenticate(user *identity.User) error {
+	if err := user.CanAuthenticate(); err != nil {
+		return domain.NewUnauthorizedError(err.Error())
+	}
+	return nil
+}
+
 // toUserDTO converts a domain User to a UserDTO.
-func toUserDTO(user *identity.User) dto.UserDTO {
-	return dto.UserDTO{
-		ID:         user.ID(),
-		Email:      user.Email(),
-		Phone:      user.Phone(),
-		FullName:   user.FullName(),
-		Role:       string(user.Role()),
-		IsVerified: user.IsVerified(),
-		AvatarURL:  user.AvatarURL(),
-		CreatedAt:  user.CreatedAt(),
+func toUserDTO(user *identity.User) UserDTO {
+	result := UserDTO{
+		UserDTO: dto.UserDTO{
+			ID:         user.ID(),
+			Email:      user.Email(),
+			Phone:      user.Phone(),
+			FullName:   user.FullName(),
+			Role:       string(user.Role()),
+			IsVerified: user.IsVerified(),
+			AvatarURL:  user.AvatarURL(),
+			CreatedAt:  user.CreatedAt(),
+		},
+		PhoneVerified: user.PhoneVerified(),
+		Status:        string(user.Status()),
+	}
+	if result.Status != string(identity.AccountActive) {
+		standing := user.Standing()
+		result.StatusReason = standing.Reason()
+		result.SuspendedUntil = standing.ExpiresAt()
 	}
+	return result
 }
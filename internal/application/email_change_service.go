@@ -0,0 +1,220 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// emailChangeTTL is how long the confirm and cancel links of an email change stay valid.
+const emailChangeTTL = 24 * time.Hour
+
+// emailChangeUserLimit caps email change requests per user a day. Every request counts,
+// including those for taken addresses, so the endpoint cannot be used to probe which
+// emails have accounts.
+var emailChangeUserLimit = requestLimit{scope: "email_change_user", limit: 5, window: 24 * time.Hour, message: "too many email change requests"}
+
+// RequestEmailChangeRequest represents a signed-in user asking to move their account
+// to a new email address. The current password is required so a stolen session
+// cannot take over the account by redirecting its email.
+type RequestEmailChangeRequest struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// ConfirmEmailChangeRequest represents the new address confirming an email change.
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CancelEmailChangeRequest represents the old address calling off an email change.
+type CancelEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// EmailChangeService implements changing the email address of an account. A change
+// must be confirmed from the new address, and the old address is told about it and
+// can cancel it until then. Sessions are kept when the address changes.
+type EmailChangeService struct {
+	changeRepo      identity.EmailChangeRepository
+	userRepo        identity.UserRepository
+	requestCounters identity.RequestCounterRepository
+	hasher          PasswordHasher
+	throttle        *LoginThrottle
+	notifier        EmailChangeNotifier
+	events          SecurityEventPublisher
+	logger          *zap.Logger
+}
+
+// NewEmailChangeService creates a new EmailChangeService.
+func NewEmailChangeService(
+	changeRepo identity.EmailChangeRepository,
+	userRepo identity.UserRepository,
+	requestCounters identity.RequestCounterRepository,
+	hasher PasswordHasher,
+	throttle *LoginThrottle,
+	notifier EmailChangeNotifier,
+	events SecurityEventPublisher,
+	logger *zap.Logger,
+) *EmailChangeService {
+	return &EmailChangeService{
+		changeRepo:      changeRepo,
+		userRepo:        userRepo,
+		requestCounters: requestCounters,
+		hasher:          hasher,
+		throttle:        throttle,
+		notifier:        notifier,
+		events:          events,
+		logger:          logger,
+	}
+}
+
+// RequestEmailChange checks the current password, then sends a confirm link to the
+// new address and a cancel link to the current one. A newer request replaces any
+// pending one. Wrong passwords count towards the sign-in lockout.
+//
+// An address that already has an account is answered the same way, so the caller
+// learns nothing; the address is told instead, and no change is created.
+func (s *EmailChangeService) RequestEmailChange(ctx context.Context, userID uuid.UUID, req RequestEmailChangeRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.NewNotFoundError("User", userID.String())
+	}
+	if err := s.throttle.Check(ctx, user.Email(), ""); err != nil {
+		return err
+	}
+
+	match, err := s.hasher.Verify(user.PasswordHash(), req.CurrentPassword)
+	if err != nil {
+		s.logger.Error("failed to verify password hash", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+	if !match {
+		s.throttle.RecordFailure(ctx, user.Email(), "", user)
+		return domain.NewValidationError("current password is incorrect")
+	}
+	s.throttle.RecordSuccess(ctx, user.Email())
+
+	if err := checkRequestLimit(ctx, s.requestCounters, emailChangeUserLimit, userID.String()); err != nil {
+		return err
+	}
+
+	newEmail, err := identity.NewEmail(req.NewEmail)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	if newEmail.Equals(user.EmailVO()) {
+		return domain.NewValidationError("new email must differ from the current email")
+	}
+	if existing, _ := s.userRepo.FindByEmail(ctx, newEmail); existing != nil {
+		if err := s.notifier.SendEmailChangeAddressTakenEmail(ctx, newEmail.String()); err != nil {
+			s.logger.Warn("failed to enqueue email change address taken email", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+		s.logger.Info("email change requested to a taken address", zap.String("user_id", userID.String()))
+		return nil
+	}
+
+	confirmToken, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate email change token", zap.Error(err))
+		return fmt.Errorf("failed to generate email change token: %w", err)
+	}
+	cancelToken, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate email change token", zap.Error(err))
+		return fmt.Errorf("failed to generate email change token: %w", err)
+	}
+
+	change, err := identity.NewEmailChange(userID, user.Email(), newEmail.String(), confirmToken, cancelToken, time.Now().UTC().Add(emailChangeTTL))
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	if err := s.changeRepo.Create(ctx, change); err != nil {
+		s.logger.Error("failed to persist email change", zap.Error(err))
+		return fmt.Errorf("failed to persist email change: %w", err)
+	}
+
+	if err := s.notifier.SendEmailChangeConfirmationEmail(ctx, change.NewEmail(), confirmToken); err != nil {
+		s.logger.Warn("failed to enqueue email change confirmation email", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+	if err := s.notifier.SendEmailChangeRequestedEmail(ctx, change.OldEmail(), change.NewEmail(), cancelToken); err != nil {
+		s.logger.Warn("failed to enqueue email change requested email", zap.Error(err), zap.String("user_id", userID.String()))
+	}
+
+	s.logger.Info("email change requested", zap.String("user_id", userID.String()), zap.String("change_id", change.ID().String()))
+	return nil
+}
+
+// ConfirmEmailChange consumes a confirm link and moves the account to the new address,
+// which counts as verified since the link reached it. Sessions stay signed in; their
+// access tokens carry the old address until refreshed.
+func (s *EmailChangeService) ConfirmEmailChange(ctx context.Context, req ConfirmEmailChangeRequest) error {
+	change, err := s.changeRepo.FindByConfirmToken(ctx, req.Token)
+	if err != nil {
+		return domain.NewValidationError("invalid token")
+	}
+	if err := change.Confirm(); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	if existing, _ := s.userRepo.FindByEmail(ctx, change.NewEmailVO()); existing != nil {
+		return domain.NewAlreadyExistsError("User", "email", change.NewEmail())
+	}
+
+	if err := s.changeRepo.Confirm(ctx, change); err != nil {
+		if errors.Is(err, identity.ErrEmailChangeNotPending) {
+			return domain.NewValidationError(err.Error())
+		}
+		// Another account claiming the address concurrently surfaces as an already-exists error.
+		s.logger.Warn("failed to confirm email change", zap.Error(err), zap.String("change_id", change.ID().String()))
+		return err
+	}
+
+	if err := s.notifier.SendEmailChangedEmail(ctx, change.OldEmail(), change.NewEmail()); err != nil {
+		s.logger.Warn("failed to enqueue email changed email", zap.Error(err), zap.String("user_id", change.UserID().String()))
+	}
+	s.publish(ctx, NewSecurityEvent(SecurityEventEmailChanged, change.UserID(), map[string]string{
+		"change_id": change.ID().String(),
+	}))
+
+	s.logger.Info("email changed", zap.String("user_id", change.UserID().String()), zap.String("change_id", change.ID().String()))
+	return nil
+}
+
+// CancelEmailChange consumes a cancel link sent to the old address and calls off the
+// pending change, so the confirm link stops working.
+func (s *EmailChangeService) CancelEmailChange(ctx context.Context, req CancelEmailChangeRequest) error {
+	change, err := s.changeRepo.FindByCancelToken(ctx, req.Token)
+	if err != nil {
+		return domain.NewValidationError("invalid token")
+	}
+	if err := change.Cancel(); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	if err := s.changeRepo.Cancel(ctx, change); err != nil {
+		if errors.Is(err, identity.ErrEmailChangeNotPending) {
+			return domain.NewValidationError(err.Error())
+		}
+		s.logger.Error("failed to cancel email change", zap.Error(err))
+		return fmt.Errorf("failed to cancel email change: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventEmailChangeCancelled, change.UserID(), map[string]string{
+		"change_id": change.ID().String(),
+	}))
+
+	s.logger.Info("email change cancelled", zap.String("user_id", change.UserID().String()), zap.String("change_id", change.ID().String()))
+	return nil
+}
+
+func (s *EmailChangeService) publish(ctx context.Context, event SecurityEvent) {
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+}
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// emailVerificationTTL is how long a verification link stays valid.
+	emailVerificationTTL = 24 * time.Hour
+	// emailVerificationCooldown is the minimum gap between two verification emails.
+	emailVerificationCooldown = time.Minute
+	// emailVerificationDailyLimit caps verification emails per user per rolling day.
+	emailVerificationDailyLimit = 5
+)
+
+// VerifyEmailRequest represents a request to confirm an email address.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// EmailVerificationPolicy decides which roles must verify their email address
+// before performing protected actions.
+type EmailVerificationPolicy struct {
+	requiredRoles map[auth.UserRole]bool
+}
+
+// NewEmailVerificationPolicy creates a policy from a comma-separated list of roles,
+// e.g. "owner,shop". An empty list requires verification from no one.
+func NewEmailVerificationPolicy(roles string) *EmailVerificationPolicy {
+	required := make(map[auth.UserRole]bool)
+	for _, role := range strings.Split(roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			required[auth.UserRole(role)] = true
+		}
+	}
+	return &EmailVerificationPolicy{requiredRoles: required}
+}
+
+// RequiresVerifiedEmail reports whether users with the role must verify their email first.
+func (p *EmailVerificationPolicy) RequiresVerifiedEmail(role auth.UserRole) bool {
+	return p.requiredRoles[role]
+}
+
+// EmailVerificationService implements the email verification use cases.
+type EmailVerificationService struct {
+	verificationRepo identity.EmailVerificationRepository
+	userRepo         identity.UserRepository
+	notifier         EmailVerificationNotifier
+	logger           *zap.Logger
+}
+
+// NewEmailVerificationService creates a new EmailVerificationService.
+func NewEmailVerificationService(
+	verificationRepo identity.EmailVerificationRepository,
+	userRepo identity.UserRepository,
+	notifier EmailVerificationNotifier,
+	logger *zap.Logger,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		notifier:         notifier,
+		logger:           logger,
+	}
+}
+
+// SendVerification issues a verification token for the user's current email address
+// and sends it. Already verified users are skipped.
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *identity.User) error {
+	if user.IsVerified() {
+		return nil
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate verification token", zap.Error(err))
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	verification := identity.NewEmailVerification(user.ID(), user.Email(), token, time.Now().UTC().Add(emailVerificationTTL))
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		s.logger.Error("failed to persist verification token", zap.Error(err))
+		return fmt.Errorf("failed to persist verification token: %w", err)
+	}
+
+	if err := s.notifier.SendVerificationEmail(ctx, user.Email(), token); err != nil {
+		s.logger.Warn("failed to enqueue verification email", zap.Error(err), zap.String("email", user.Email()))
+	}
+	return nil
+}
+
+// ResendVerification sends a fresh verification email, throttled per user.
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.NewNotFoundError("User", userID.String())
+	}
+	if user.IsVerified() {
+		return domain.NewValidationError("email already verified")
+	}
+
+	now := time.Now().UTC()
+	recent, err := s.verificationRepo.CountCreatedSince(ctx, userID, now.Add(-emailVerificationCooldown))
+	if err != nil {
+		return fmt.Errorf("failed to check verification throttle: %w", err)
+	}
+	if recent > 0 {
+		return NewRateLimitedError("verification email was sent recently", emailVerificationCooldown)
+	}
+
+	daily, err := s.verificationRepo.CountCreatedSince(ctx, userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to check verification throttle: %w", err)
+	}
+	if daily >= emailVerificationDailyLimit {
+		return NewRateLimitedError("too many verification emails requested", 24*time.Hour)
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+// SendRegistrationAttempt answers a sign-up with an email that already has an account
+// by writing to that address instead of telling the caller. An unverified owner gets
+// a fresh verification link, throttled like ResendVerification; a verified owner is
+// told that someone tried to register with their email.
+func (s *EmailVerificationService) SendRegistrationAttempt(ctx context.Context, user *identity.User) error {
+	if !user.IsVerified() {
+		return s.ResendVerification(ctx, user.ID())
+	}
+
+	if err := s.notifier.SendRegistrationAttemptEmail(ctx, user.Email()); err != nil {
+		s.logger.Warn("failed to enqueue registration attempt email", zap.Error(err), zap.String("user_id", user.ID().String()))
+	}
+	return nil
+}
+
+// VerifyEmail consumes a verification token and marks the user's email verified.
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, req VerifyEmailRequest) error {
+	verification, err := s.verificationRepo.FindAnyByToken(ctx, req.Token)
+	if err != nil {
+		return domain.NewValidationError("invalid token")
+	}
+	if verification.IsUsed() {
+		return domain.NewValidationError("token already used")
+	}
+	if verification.IsExpired() {
+		return domain.NewValidationError("token expired")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, verification.UserID())
+	if err != nil {
+		return domain.NewValidationError("invalid token")
+	}
+	if user.Email() != verification.Email() {
+		// The address changed after this token was issued.
+		return domain.NewValidationError("invalid token")
+	}
+
+	if err := s.verificationRepo.MarkUsedAndVerifyUser(ctx, verification.ID(), user.ID()); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("token already used")
+		}
+		s.logger.Error("failed to complete email verification", zap.Error(err))
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	s.logger.Info("email verified", zap.String("user_id", user.ID().String()))
+	return nil
+}
@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultIntrospectionCacheTTL is how long an introspection answer is reused when
+	// no TTL is configured. A ban or logout takes at most this long to show.
+	DefaultIntrospectionCacheTTL = 10 * time.Second
+	// introspectionCacheSize caps the number of cached answers.
+	introspectionCacheSize = 10000
+)
+
+// IntrospectRequest is an RFC 7662 introspection request. Only access tokens are
+// introspected; token_type_hint is accepted and ignored.
+type IntrospectRequest struct {
+	Token         string `form:"token" json:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+}
+
+// IntrospectionResponse is an RFC 7662 introspection response. An inactive token
+// carries no other fields, whatever the reason it is inactive.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	TokenType string `json:"token_type,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+	TokenID   string `json:"jti,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// inactiveToken is the answer for every token that is not active.
+var inactiveToken = IntrospectionResponse{Active: false}
+
+// IntrospectionService tells other services whether an access token is still good:
+// correctly signed, unexpired and not revoked, issued to an account that may still sign
+// in, for a session that has not ended. Answers are cached briefly so callers can
+// introspect on every request.
+type IntrospectionService struct {
+	tokens      *tokens.Manager
+	revocations *TokenRevocationList
+	userRepo    identity.UserRepository
+	sessionRepo identity.SessionRepository
+	cache       *introspectionCache
+	logger      *zap.Logger
+}
+
+// NewIntrospectionService creates a new IntrospectionService. A zero cacheTTL means
+// DefaultIntrospectionCacheTTL; a negative one disables the cache.
+func NewIntrospectionService(
+	tokenManager *tokens.Manager,
+	revocations *TokenRevocationList,
+	userRepo identity.UserRepository,
+	sessionRepo identity.SessionRepository,
+	cacheTTL time.Duration,
+	logger *zap.Logger,
+) *IntrospectionService {
+	if cacheTTL == 0 {
+		cacheTTL = DefaultIntrospectionCacheTTL
+	}
+	return &IntrospectionService{
+		tokens:      tokenManager,
+		revocations: revocations,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		cache:       newIntrospectionCache(cacheTTL, introspectionCacheSize),
+		logger:      logger,
+	}
+}
+
+// Introspect reports whether token is active and, if so, whom it was issued to.
+// Storage failures are returned as errors rather than answered as inactive, so
+// callers can tell an outage from a revoked token.
+func (s *IntrospectionService) Introspect(ctx context.Context, req IntrospectRequest) (*IntrospectionResponse, error) {
+	key := sha256.Sum256([]byte(req.Token))
+	now := time.Now().UTC()
+	if cached, ok := s.cache.get(key, now); ok {
+		return &cached, nil
+	}
+
+	resp, err := s.introspect(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(s.cache.ttl)
+	if resp.Active && time.Unix(resp.ExpiresAt, 0).Before(expiresAt) {
+		expiresAt = time.Unix(resp.ExpiresAt, 0)
+	}
+	s.cache.put(key, resp, expiresAt, now)
+	return &resp, nil
+}
+
+func (s *IntrospectionService) introspect(ctx context.Context, token string) (IntrospectionResponse, error) {
+	claims, err := s.tokens.ValidateAccessToken(token)
+	if err != nil || s.revocations.IsRevoked(claims) {
+		return inactiveToken, nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return inactiveToken, nil
+		}
+		s.logger.Error("failed to load user for introspection", zap.Error(err))
+		return IntrospectionResponse{}, fmt.Errorf("failed to introspect token: %w", err)
+	}
+	if user.CanAuthenticate() != nil {
+		return inactiveToken, nil
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return inactiveToken, nil
+		}
+		s.logger.Error("failed to load session for introspection", zap.Error(err))
+		return IntrospectionResponse{}, fmt.Errorf("failed to introspect token: %w", err)
+	}
+	if !session.IsActive() || !session.BelongsTo(user.ID()) {
+		return inactiveToken, nil
+	}
+
+	resp := IntrospectionResponse{
+		Active:    true,
+		TokenType: "Bearer",
+		Subject:   claims.Subject,
+		Role:      string(user.Role()),
+		SessionID: claims.SessionID.String(),
+		TokenID:   claims.ID,
+		Issuer:    claims.Issuer,
+	}
+	if claims.IssuedAt != nil {
+		resp.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		resp.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	return resp, nil
+}
+
+// introspectionCache keeps introspection answers in memory, keyed by the SHA-256 of
+// the token so raw tokens are not held. It is bounded: when full, expired answers are
+// dropped, and if none have expired the cache starts over.
+type introspectionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[[sha256.Size]byte]introspectionCacheEntry
+}
+
+type introspectionCacheEntry struct {
+	response  IntrospectionResponse
+	expiresAt time.Time
+}
+
+func newIntrospectionCache(ttl time.Duration, size int) *introspectionCache {
+	return &introspectionCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[[sha256.Size]byte]introspectionCacheEntry),
+	}
+}
+
+func (c *introspectionCache) get(key [sha256.Size]byte, now time.Time) (IntrospectionResponse, bool) {
+	if c.ttl <= 0 {
+		return IntrospectionResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return IntrospectionResponse{}, false
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return IntrospectionResponse{}, false
+	}
+	return entry.response, true
+}
+
+func (c *introspectionCache) put(key [sha256.Size]byte, response IntrospectionResponse, expiresAt, now time.Time) {
+	if c.ttl <= 0 || !now.Before(expiresAt) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.size {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.size {
+			c.entries = make(map[[sha256.Size]byte]introspectionCacheEntry)
+		}
+	}
+	c.entries[key] = introspectionCacheEntry{response: response, expiresAt: expiresAt}
+}
@@ -0,0 +1,226 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// invitationTTL is how long an invitation link stays valid.
+const invitationTTL = 72 * time.Hour
+
+// CreateInvitationRequest represents an admin request to invite an email address.
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner runner admin shop"`
+}
+
+// AcceptInvitationRequest represents an invitee accepting an invitation and choosing credentials.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	FullName string `json:"full_name" binding:"required"`
+	Phone    string `json:"phone"`
+	Password string `json:"password" binding:"required"`
+}
+
+// InvitationDTO represents an invitation in API responses. The token is never included.
+type InvitationDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	InvitedBy      uuid.UUID  `json:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *uuid.UUID `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// InvitationService implements the admin invitation use cases.
+type InvitationService struct {
+	invitationRepo identity.InvitationRepository
+	userRepo       identity.UserRepository
+	hasher         PasswordHasher
+	passwordPolicy PasswordPolicy
+	notifier       InvitationNotifier
+	logger         *zap.Logger
+}
+
+// NewInvitationService creates a new InvitationService.
+func NewInvitationService(
+	invitationRepo identity.InvitationRepository,
+	userRepo identity.UserRepository,
+	hasher PasswordHasher,
+	passwordPolicy PasswordPolicy,
+	notifier InvitationNotifier,
+	logger *zap.Logger,
+) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		hasher:         hasher,
+		passwordPolicy: passwordPolicy,
+		notifier:       notifier,
+		logger:         logger,
+	}
+}
+
+// Invite creates an invitation for an email address and sends the invitee a single-use link.
+func (s *InvitationService) Invite(ctx context.Context, actorID uuid.UUID, req CreateInvitationRequest) (*InvitationDTO, error) {
+	email, err := identity.NewEmail(req.Email)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	if existing, _ := s.userRepo.FindByEmail(ctx, email); existing != nil {
+		return nil, domain.NewAlreadyExistsError("User", "email", email.String())
+	}
+	if pending, _ := s.invitationRepo.FindPendingByEmail(ctx, email); pending != nil {
+		return nil, domain.NewAlreadyExistsError("Invitation", "email", email.String())
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate invitation token", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	invitation, err := identity.NewInvitation(req.Email, auth.UserRole(req.Role), actorID, token, time.Now().UTC().Add(invitationTTL))
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	if err := s.invitationRepo.Save(ctx, invitation); err != nil {
+		s.logger.Error("failed to save invitation", zap.Error(err))
+		return nil, fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	if err := s.notifier.SendInvitationEmail(ctx, invitation.Email(), req.Role, token); err != nil {
+		s.logger.Warn("failed to enqueue invitation email", zap.Error(err), zap.String("email", invitation.Email()))
+	}
+
+	s.logger.Info("invitation created",
+		zap.String("invitation_id", invitation.ID().String()),
+		zap.String("role", req.Role),
+		zap.String("invited_by", actorID.String()),
+	)
+
+	result := toInvitationDTO(invitation)
+	return &result, nil
+}
+
+// ListInvitations returns a paginated list of invitations.
+func (s *InvitationService) ListInvitations(ctx context.Context, page, limit int) ([]InvitationDTO, int64, error) {
+	invitations, total, err := s.invitationRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	dtos := make([]InvitationDTO, len(invitations))
+	for i, invitation := range invitations {
+		dtos[i] = toInvitationDTO(invitation)
+	}
+	return dtos, total, nil
+}
+
+// RevokeInvitation withdraws a pending invitation so its link can no longer be used.
+func (s *InvitationService) RevokeInvitation(ctx context.Context, actorID, invitationID uuid.UUID) error {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return domain.NewNotFoundError("Invitation", invitationID.String())
+	}
+
+	if err := invitation.Revoke(); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	if err := s.invitationRepo.Revoke(ctx, invitation); err != nil {
+		if errors.Is(err, identity.ErrInvitationNotPending) {
+			return domain.NewValidationError(err.Error())
+		}
+		s.logger.Error("failed to revoke invitation", zap.Error(err))
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	s.logger.Info("invitation revoked",
+		zap.String("invitation_id", invitationID.String()),
+		zap.String("revoked_by", actorID.String()),
+	)
+	return nil
+}
+
+// AcceptInvitation consumes an invitation token and creates the invited account.
+// The invitee proved control of the email by receiving the link, so the account starts verified.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, req AcceptInvitationRequest) (*UserDTO, error) {
+	invitation, err := s.invitationRepo.FindByToken(ctx, req.Token)
+	if err != nil {
+		return nil, domain.NewValidationError("invalid invitation")
+	}
+	if !invitation.IsPending() {
+		return nil, domain.NewValidationError(fmt.Sprintf("invitation is %s", invitation.Status()))
+	}
+
+	if existing, _ := s.userRepo.FindByEmail(ctx, invitation.EmailVO()); existing != nil {
+		return nil, domain.NewAlreadyExistsError("User", "email", invitation.Email())
+	}
+
+	if err := checkPassword(s.passwordPolicy, req.Password, invitation.Email(), req.FullName); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		s.logger.Error("failed to hash password", zap.Error(err))
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := identity.NewUser(invitation.Email(), req.Phone, req.FullName, hashedPassword, invitation.Role())
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	user.Verify()
+
+	if err := invitation.Accept(user.ID()); err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	if err := s.invitationRepo.Accept(ctx, invitation, user); err != nil {
+		if errors.Is(err, identity.ErrInvitationNotPending) {
+			return nil, domain.NewValidationError(err.Error())
+		}
+		s.logger.Error("failed to accept invitation", zap.Error(err))
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	s.logger.Info("invitation accepted",
+		zap.String("invitation_id", invitation.ID().String()),
+		zap.String("user_id", user.ID().String()),
+		zap.String("role", string(user.Role())),
+	)
+
+	result := toUserDTO(user)
+	return &result, nil
+}
+
+// toInvitationDTO converts a domain Invitation to an InvitationDTO.
+func toInvitationDTO(invitation *identity.Invitation) InvitationDTO {
+	return InvitationDTO{
+		ID:             invitation.ID(),
+		Email:          invitation.Email(),
+		Role:           string(invitation.Role()),
+		Status:         string(invitation.Status()),
+		InvitedBy:      invitation.InvitedBy(),
+		ExpiresAt:      invitation.ExpiresAt(),
+		AcceptedAt:     invitation.AcceptedAt(),
+		AcceptedUserID: invitation.AcceptedUserID(),
+		RevokedAt:      invitation.RevokedAt(),
+		CreatedAt:      invitation.CreatedAt(),
+	}
+}
@@ -0,0 +1,197 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"go.uber.org/zap"
+)
+
+const (
+	// loginFailureWindow is how long without a failure it takes for a counter to start over.
+	loginFailureWindow = 15 * time.Minute
+	// loginDelayAfter is how many consecutive failures an account gets before each
+	// further attempt has to wait.
+	loginDelayAfter = 3
+	// loginMaxDelay caps the wait between attempts on an account.
+	loginMaxDelay = time.Minute
+	// loginLockThreshold is how many consecutive failures lock an account.
+	loginLockThreshold = 10
+	// loginLockDuration is how long an account lockout lasts.
+	loginLockDuration = 15 * time.Minute
+	// ipLockThreshold is how many failures from one address, across all accounts,
+	// lock that address out. It is higher than the account threshold because
+	// offices and mobile carriers put many users behind one address.
+	ipLockThreshold = 50
+	// ipLockDuration is how long an address lockout lasts.
+	ipLockDuration = 15 * time.Minute
+)
+
+// LoginThrottle limits password guessing. Failed sign-ins are counted per account
+// and per client IP in shared storage; after a few failures an account must wait
+// progressively longer between attempts, and too many failures lock it for a while.
+// Attempts refused by the throttle are answered before any password is checked and
+// are not counted themselves.
+//
+// Counters are keyed by the submitted email whether or not an account exists, so
+// the throttle behaves the same for unknown emails and does not reveal which exist.
+type LoginThrottle struct {
+	failureRepo identity.LoginFailureRepository
+	notifier    AccountLockedNotifier
+	events      SecurityEventPublisher
+	logger      *zap.Logger
+}
+
+// NewLoginThrottle creates a new LoginThrottle.
+func NewLoginThrottle(
+	failureRepo identity.LoginFailureRepository,
+	notifier AccountLockedNotifier,
+	events SecurityEventPublisher,
+	logger *zap.Logger,
+) *LoginThrottle {
+	return &LoginThrottle{
+		failureRepo: failureRepo,
+		notifier:    notifier,
+		events:      events,
+		logger:      logger,
+	}
+}
+
+// Check returns a RateLimitedError if the account or the address is locked, or the
+// account is still inside its delay after the last failure.
+func (t *LoginThrottle) Check(ctx context.Context, email, ipAddress string) error {
+	now := time.Now().UTC()
+
+	account, err := t.find(ctx, identity.LoginFailureScopeAccount, accountKey(email))
+	if err != nil {
+		return err
+	}
+	if account != nil {
+		if account.IsLocked(now) {
+			return NewRateLimitedError("too many failed sign-in attempts; the account is temporarily locked", account.LockedUntil().Sub(now))
+		}
+		if wait := account.LastFailedAt().Add(loginDelay(account.Failures())).Sub(now); wait > 0 {
+			return NewRateLimitedError("too many failed sign-in attempts", wait)
+		}
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+	address, err := t.find(ctx, identity.LoginFailureScopeIP, ipAddress)
+	if err != nil {
+		return err
+	}
+	if address != nil && address.IsLocked(now) {
+		return NewRateLimitedError("too many failed sign-in attempts from this address", address.LockedUntil().Sub(now))
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt against the email and the address, and locks
+// either once it crosses its threshold. user is the account the email belongs to, or
+// nil if none does; only real accounts are told about a lockout. Storage errors are
+// logged rather than returned so the caller still reports the failed sign-in.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, ipAddress string, user *identity.User) {
+	key := accountKey(email)
+	account, err := t.failureRepo.RecordFailure(ctx, identity.LoginFailureScopeAccount, key, loginFailureWindow)
+	if err != nil {
+		t.logger.Error("failed to record sign-in failure", zap.Error(err))
+	} else if account.Failures() >= loginLockThreshold {
+		until := time.Now().UTC().Add(loginLockDuration)
+		locked, err := t.failureRepo.Lock(ctx, identity.LoginFailureScopeAccount, key, until)
+		if err != nil {
+			t.logger.Error("failed to lock account", zap.Error(err))
+		} else if locked {
+			t.accountLocked(ctx, user, until)
+		}
+	}
+
+	if ipAddress == "" {
+		return
+	}
+	address, err := t.failureRepo.RecordFailure(ctx, identity.LoginFailureScopeIP, ipAddress, loginFailureWindow)
+	if err != nil {
+		t.logger.Error("failed to record sign-in failure", zap.Error(err))
+		return
+	}
+	if address.Failures() >= ipLockThreshold {
+		locked, err := t.failureRepo.Lock(ctx, identity.LoginFailureScopeIP, ipAddress, time.Now().UTC().Add(ipLockDuration))
+		if err != nil {
+			t.logger.Error("failed to lock address", zap.Error(err))
+		} else if locked {
+			t.logger.Warn("sign-ins locked for address after repeated failures", zap.String("ip_address", ipAddress))
+		}
+	}
+}
+
+// RecordSuccess clears the account's failures after a completed sign-in. The address
+// counter is left alone so one working account cannot be used to keep guessing others.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) {
+	if err := t.failureRepo.Reset(ctx, identity.LoginFailureScopeAccount, accountKey(email)); err != nil {
+		t.logger.Error("failed to reset sign-in failures", zap.Error(err))
+	}
+}
+
+// Unlock lifts an account lockout and clears its failures.
+func (t *LoginThrottle) Unlock(ctx context.Context, email string) error {
+	if err := t.failureRepo.Reset(ctx, identity.LoginFailureScopeAccount, accountKey(email)); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+	return nil
+}
+
+func (t *LoginThrottle) find(ctx context.Context, scope identity.LoginFailureScope, key string) (*identity.LoginFailure, error) {
+	failure, err := t.failureRepo.Find(ctx, scope, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		t.logger.Error("failed to load sign-in failures", zap.Error(err))
+		return nil, fmt.Errorf("failed to load sign-in failures: %w", err)
+	}
+	return failure, nil
+}
+
+func (t *LoginThrottle) accountLocked(ctx context.Context, user *identity.User, until time.Time) {
+	if user == nil {
+		return
+	}
+
+	t.logger.Warn("account locked after repeated failed sign-ins",
+		zap.String("user_id", user.ID().String()),
+		zap.Time("locked_until", until),
+	)
+	if err := t.notifier.SendAccountLockedEmail(ctx, user.Email(), until); err != nil {
+		t.logger.Warn("failed to enqueue account locked email", zap.Error(err), zap.String("user_id", user.ID().String()))
+	}
+	event := NewSecurityEvent(SecurityEventAccountLocked, user.ID(), map[string]string{
+		"locked_until": until.Format(time.RFC3339),
+	})
+	if err := t.events.Publish(ctx, event); err != nil {
+		t.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+}
+
+// loginDelay is the wait required after the given number of consecutive failures:
+// none for the first few, then doubling from one second up to loginMaxDelay.
+func loginDelay(failures int) time.Duration {
+	if failures < loginDelayAfter {
+		return 0
+	}
+	delay := time.Second << (failures - loginDelayAfter)
+	if delay > loginMaxDelay || delay <= 0 {
+		return loginMaxDelay
+	}
+	return delay
+}
+
+// accountKey normalizes an email the way stored emails are, so "A@x.my " and "a@x.my" share a counter.
+func accountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
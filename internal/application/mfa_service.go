@@ -0,0 +1,386 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/totp"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// totpSkew is how many time steps either side of now a code is accepted for, to absorb clock drift.
+	totpSkew = 1
+	// mfaPolicyCacheTTL bounds how stale the cached policy may be on instances that did not change it.
+	mfaPolicyCacheTTL = 30 * time.Second
+)
+
+// TOTPCodeRequest carries a code from the user's authenticator app.
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// UpdateMFAPolicyRequest represents an admin request to set which roles must use two-factor authentication.
+type UpdateMFAPolicyRequest struct {
+	RequiredRoles []string `json:"required_roles" binding:"required,dive,oneof=owner runner admin shop"`
+}
+
+// TOTPEnrollmentDTO is returned when an authenticator app enrollment starts. The
+// secret is shown once, for manual entry or as the QR code of OTPAuthURI.
+type TOTPEnrollmentDTO struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAStatusDTO describes a user's two-factor authentication state.
+type MFAStatusDTO struct {
+	TOTPEnabled bool `json:"totp_enabled"`
+	Required    bool `json:"required"`
+}
+
+// MFAPolicyDTO lists the roles that must use two-factor authentication.
+type MFAPolicyDTO struct {
+	RequiredRoles []string `json:"required_roles"`
+}
+
+// MFAService implements authenticator app enrollment, code checks and the
+// admin policy of which roles must use a second factor.
+type MFAService struct {
+	factorRepo identity.TOTPFactorRepository
+	policyRepo identity.MFAPolicyRepository
+	userRepo   identity.UserRepository
+	throttle   *LoginThrottle
+	events     SecurityEventPublisher
+	issuer     string
+	logger     *zap.Logger
+
+	mu             sync.RWMutex
+	requiredRoles  map[auth.UserRole]bool
+	policyLoadedAt time.Time
+}
+
+// NewMFAService creates a new MFAService. issuer is the account name authenticator apps
+// display. Wrong codes when enabling or disabling the authenticator count towards the
+// sign-in lockout through throttle, like wrong codes at sign-in.
+func NewMFAService(
+	factorRepo identity.TOTPFactorRepository,
+	policyRepo identity.MFAPolicyRepository,
+	userRepo identity.UserRepository,
+	throttle *LoginThrottle,
+	events SecurityEventPublisher,
+	issuer string,
+	logger *zap.Logger,
+) *MFAService {
+	return &MFAService{
+		factorRepo: factorRepo,
+		policyRepo: policyRepo,
+		userRepo:   userRepo,
+		throttle:   throttle,
+		events:     events,
+		issuer:     issuer,
+		logger:     logger,
+	}
+}
+
+// Status returns whether the user has an authenticator app and whether their role requires one.
+func (s *MFAService) Status(ctx context.Context, userID uuid.UUID) (*MFAStatusDTO, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("User", userID.String())
+	}
+
+	enabled, err := s.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &MFAStatusDTO{
+		TOTPEnabled: enabled,
+		Required:    s.RequiresMFA(ctx, user.Role()),
+	}, nil
+}
+
+// EnrollTOTP starts an authenticator app enrollment with a fresh secret. It replaces
+// any unconfirmed enrollment and only takes effect once confirmed with a code.
+func (s *MFAService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (*TOTPEnrollmentDTO, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("User", userID.String())
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.logger.Error("failed to generate totp secret", zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.factorRepo.Save(ctx, identity.NewTOTPFactor(userID, secret)); err != nil {
+		if errors.Is(err, identity.ErrTOTPAlreadyEnabled) {
+			return nil, domain.NewConflictError(err.Error())
+		}
+		s.logger.Error("failed to save totp enrollment", zap.Error(err))
+		return nil, fmt.Errorf("failed to save totp enrollment: %w", err)
+	}
+
+	s.logger.Info("totp enrollment started", zap.String("user_id", userID.String()))
+	return &TOTPEnrollmentDTO{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.issuer, user.Email(), secret),
+	}, nil
+}
+
+// ConfirmTOTP activates a pending enrollment once the user enters a valid code from it.
+func (s *MFAService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, req TOTPCodeRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.NewNotFoundError("User", userID.String())
+	}
+	if err := s.throttle.Check(ctx, user.Email(), ""); err != nil {
+		return err
+	}
+
+	factor, err := s.factorRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("no authenticator enrollment in progress")
+		}
+		return fmt.Errorf("failed to load totp enrollment: %w", err)
+	}
+	if factor.IsConfirmed() {
+		return domain.NewConflictError(identity.ErrTOTPAlreadyEnabled.Error())
+	}
+
+	step, ok := totp.Validate(factor.Secret(), req.Code, time.Now().UTC(), totpSkew)
+	if !ok {
+		s.throttle.RecordFailure(ctx, user.Email(), "", user)
+		return domain.NewValidationError("invalid authentication code")
+	}
+	s.throttle.RecordSuccess(ctx, user.Email())
+	if err := factor.Confirm(step); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	if err := s.factorRepo.Confirm(ctx, factor); err != nil {
+		if errors.Is(err, identity.ErrTOTPAlreadyEnabled) {
+			return domain.NewConflictError(err.Error())
+		}
+		s.logger.Error("failed to confirm totp enrollment", zap.Error(err))
+		return fmt.Errorf("failed to confirm totp enrollment: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventMFAEnabled, userID, map[string]string{"method": "totp"}))
+	s.logger.Info("totp enabled", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// DisableTOTP removes the user's authenticator app after checking a current code.
+// Users whose role requires two-factor authentication cannot disable it.
+func (s *MFAService) DisableTOTP(ctx context.Context, userID uuid.UUID, req TOTPCodeRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return domain.NewNotFoundError("User", userID.String())
+	}
+	if s.RequiresMFA(ctx, user.Role()) {
+		return domain.NewValidationError("two-factor authentication is required for your role")
+	}
+	if err := s.throttle.Check(ctx, user.Email(), ""); err != nil {
+		return err
+	}
+
+	factor, err := s.confirmedFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if factor == nil {
+		return domain.NewValidationError(identity.ErrTOTPNotEnabled.Error())
+	}
+	if !s.checkCode(ctx, factor, req.Code) {
+		s.throttle.RecordFailure(ctx, user.Email(), "", user)
+		return domain.NewValidationError("invalid authentication code")
+	}
+	s.throttle.RecordSuccess(ctx, user.Email())
+
+	if err := s.factorRepo.Delete(ctx, userID); err != nil {
+		s.logger.Error("failed to delete totp enrollment", zap.Error(err))
+		return fmt.Errorf("failed to delete totp enrollment: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventMFADisabled, userID, map[string]string{"method": "totp"}))
+	s.logger.Info("totp disabled", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// IsEnabled reports whether the user has a confirmed authenticator app.
+func (s *MFAService) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	factor, err := s.confirmedFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return factor != nil, nil
+}
+
+// VerifyCode checks a sign-in code against the user's authenticator app. Each
+// code is accepted at most once.
+func (s *MFAService) VerifyCode(ctx context.Context, userID uuid.UUID, code string) error {
+	factor, err := s.confirmedFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if factor == nil || !s.checkCode(ctx, factor, code) {
+		return domain.NewUnauthorizedError("invalid authentication code")
+	}
+	return nil
+}
+
+// confirmedFactor returns the user's confirmed enrollment, or nil if there is none.
+func (s *MFAService) confirmedFactor(ctx context.Context, userID uuid.UUID) (*identity.TOTPFactor, error) {
+	factor, err := s.factorRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		s.logger.Error("failed to load totp enrollment", zap.Error(err), zap.String("user_id", userID.String()))
+		return nil, fmt.Errorf("failed to load totp enrollment: %w", err)
+	}
+	if !factor.IsConfirmed() {
+		return nil, nil
+	}
+	return factor, nil
+}
+
+// checkCode validates code against the factor and records its time step so the
+// same code cannot be used twice.
+func (s *MFAService) checkCode(ctx context.Context, factor *identity.TOTPFactor, code string) bool {
+	step, ok := totp.Validate(factor.Secret(), code, time.Now().UTC(), totpSkew)
+	if !ok || factor.UseStep(step) != nil {
+		return false
+	}
+	if err := s.factorRepo.RecordUse(ctx, factor); err != nil {
+		if !errors.Is(err, identity.ErrTOTPCodeReused) {
+			s.logger.Error("failed to record totp use", zap.Error(err), zap.String("user_id", factor.UserID().String()))
+		}
+		return false
+	}
+	return true
+}
+
+// --- Policy ---
+
+// RequiresMFA reports whether users with the role must sign in with a second factor.
+// The policy is cached briefly; if it cannot be loaded at all, every role is treated
+// as required so an outage cannot silently lift the requirement.
+func (s *MFAService) RequiresMFA(ctx context.Context, role auth.UserRole) bool {
+	s.mu.RLock()
+	roles, loadedAt := s.requiredRoles, s.policyLoadedAt
+	s.mu.RUnlock()
+
+	if roles == nil || time.Since(loadedAt) > mfaPolicyCacheTTL {
+		loaded, err := s.loadPolicy(ctx)
+		switch {
+		case err == nil:
+			roles = loaded
+		case roles == nil:
+			s.logger.Error("failed to load mfa policy, requiring mfa", zap.Error(err))
+			return true
+		default:
+			s.logger.Warn("failed to refresh mfa policy, using cached policy", zap.Error(err))
+		}
+	}
+	return roles[role]
+}
+
+// GetPolicy returns the roles that must use two-factor authentication.
+func (s *MFAService) GetPolicy(ctx context.Context) (*MFAPolicyDTO, error) {
+	roles, err := s.policyRepo.ListRequiredRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mfa policy: %w", err)
+	}
+	return toMFAPolicyDTO(roles), nil
+}
+
+// UpdatePolicy replaces the roles that must use two-factor authentication. An admin
+// cannot require it for their own role before enabling it themselves, which would
+// lock them out of the admin endpoints.
+func (s *MFAService) UpdatePolicy(ctx context.Context, actorID uuid.UUID, req UpdateMFAPolicyRequest) (*MFAPolicyDTO, error) {
+	actor, err := s.userRepo.FindByID(ctx, actorID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("User", actorID.String())
+	}
+
+	required := make(map[auth.UserRole]bool, len(req.RequiredRoles))
+	roles := make([]auth.UserRole, 0, len(req.RequiredRoles))
+	for _, r := range req.RequiredRoles {
+		role := auth.UserRole(r)
+		if !required[role] {
+			required[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	if required[actor.Role()] {
+		enabled, err := s.IsEnabled(ctx, actorID)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, domain.NewValidationError("enable two-factor authentication on your own account before requiring it for your role")
+		}
+	}
+
+	if err := s.policyRepo.ReplaceRequiredRoles(ctx, roles, actorID); err != nil {
+		s.logger.Error("failed to update mfa policy", zap.Error(err))
+		return nil, fmt.Errorf("failed to update mfa policy: %w", err)
+	}
+	s.cachePolicy(required)
+
+	policy := toMFAPolicyDTO(roles)
+	s.publish(ctx, NewSecurityEvent(SecurityEventMFAPolicyChanged, actorID, map[string]string{
+		"required_roles": strings.Join(policy.RequiredRoles, ","),
+	}))
+	s.logger.Info("mfa policy updated", zap.String("actor_id", actorID.String()), zap.Strings("required_roles", policy.RequiredRoles))
+	return policy, nil
+}
+
+// loadPolicy reads the required roles from storage and refreshes the cache.
+func (s *MFAService) loadPolicy(ctx context.Context) (map[auth.UserRole]bool, error) {
+	roles, err := s.policyRepo.ListRequiredRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	required := make(map[auth.UserRole]bool, len(roles))
+	for _, role := range roles {
+		required[role] = true
+	}
+	s.cachePolicy(required)
+	return required, nil
+}
+
+func (s *MFAService) cachePolicy(required map[auth.UserRole]bool) {
+	s.mu.Lock()
+	s.requiredRoles = required
+	s.policyLoadedAt = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *MFAService) publish(ctx context.Context, event SecurityEvent) {
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+}
+
+// toMFAPolicyDTO converts a set of roles to an MFAPolicyDTO with the roles sorted.
+func toMFAPolicyDTO(roles []auth.UserRole) *MFAPolicyDTO {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	sort.Strings(names)
+	return &MFAPolicyDTO{RequiredRoles: names}
+}
@@ -2,14 +2,18 @@ package application
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-// PasswordResetNotifier sends password reset notifications.
+// PasswordResetNotifier sends password reset and password change notifications.
 // TODO: replace with Kafka-backed notifier publishing to identity.events when that topic exists.
 type PasswordResetNotifier interface {
 	SendPasswordResetEmail(ctx context.Context, email, token string) error
+	// SendPasswordChangedEmail tells the user their password was changed, so they can
+	// react if it was not them.
+	SendPasswordChangedEmail(ctx context.Context, email string) error
 }
 
 // LogOnlyPasswordResetNotifier is a stub notifier that logs the event without sending.
@@ -27,3 +31,183 @@ func (n *LogOnlyPasswordResetNotifier) SendPasswordResetEmail(ctx context.Contex
 	n.logger.Info("password reset email enqueued (log-only)", zap.String("email", email))
 	return nil
 }
+
+// SendPasswordChangedEmail logs the password changed email event without sending.
+func (n *LogOnlyPasswordResetNotifier) SendPasswordChangedEmail(ctx context.Context, email string) error {
+	n.logger.Info("password changed email enqueued (log-only)", zap.String("email", email))
+	return nil
+}
+
+// InvitationNotifier sends account invitation notifications.
+// TODO: replace with Kafka-backed notifier publishing to identity.events when that topic exists.
+type InvitationNotifier interface {
+	SendInvitationEmail(ctx context.Context, email, role, token string) error
+}
+
+// LogOnlyInvitationNotifier is a stub notifier that logs the event without sending.
+type LogOnlyInvitationNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogOnlyInvitationNotifier creates a new LogOnlyInvitationNotifier.
+func NewLogOnlyInvitationNotifier(logger *zap.Logger) *LogOnlyInvitationNotifier {
+	return &LogOnlyInvitationNotifier{logger: logger}
+}
+
+// SendInvitationEmail logs the invitation email event without sending.
+func (n *LogOnlyInvitationNotifier) SendInvitationEmail(ctx context.Context, email, role, token string) error {
+	n.logger.Info("invitation email enqueued (log-only)", zap.String("email", email), zap.String("role", role))
+	return nil
+}
+
+// EmailVerificationNotifier sends email address verification notifications.
+// TODO: replace with Kafka-backed notifier publishing to identity.events when that topic exists.
+type EmailVerificationNotifier interface {
+	SendVerificationEmail(ctx context.Context, email, token string) error
+	// SendRegistrationAttemptEmail tells the owner of a verified address that someone
+	// tried to create an account with it.
+	SendRegistrationAttemptEmail(ctx context.Context, email string) error
+}
+
+// LogOnlyEmailVerificationNotifier is a stub notifier that logs the event without sending.
+type LogOnlyEmailVerificationNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogOnlyEmailVerificationNotifier creates a new LogOnlyEmailVerificationNotifier.
+func NewLogOnlyEmailVerificationNotifier(logger *zap.Logger) *LogOnlyEmailVerificationNotifier {
+	return &LogOnlyEmailVerificationNotifier{logger: logger}
+}
+
+// SendVerificationEmail logs the verification email event without sending.
+func (n *LogOnlyEmailVerificationNotifier) SendVerificationEmail(ctx context.Context, email, token string) error {
+	n.logger.Info("verification email enqueued (log-only)", zap.String("email", email))
+	return nil
+}
+
+// SendRegistrationAttemptEmail logs the registration attempt email event without sending.
+func (n *LogOnlyEmailVerificationNotifier) SendRegistrationAttemptEmail(ctx context.Context, email string) error {
+	n.logger.Info("registration attempt email enqueued (log-only)", zap.String("email", email))
+	return nil
+}
+
+// RecoveryNotifier sends assisted account recovery links.
+// TODO: replace with Kafka-backed notifier publishing to identity.events when that topic exists.
+type RecoveryNotifier interface {
+	SendRecoveryEmail(ctx context.Context, email, token string) error
+}
+
+// LogOnlyRecoveryNotifier is a stub notifier that logs the event without sending.
+type LogOnlyRecoveryNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogOnlyRecoveryNotifier creates a new LogOnlyRecoveryNotifier.
+func NewLogOnlyRecoveryNotifier(logger *zap.Logger) *LogOnlyRecoveryNotifier {
+	return &LogOnlyRecoveryNotifier{logger: logger}
+}
+
+// SendRecoveryEmail logs the recovery email event without sending.
+func (n *LogOnlyRecoveryNotifier) SendRecoveryEmail(ctx context.Context, email, token string) error {
+	n.logger.Info("recovery email enqueued (log-only)", zap.String("email", email))
+	return nil
+}
+
+// EmailChangeNotifier sends the messages of an email address change.
+// TODO: replace with Kafka-backed notifier publishing to identity.events when that topic exists.
+type EmailChangeNotifier interface {
+	// SendEmailChangeConfirmationEmail sends the confirm link to the new address.
+	SendEmailChangeConfirmationEmail(ctx context.Context, newEmail, token string) error
+	// SendEmailChangeRequestedEmail tells the current address about the request, with
+	// a link to cancel it.
+	SendEmailChangeRequestedEmail(ctx context.Context, oldEmail, newEmail, cancelToken string) error
+	// SendEmailChangedEmail tells the previous address the account has moved.
+	SendEmailChangedEmail(ctx context.Context, oldEmail, newEmail string) error
+	// SendEmailChangeAddressTakenEmail tells an address that already has an account
+	// that someone asked to move another account to it.
+	SendEmailChangeAddressTakenEmail(ctx context.Context, email string) error
+}
+
+// LogOnlyEmailChangeNotifier is a stub notifier that logs the event without sending.
+type LogOnlyEmailChangeNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogOnlyEmailChangeNotifier creates a new LogOnlyEmailChangeNotifier.
+func NewLogOnlyEmailChangeNotifier(logger *zap.Logger) *LogOnlyEmailChangeNotifier {
+	return &LogOnlyEmailChangeNotifier{logger: logger}
+}
+
+// SendEmailChangeConfirmationEmail logs the confirmation email event without sending.
+func (n *LogOnlyEmailChangeNotifier) SendEmailChangeConfirmationEmail(ctx context.Context, newEmail, token string) error {
+	n.logger.Info("email change confirmation email enqueued (log-only)", zap.String("email", newEmail))
+	return nil
+}
+
+// SendEmailChangeRequestedEmail logs the change requested email event without sending.
+func (n *LogOnlyEmailChangeNotifier) SendEmailChangeRequestedEmail(ctx context.Context, oldEmail, newEmail, cancelToken string) error {
+	n.logger.Info("email change requested email enqueued (log-only)", zap.String("email", oldEmail))
+	return nil
+}
+
+// SendEmailChangedEmail logs the email changed email event without sending.
+func (n *LogOnlyEmailChangeNotifier) SendEmailChangedEmail(ctx context.Context, oldEmail, newEmail string) error {
+	n.logger.Info("email changed email enqueued (log-only)", zap.String("email", oldEmail))
+	return nil
+}
+
+// SendEmailChangeAddressTakenEmail logs the address taken email event without sending.
+func (n *LogOnlyEmailChangeNotifier) SendEmailChangeAddressTakenEmail(ctx context.Context, email string) error {
+	n.logger.Info("email change address taken email enqueued (log-only)", zap.String("email", email))
+	return nil
+}
+
+// AccountLockedNotifier tells users their account was locked after repeated failed sign-ins.
+// TODO: replace with Kafka-backed notifier publishing to identity.events when that topic exists.
+type AccountLockedNotifier interface {
+	SendAccountLockedEmail(ctx context.Context, email string, lockedUntil time.Time) error
+}
+
+// LogOnlyAccountLockedNotifier is a stub notifier that logs the event without sending.
+type LogOnlyAccountLockedNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogOnlyAccountLockedNotifier creates a new LogOnlyAccountLockedNotifier.
+func NewLogOnlyAccountLockedNotifier(logger *zap.Logger) *LogOnlyAccountLockedNotifier {
+	return &LogOnlyAccountLockedNotifier{logger: logger}
+}
+
+// SendAccountLockedEmail logs the account locked email event without sending.
+func (n *LogOnlyAccountLockedNotifier) SendAccountLockedEmail(ctx context.Context, email string, lockedUntil time.Time) error {
+	n.logger.Info("account locked email enqueued (log-only)", zap.String("email", email), zap.Time("locked_until", lockedUntil))
+	return nil
+}
+
+// SMSSender delivers text messages to phone numbers.
+// TODO: replace with an SMS gateway integration.
+type SMSSender interface {
+	SendSMS(ctx context.Context, phone, message string) error
+}
+
+// LogOnlySMSSender is a stub sender that logs messages without delivering them.
+// With logContent set (development only) the message body, including any code, is logged too.
+type LogOnlySMSSender struct {
+	logger     *zap.Logger
+	logContent bool
+}
+
+// NewLogOnlySMSSender creates a new LogOnlySMSSender.
+func NewLogOnlySMSSender(logger *zap.Logger, logContent bool) *LogOnlySMSSender {
+	return &LogOnlySMSSender{logger: logger, logContent: logContent}
+}
+
+// SendSMS logs the SMS event without sending.
+func (n *LogOnlySMSSender) SendSMS(ctx context.Context, phone, message string) error {
+	fields := []zap.Field{zap.String("phone", phone)}
+	if n.logContent {
+		fields = append(fields, zap.String("message", message))
+	}
+	n.logger.Info("sms enqueued (log-only)", fields...)
+	return nil
+}
@@ -0,0 +1,338 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Grants the token endpoint supports.
+const (
+	// GrantTypeClientCredentials issues a service a token of its own; see OAuthClientService.
+	GrantTypeClientCredentials = "client_credentials"
+	// GrantTypeAuthorizationCode redeems an OpenID Connect authorization code; see OIDCService.
+	GrantTypeAuthorizationCode = "authorization_code"
+)
+
+// ScopeIntrospect lets an OAuth client call the token introspection endpoint. Other
+// services are registered as OAuth clients with this scope to check user tokens.
+const ScopeIntrospect = "introspect"
+
+// oauthClientIDPrefix marks client IDs so they are recognisable in logs and configs.
+const oauthClientIDPrefix = "svc_"
+
+// OAuth2 error codes (RFC 6749 sections 4.1.2.1 and 5.2).
+const (
+	OAuthErrorInvalidRequest       = "invalid_request"
+	OAuthErrorInvalidClient        = "invalid_client"
+	OAuthErrorInvalidGrant         = "invalid_grant"
+	OAuthErrorUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrorInvalidScope         = "invalid_scope"
+	// OAuthErrorUnauthorizedClient is reported when an authenticated client lacks the
+	// scope an endpoint requires.
+	OAuthErrorUnauthorizedClient = "unauthorized_client"
+	// OAuthErrorUnsupportedResponseType is only reported by the authorization endpoint.
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+)
+
+// OAuthError is a token endpoint error, answered in the form RFC 6749 requires rather
+// than in the response envelope.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+// Error implements the error interface.
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// ClientTokenRequest is a token endpoint request. The handler fills in the client
+// credentials from HTTP Basic authentication when the caller uses it. Code,
+// RedirectURI and CodeVerifier are only used by the authorization code grant.
+type ClientTokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// ClientTokenResponse is a successful token response (RFC 6749 section 5.1). The
+// authorization code grant adds a refresh token and an ID token.
+type ClientTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// CreateOAuthClientRequest represents an admin registering a service or web app as an
+// OAuth client. Only clients with redirect URIs can sign users in with OpenID Connect.
+type CreateOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Scopes       []string `json:"scopes" binding:"required,min=1"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
+
+// OAuthClientDTO represents an OAuth client in API responses. The secret is never included.
+type OAuthClientDTO struct {
+	ID              uuid.UUID  `json:"id"`
+	ClientID        string     `json:"client_id"`
+	Name            string     `json:"name"`
+	Scopes          []string   `json:"scopes"`
+	RedirectURIs    []string   `json:"redirect_uris"`
+	Status          string     `json:"status"`
+	CreatedBy       uuid.UUID  `json:"created_by"`
+	SecretRotatedAt time.Time  `json:"secret_rotated_at"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// OAuthClientSecretDTO is an OAuth client with its new secret, returned only when the
+// client is created or its secret rotated. The secret cannot be retrieved later.
+type OAuthClientSecretDTO struct {
+	OAuthClientDTO
+	ClientSecret string `json:"client_secret"`
+}
+
+// OAuthClientService manages the services registered as OAuth clients and issues them
+// access tokens of their own with the client credentials grant.
+type OAuthClientService struct {
+	clientRepo identity.OAuthClientRepository
+	tokens     *tokens.Manager
+	events     SecurityEventPublisher
+	logger     *zap.Logger
+}
+
+// NewOAuthClientService creates a new OAuthClientService.
+func NewOAuthClientService(
+	clientRepo identity.OAuthClientRepository,
+	tokenManager *tokens.Manager,
+	events SecurityEventPublisher,
+	logger *zap.Logger,
+) *OAuthClientService {
+	return &OAuthClientService{
+		clientRepo: clientRepo,
+		tokens:     tokenManager,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+// CreateClient registers a new client allowed the requested scopes and returns it with
+// its secret.
+func (s *OAuthClientService) CreateClient(ctx context.Context, actorID uuid.UUID, req CreateOAuthClientRequest) (*OAuthClientSecretDTO, error) {
+	clientID, err := generateClientID()
+	if err != nil {
+		s.logger.Error("failed to generate client ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate client ID: %w", err)
+	}
+	secret, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate client secret", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate client secret: %w", err)
+	}
+
+	client, err := identity.NewOAuthClient(clientID, req.Name, secret, req.Scopes, req.RedirectURIs, actorID)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		s.logger.Error("failed to save oauth client", zap.Error(err))
+		return nil, fmt.Errorf("failed to save oauth client: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventOAuthClientCreated, actorID, map[string]string{
+		"client_id": client.ClientID(),
+		"scopes":    strings.Join(client.Scopes(), " "),
+	}))
+	s.logger.Info("oauth client created", zap.String("client_id", client.ClientID()), zap.String("created_by", actorID.String()))
+	return &OAuthClientSecretDTO{OAuthClientDTO: toOAuthClientDTO(client), ClientSecret: secret}, nil
+}
+
+// ListClients returns every registered client.
+func (s *OAuthClientService) ListClients(ctx context.Context) ([]OAuthClientDTO, error) {
+	clients, err := s.clientRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
+	}
+	dtos := make([]OAuthClientDTO, len(clients))
+	for i, client := range clients {
+		dtos[i] = toOAuthClientDTO(client)
+	}
+	return dtos, nil
+}
+
+// RotateSecret gives a client a new secret and returns it. The old secret stops
+// working at once; tokens already issued stay valid until they expire.
+func (s *OAuthClientService) RotateSecret(ctx context.Context, actorID, id uuid.UUID) (*OAuthClientSecretDTO, error) {
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, domain.NewNotFoundError("OAuthClient", id.String())
+	}
+	secret, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate client secret", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate client secret: %w", err)
+	}
+	if err := client.RotateSecret(secret); err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		s.logger.Error("failed to rotate oauth client secret", zap.Error(err))
+		return nil, fmt.Errorf("failed to rotate oauth client secret: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventOAuthClientSecretRotated, actorID, map[string]string{
+		"client_id": client.ClientID(),
+	}))
+	s.logger.Info("oauth client secret rotated", zap.String("client_id", client.ClientID()), zap.String("rotated_by", actorID.String()))
+	return &OAuthClientSecretDTO{OAuthClientDTO: toOAuthClientDTO(client), ClientSecret: secret}, nil
+}
+
+// DisableClient stops a client from obtaining new tokens.
+func (s *OAuthClientService) DisableClient(ctx context.Context, actorID, id uuid.UUID) error {
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.NewNotFoundError("OAuthClient", id.String())
+	}
+	if err := client.Disable(); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		s.logger.Error("failed to disable oauth client", zap.Error(err))
+		return fmt.Errorf("failed to disable oauth client: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventOAuthClientDisabled, actorID, map[string]string{
+		"client_id": client.ClientID(),
+	}))
+	s.logger.Info("oauth client disabled", zap.String("client_id", client.ClientID()), zap.String("disabled_by", actorID.String()))
+	return nil
+}
+
+// IssueToken handles a client credentials grant: it authenticates the client and
+// issues an access token for the requested scopes, or every allowed scope if none
+// are requested. Unknown, wrongly authenticated and disabled clients all get
+// invalid_client so the answer does not reveal which client IDs exist.
+func (s *OAuthClientService) IssueToken(ctx context.Context, req ClientTokenRequest) (*ClientTokenResponse, error) {
+	if req.GrantType == "" {
+		return nil, &OAuthError{Code: OAuthErrorInvalidRequest, Description: "grant_type is required"}
+	}
+	if req.GrantType != GrantTypeClientCredentials {
+		return nil, &OAuthError{Code: OAuthErrorUnsupportedGrantType, Description: "only client_credentials is supported"}
+	}
+
+	client, err := authenticateClient(ctx, s.clientRepo, req, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := client.GrantScopes(strings.Fields(req.Scope))
+	if err != nil {
+		return nil, &OAuthError{Code: OAuthErrorInvalidScope, Description: err.Error()}
+	}
+
+	accessToken, expiresAt, err := s.tokens.GenerateClientToken(client.ClientID(), scopes)
+	if err != nil {
+		s.logger.Error("failed to generate client token", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate client token: %w", err)
+	}
+
+	s.logger.Info("client token issued", zap.String("client_id", client.ClientID()), zap.Strings("scopes", scopes))
+	return &ClientTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// AuthenticateIntrospectionClient authenticates a caller of the token introspection
+// endpoint with its OAuth client credentials. Unknown, wrongly authenticated and
+// disabled clients get invalid_client, as at the token endpoint; an active client
+// without the introspect scope gets unauthorized_client.
+func (s *OAuthClientService) AuthenticateIntrospectionClient(ctx context.Context, clientID, secret string) error {
+	client, err := authenticateClient(ctx, s.clientRepo, ClientTokenRequest{ClientID: clientID, ClientSecret: secret}, s.logger)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(client.Scopes(), ScopeIntrospect) {
+		s.logger.Warn("oauth client without the introspect scope called introspection", zap.String("client_id", client.ClientID()))
+		return &OAuthError{Code: OAuthErrorUnauthorizedClient, Description: "client is not allowed to introspect tokens"}
+	}
+	return nil
+}
+
+func (s *OAuthClientService) publish(ctx context.Context, event SecurityEvent) {
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+}
+
+// authenticateClient checks the client credentials of a token request. Unknown,
+// wrongly authenticated and disabled clients all get invalid_client.
+func authenticateClient(ctx context.Context, clientRepo identity.OAuthClientRepository, req ClientTokenRequest, logger *zap.Logger) (*identity.OAuthClient, error) {
+	if req.ClientID == "" || req.ClientSecret == "" {
+		return nil, &OAuthError{Code: OAuthErrorInvalidClient, Description: "client authentication failed"}
+	}
+
+	client, err := clientRepo.FindByCredentials(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			logger.Warn("oauth client authentication failed", zap.String("client_id", req.ClientID))
+			return nil, &OAuthError{Code: OAuthErrorInvalidClient, Description: "client authentication failed"}
+		}
+		logger.Error("failed to load oauth client", zap.Error(err))
+		return nil, fmt.Errorf("failed to load oauth client: %w", err)
+	}
+	if !client.IsActive() {
+		logger.Warn("disabled oauth client asked for a token", zap.String("client_id", client.ClientID()))
+		return nil, &OAuthError{Code: OAuthErrorInvalidClient, Description: "client authentication failed"}
+	}
+	return client, nil
+}
+
+// generateClientID returns a new random client ID with the svc_ prefix.
+func generateClientID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return oauthClientIDPrefix + hex.EncodeToString(b), nil
+}
+
+func toOAuthClientDTO(client *identity.OAuthClient) OAuthClientDTO {
+	status := "active"
+	if !client.IsActive() {
+		status = "disabled"
+	}
+	return OAuthClientDTO{
+		ID:              client.ID(),
+		ClientID:        client.ClientID(),
+		Name:            client.Name(),
+		Scopes:          client.Scopes(),
+		RedirectURIs:    client.RedirectURIs(),
+		Status:          status,
+		CreatedBy:       client.CreatedBy(),
+		SecretRotatedAt: client.SecretRotatedAt(),
+		DisabledAt:      client.DisabledAt(),
+		CreatedAt:       client.CreatedAt(),
+	}
+}
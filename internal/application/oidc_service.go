@@ -0,0 +1,428 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OpenID Connect scopes (OpenID Connect Core section 5.4).
+const (
+	// ScopeOpenID marks an OpenID Connect request; every authorization request needs it.
+	ScopeOpenID = "openid"
+	// ScopeProfile adds the user's name and picture to the ID token.
+	ScopeProfile = "profile"
+	// ScopeEmail adds the user's email address to the ID token.
+	ScopeEmail = "email"
+)
+
+// ResponseTypeCode is the only response type the authorization endpoint supports.
+const ResponseTypeCode = "code"
+
+// CodeChallengeMethodS256 is the only PKCE method accepted (RFC 7636 section 4.2).
+const CodeChallengeMethodS256 = "S256"
+
+// maxNonceLength is the length of the authorization_codes.nonce column; longer nonces
+// are refused rather than failing when the code is stored.
+const maxNonceLength = 255
+
+// authorizationCodeLifetime is how long a client has to redeem an authorization code.
+const authorizationCodeLifetime = time.Minute
+
+// oidcSessionPlatform is the platform recorded on sessions started through OpenID Connect.
+const oidcSessionPlatform = "oidc"
+
+// ErrInvalidAuthorizationClient is returned for authorization requests naming an
+// unknown or disabled client, or a redirect URI the client has not registered. Such
+// requests are answered to the user directly and never redirected.
+var ErrInvalidAuthorizationClient = errors.New("unknown client or unregistered redirect_uri")
+
+// AuthorizeRequest is an OpenID Connect authorization request, from the client's
+// redirect to the authorization endpoint, plus what the user entered on the sign-in
+// page: email and password, then MFAToken and OTP if two-factor authentication is on.
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type"`
+	ClientID            string `form:"client_id"`
+	RedirectURI         string `form:"redirect_uri"`
+	Scope               string `form:"scope"`
+	State               string `form:"state"`
+	Nonce               string `form:"nonce"`
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"`
+	Email               string `form:"email"`
+	Password            string `form:"password"`
+	MFAToken            string `form:"mfa_token"`
+	OTP                 string `form:"otp"`
+}
+
+// AuthorizationError is an error in an authorization request that is reported to the
+// client at its redirect URI (RFC 6749 section 4.1.2.1).
+type AuthorizationError struct {
+	Code        string
+	Description string
+	RedirectURI string
+	State       string
+}
+
+// Error implements the error interface.
+func (e *AuthorizationError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// RedirectURL returns the redirect URI with the error added to its query.
+func (e *AuthorizationError) RedirectURL() string {
+	params := url.Values{"error": {e.Code}, "error_description": {e.Description}}
+	if e.State != "" {
+		params.Set("state", e.State)
+	}
+	return withQuery(e.RedirectURI, params)
+}
+
+// AuthorizationDTO describes a valid authorization request to the sign-in page.
+type AuthorizationDTO struct {
+	ClientName string
+	Scopes     []string
+}
+
+// AuthorizeResult is the outcome of a sign-in on the authorization endpoint: an MFA
+// challenge to complete, or the URL to send the user back to with the code.
+type AuthorizeResult struct {
+	MFARequired bool
+	MFAToken    string
+	RedirectURL string
+}
+
+// UserInfo holds the claims returned by the userinfo endpoint (OpenID Connect Core section 5.3).
+type UserInfo struct {
+	Subject             string `json:"sub"`
+	Email               string `json:"email"`
+	EmailVerified       bool   `json:"email_verified"`
+	Name                string `json:"name,omitempty"`
+	Picture             string `json:"picture,omitempty"`
+	PhoneNumber         string `json:"phone_number,omitempty"`
+	PhoneNumberVerified bool   `json:"phone_number_verified"`
+	Role                string `json:"role"`
+}
+
+// OIDCDiscovery is the OpenID Provider metadata document (OpenID Connect Discovery section 3).
+type OIDCDiscovery struct {
+	Issuer                                     string   `json:"issuer"`
+	AuthorizationEndpoint                      string   `json:"authorization_endpoint"`
+	TokenEndpoint                              string   `json:"token_endpoint"`
+	UserInfoEndpoint                           string   `json:"userinfo_endpoint"`
+	JWKSURI                                    string   `json:"jwks_uri"`
+	ScopesSupported                            []string `json:"scopes_supported"`
+	ResponseTypesSupported                     []string `json:"response_types_supported"`
+	GrantTypesSupported                        []string `json:"grant_types_supported"`
+	SubjectTypesSupported                      []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported           []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported          []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported              []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                            []string `json:"claims_supported"`
+	AuthorizationResponseIssParameterSupported bool     `json:"authorization_response_iss_parameter_supported"`
+}
+
+// OIDCService makes the service an OpenID Connect provider for registered clients with
+// redirect URIs, such as the admin dashboard and the shop portal: the authorization
+// code flow with PKCE, ID tokens, discovery and userinfo. Users sign in with the same
+// credentials, throttling and two-factor rules as on the login API, and the tokens a
+// client receives are the service's ordinary access and refresh tokens.
+type OIDCService struct {
+	clientRepo identity.OAuthClientRepository
+	codeRepo   identity.AuthorizationCodeRepository
+	userRepo   identity.UserRepository
+	auth       *AuthService
+	tokens     *tokens.Manager
+	events     SecurityEventPublisher
+	issuer     string
+	logger     *zap.Logger
+}
+
+// NewOIDCService creates a new OIDCService. issuer is the provider's public base URL;
+// it is the "iss" of ID tokens and the base of every endpoint URL in discovery.
+func NewOIDCService(
+	clientRepo identity.OAuthClientRepository,
+	codeRepo identity.AuthorizationCodeRepository,
+	userRepo identity.UserRepository,
+	authService *AuthService,
+	tokenManager *tokens.Manager,
+	events SecurityEventPublisher,
+	issuer string,
+	logger *zap.Logger,
+) *OIDCService {
+	return &OIDCService{
+		clientRepo: clientRepo,
+		codeRepo:   codeRepo,
+		userRepo:   userRepo,
+		auth:       authService,
+		tokens:     tokenManager,
+		events:     events,
+		issuer:     strings.TrimRight(issuer, "/"),
+		logger:     logger,
+	}
+}
+
+// Discovery returns the provider metadata.
+func (s *OIDCService) Discovery() *OIDCDiscovery {
+	return &OIDCDiscovery{
+		Issuer:                            s.issuer,
+		AuthorizationEndpoint:             s.issuer + "/oauth/authorize",
+		TokenEndpoint:                     s.issuer + "/oauth/token",
+		UserInfoEndpoint:                  s.issuer + "/oauth/userinfo",
+		JWKSURI:                           s.issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   []string{ScopeOpenID, ScopeProfile, ScopeEmail},
+		ResponseTypesSupported:            []string{ResponseTypeCode},
+		GrantTypesSupported:               []string{GrantTypeAuthorizationCode, GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{s.tokens.SigningAlgorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{CodeChallengeMethodS256},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "amr", "sid",
+			"email", "email_verified", "name", "picture",
+		},
+		AuthorizationResponseIssParameterSupported: true,
+	}
+}
+
+// ValidateAuthorization checks an authorization request before the sign-in page is
+// shown. It returns ErrInvalidAuthorizationClient, which must not be redirected, or an
+// AuthorizationError to report at the redirect URI.
+func (s *OIDCService) ValidateAuthorization(ctx context.Context, req AuthorizeRequest) (*AuthorizationDTO, error) {
+	client, scopes, err := s.checkAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizationDTO{ClientName: client.Name(), Scopes: scopes}, nil
+}
+
+// Authorize signs the user in for an authorization request. Without two-factor
+// authentication, or once its code is given, it issues an authorization code and
+// returns the redirect URI to send it to.
+func (s *OIDCService) Authorize(ctx context.Context, req AuthorizeRequest, client ClientInfo) (*AuthorizeResult, error) {
+	oauthClient, scopes, err := s.checkAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var authn *Authentication
+	if req.MFAToken != "" {
+		authn, err = s.auth.AuthenticateMFA(ctx, MFAVerifyRequest{MFAToken: req.MFAToken, Code: req.OTP}, client)
+	} else {
+		authn, err = s.auth.Authenticate(ctx, LoginRequest{Email: req.Email, Password: req.Password}, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if authn.MFARequired {
+		return &AuthorizeResult{MFARequired: true, MFAToken: authn.MFAToken}, nil
+	}
+
+	raw, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate authorization code", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate authorization code: %w", err)
+	}
+	code := identity.NewAuthorizationCode(raw, oauthClient.ClientID(), authn.UserID, req.RedirectURI, scopes,
+		req.Nonce, req.CodeChallenge, authn.AuthMethods, authn.AuthTime, authn.AuthTime.Add(authorizationCodeLifetime))
+	if err := s.codeRepo.Create(ctx, code); err != nil {
+		s.logger.Error("failed to save authorization code", zap.Error(err))
+		return nil, fmt.Errorf("failed to save authorization code: %w", err)
+	}
+
+	s.logger.Info("authorization code issued", zap.String("client_id", oauthClient.ClientID()), zap.String("user_id", authn.UserID.String()))
+	params := url.Values{"code": {raw}, "iss": {s.issuer}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return &AuthorizeResult{RedirectURL: withQuery(req.RedirectURI, params)}, nil
+}
+
+// checkAuthorization validates an authorization request and returns its client and
+// the scopes to grant. The client and redirect URI are checked first, as errors can
+// only be redirected to a redirect URI the client registered.
+func (s *OIDCService) checkAuthorization(ctx context.Context, req AuthorizeRequest) (*identity.OAuthClient, []string, error) {
+	client, err := s.clientRepo.FindByClientID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, ErrInvalidAuthorizationClient
+		}
+		s.logger.Error("failed to load oauth client", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to load oauth client: %w", err)
+	}
+	if !client.IsActive() || !client.HasRedirectURI(req.RedirectURI) {
+		s.logger.Warn("authorization request rejected", zap.String("client_id", req.ClientID), zap.String("redirect_uri", req.RedirectURI))
+		return nil, nil, ErrInvalidAuthorizationClient
+	}
+
+	fail := func(code, description string) error {
+		return &AuthorizationError{Code: code, Description: description, RedirectURI: req.RedirectURI, State: req.State}
+	}
+	if req.ResponseType != ResponseTypeCode {
+		return nil, nil, fail(OAuthErrorUnsupportedResponseType, "only the code response type is supported")
+	}
+	if req.CodeChallengeMethod != CodeChallengeMethodS256 || len(req.CodeChallenge) != 43 {
+		return nil, nil, fail(OAuthErrorInvalidRequest, "a PKCE code_challenge with method S256 is required")
+	}
+	if len(req.Nonce) > maxNonceLength {
+		return nil, nil, fail(OAuthErrorInvalidRequest, fmt.Sprintf("nonce must be at most %d characters", maxNonceLength))
+	}
+	requested := strings.Fields(req.Scope)
+	if !slices.Contains(requested, ScopeOpenID) {
+		return nil, nil, fail(OAuthErrorInvalidScope, "the openid scope is required")
+	}
+	scopes, err := client.GrantScopes(requested)
+	if err != nil {
+		return nil, nil, fail(OAuthErrorInvalidScope, err.Error())
+	}
+	return client, scopes, nil
+}
+
+// ExchangeCode handles an authorization code grant: it authenticates the client,
+// redeems the code and starts a session for the user, returning its access and
+// refresh tokens with an ID token. A code presented a second time ends the session
+// its first redemption started (RFC 6749 section 4.1.2).
+func (s *OIDCService) ExchangeCode(ctx context.Context, req ClientTokenRequest, client ClientInfo) (*ClientTokenResponse, error) {
+	oauthClient, err := authenticateClient(ctx, s.clientRepo, req, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	if req.Code == "" {
+		return nil, &OAuthError{Code: OAuthErrorInvalidRequest, Description: "code is required"}
+	}
+	invalidGrant := &OAuthError{Code: OAuthErrorInvalidGrant, Description: "authorization code is invalid or expired"}
+
+	code, err := s.codeRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, invalidGrant
+		}
+		s.logger.Error("failed to load authorization code", zap.Error(err))
+		return nil, fmt.Errorf("failed to load authorization code: %w", err)
+	}
+	if code.IsConsumed() && code.ClientID() == oauthClient.ClientID() {
+		s.handleCodeReuse(ctx, code)
+		return nil, invalidGrant
+	}
+	if err := code.CheckRedemption(oauthClient.ClientID(), req.RedirectURI, req.CodeVerifier); err != nil {
+		s.logger.Info("authorization code rejected", zap.String("client_id", oauthClient.ClientID()), zap.Error(err))
+		return nil, invalidGrant
+	}
+	if err := s.codeRepo.Consume(ctx, code.ID()); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, invalidGrant
+		}
+		s.logger.Error("failed to consume authorization code", zap.Error(err))
+		return nil, fmt.Errorf("failed to consume authorization code: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, code.UserID())
+	if err != nil || user.CanAuthenticate() != nil {
+		return nil, &OAuthError{Code: OAuthErrorInvalidGrant, Description: "the user can no longer sign in"}
+	}
+	session, err := s.auth.StartAuthorizedSession(ctx, user.ID(), code.AuthMethods(), ClientInfo{
+		DeviceName: oauthClient.Name(),
+		Platform:   oidcSessionPlatform,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codeRepo.AttachSession(ctx, code.ID(), session.SessionID); err != nil {
+		s.logger.Warn("failed to record authorization code session", zap.Error(err), zap.String("code_id", code.ID().String()))
+	}
+
+	subject := tokens.IDTokenSubject{
+		UserID:      user.ID(),
+		ClientID:    oauthClient.ClientID(),
+		SessionID:   session.SessionID,
+		Nonce:       code.Nonce(),
+		AuthTime:    code.AuthTime(),
+		AuthMethods: code.AuthMethods(),
+	}
+	if slices.Contains(code.Scopes(), ScopeEmail) {
+		verified := user.IsVerified()
+		subject.Email = user.Email()
+		subject.EmailVerified = &verified
+	}
+	if slices.Contains(code.Scopes(), ScopeProfile) {
+		subject.Name = user.FullName()
+		subject.Picture = user.AvatarURL()
+	}
+	idToken, err := s.tokens.GenerateIDToken(s.issuer, subject)
+	if err != nil {
+		s.logger.Error("failed to generate id token", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate id token: %w", err)
+	}
+
+	s.logger.Info("authorization code redeemed", zap.String("client_id", oauthClient.ClientID()), zap.String("user_id", user.ID().String()))
+	return &ClientTokenResponse{
+		AccessToken:  session.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.tokens.AccessExpiry().Seconds()),
+		Scope:        strings.Join(code.Scopes(), " "),
+		RefreshToken: session.RefreshToken,
+		IDToken:      idToken,
+	}, nil
+}
+
+// handleCodeReuse ends the session started with a replayed authorization code, whose
+// tokens may have leaked with it, and emits a security event. Errors are logged rather
+// than returned because the caller rejects the request regardless.
+func (s *OIDCService) handleCodeReuse(ctx context.Context, code *identity.AuthorizationCode) {
+	s.logger.Warn("authorization code reused", zap.String("client_id", code.ClientID()), zap.String("user_id", code.UserID().String()))
+	metadata := map[string]string{"client_id": code.ClientID()}
+	if sessionID := code.SessionID(); sessionID != nil {
+		if err := s.auth.RevokeSession(ctx, code.UserID(), *sessionID); err != nil {
+			s.logger.Error("failed to end session of reused authorization code", zap.Error(err), zap.String("session_id", sessionID.String()))
+		}
+		metadata["session_id"] = sessionID.String()
+	}
+	if err := s.events.Publish(ctx, NewSecurityEvent(SecurityEventAuthorizationCodeReuse, code.UserID(), metadata)); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(SecurityEventAuthorizationCodeReuse)))
+	}
+}
+
+// UserInfo returns the claims about the user an access token was issued to.
+func (s *OIDCService) UserInfo(ctx context.Context, userID uuid.UUID) (*UserInfo, error) {
+	profile, err := s.auth.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		Subject:             profile.ID.String(),
+		Email:               profile.Email,
+		EmailVerified:       profile.IsVerified,
+		Name:                profile.FullName,
+		Picture:             profile.AvatarURL,
+		PhoneNumber:         profile.Phone,
+		PhoneNumberVerified: profile.PhoneVerified,
+		Role:                profile.Role,
+	}, nil
+}
+
+// withQuery returns rawURL with params added to its query string. rawURL is a
+// registered redirect URI, so it parses.
+func withQuery(rawURL string, params url.Values) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
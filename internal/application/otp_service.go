@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// otpDigits is the length of SMS codes.
+	otpDigits = 6
+	// otpTTL is how long an SMS code stays valid.
+	otpTTL = 5 * time.Minute
+	// otpMaxAttempts is how many wrong guesses a single code tolerates.
+	otpMaxAttempts = 5
+	// otpResendCooldown is the minimum gap between two codes to the same number.
+	otpResendCooldown = time.Minute
+	// otpDailyLimit caps codes per number and purpose per rolling day.
+	otpDailyLimit = 10
+)
+
+// SecretHasher derives a keyed, deterministic digest of a short-lived secret.
+type SecretHasher interface {
+	Hash(secret string) string
+}
+
+// OTPService issues and checks one-time SMS codes. It owns generation, hashing,
+// throttling and attempt counting; callers decide what a redeemed code unlocks.
+type OTPService struct {
+	otpRepo identity.PhoneOTPRepository
+	hasher  SecretHasher
+	sms     SMSSender
+	logger  *zap.Logger
+}
+
+// NewOTPService creates a new OTPService.
+func NewOTPService(otpRepo identity.PhoneOTPRepository, hasher SecretHasher, sms SMSSender, logger *zap.Logger) *OTPService {
+	return &OTPService{
+		otpRepo: otpRepo,
+		hasher:  hasher,
+		sms:     sms,
+		logger:  logger,
+	}
+}
+
+// Send issues a new code for the phone and purpose and texts it, subject to the
+// resend cooldown and daily limit. userID may be nil for codes not tied to an account.
+func (s *OTPService) Send(ctx context.Context, phone string, purpose identity.OTPPurpose, userID *uuid.UUID) error {
+	return s.issue(ctx, phone, purpose, userID, true)
+}
+
+// SendDecoy goes through the same throttling and persistence as Send but never texts
+// the code. Flows that must not reveal whether a number is registered use it for
+// unknown numbers so both cases look and rate-limit the same.
+func (s *OTPService) SendDecoy(ctx context.Context, phone string, purpose identity.OTPPurpose) error {
+	return s.issue(ctx, phone, purpose, nil, false)
+}
+
+func (s *OTPService) issue(ctx context.Context, phone string, purpose identity.OTPPurpose, userID *uuid.UUID, deliver bool) error {
+	now := time.Now().UTC()
+	recent, err := s.otpRepo.CountCreatedSince(ctx, phone, purpose, now.Add(-otpResendCooldown))
+	if err != nil {
+		return fmt.Errorf("failed to check code throttle: %w", err)
+	}
+	if recent > 0 {
+		return NewRateLimitedError("a code was sent recently", otpResendCooldown)
+	}
+
+	daily, err := s.otpRepo.CountCreatedSince(ctx, phone, purpose, now.Add(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to check code throttle: %w", err)
+	}
+	if daily >= otpDailyLimit {
+		return NewRateLimitedError("too many codes requested", 24*time.Hour)
+	}
+
+	code, err := generateNumericCode(otpDigits)
+	if err != nil {
+		s.logger.Error("failed to generate code", zap.Error(err))
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+
+	otp := identity.NewPhoneOTP(userID, phone, purpose, s.hasher.Hash(code), otpMaxAttempts, now.Add(otpTTL))
+	if err := s.otpRepo.Create(ctx, otp); err != nil {
+		s.logger.Error("failed to persist code", zap.Error(err))
+		return fmt.Errorf("failed to persist code: %w", err)
+	}
+
+	if !deliver {
+		return nil
+	}
+
+	message := fmt.Sprintf("Your Kilat code is %s. It expires in %d minutes. Never share it.", code, int(otpTTL.Minutes()))
+	if err := s.sms.SendSMS(ctx, phone, message); err != nil {
+		s.logger.Error("failed to send code", zap.Error(err))
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+	return nil
+}
+
+// Redeem checks a code against the latest one issued for the phone and purpose and
+// consumes it on success. Wrong guesses count against the code's attempt limit.
+func (s *OTPService) Redeem(ctx context.Context, phone string, purpose identity.OTPPurpose, code string) (*identity.PhoneOTP, error) {
+	otp, err := s.otpRepo.FindLatest(ctx, phone, purpose)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewValidationError("no code was requested; request a new code")
+		}
+		return nil, fmt.Errorf("failed to look up code: %w", err)
+	}
+	if otp.IsConsumed() || otp.IsExpired() {
+		return nil, domain.NewValidationError("code expired; request a new code")
+	}
+	if otp.AttemptsExhausted() {
+		return nil, domain.NewValidationError("too many wrong attempts; request a new code")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(s.hasher.Hash(code)), []byte(otp.CodeHash())) != 1 {
+		if err := s.otpRepo.RecordFailedAttempt(ctx, otp.ID()); err != nil {
+			s.logger.Error("failed to record wrong code attempt", zap.Error(err))
+		}
+		otp.RecordFailedAttempt()
+		return nil, domain.NewValidationError(fmt.Sprintf("invalid code; %d attempts left", otp.RemainingAttempts()))
+	}
+
+	if err := s.otpRepo.Consume(ctx, otp.ID()); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewValidationError("code expired; request a new code")
+		}
+		return nil, fmt.Errorf("failed to consume code: %w", err)
+	}
+	return otp, nil
+}
+
+// generateNumericCode returns a uniformly random decimal code of the given length.
+func generateNumericCode(digits int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
@@ -0,0 +1,283 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/webauthn"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxPasskeysPerUser caps how many passkeys one account can register.
+const maxPasskeysPerUser = 10
+
+// FinishPasskeyRegistrationRequest carries the browser's response to the registration options.
+type FinishPasskeyRegistrationRequest struct {
+	ChallengeID uuid.UUID                    `json:"challenge_id" binding:"required"`
+	Name        string                       `json:"name" binding:"max=100"`
+	Credential  webauthn.AttestationResponse `json:"credential"`
+}
+
+// PasskeyLoginRequest carries the browser's response to the sign-in options.
+type PasskeyLoginRequest struct {
+	ChallengeID uuid.UUID                  `json:"challenge_id" binding:"required"`
+	Credential  webauthn.AssertionResponse `json:"credential"`
+}
+
+// PasskeyRegistrationOptionsDTO starts a registration. PublicKey is passed to
+// navigator.credentials.create and ChallengeID is sent back with the result.
+type PasskeyRegistrationOptionsDTO struct {
+	ChallengeID uuid.UUID                `json:"challenge_id"`
+	PublicKey   webauthn.CreationOptions `json:"public_key"`
+}
+
+// PasskeyLoginOptionsDTO starts a sign-in. PublicKey is passed to
+// navigator.credentials.get and ChallengeID is sent back with the result.
+type PasskeyLoginOptionsDTO struct {
+	ChallengeID uuid.UUID               `json:"challenge_id"`
+	PublicKey   webauthn.RequestOptions `json:"public_key"`
+}
+
+// PasskeyDTO represents a registered passkey in API responses.
+type PasskeyDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// PasskeyService implements passkey registration and management, and verifies
+// passkey sign-ins for AuthService.
+type PasskeyService struct {
+	passkeyRepo   identity.PasskeyRepository
+	challengeRepo identity.WebAuthnChallengeRepository
+	userRepo      identity.UserRepository
+	rp            *webauthn.RelyingParty
+	events        SecurityEventPublisher
+	logger        *zap.Logger
+}
+
+// NewPasskeyService creates a new PasskeyService.
+func NewPasskeyService(
+	passkeyRepo identity.PasskeyRepository,
+	challengeRepo identity.WebAuthnChallengeRepository,
+	userRepo identity.UserRepository,
+	rp *webauthn.RelyingParty,
+	events SecurityEventPublisher,
+	logger *zap.Logger,
+) *PasskeyService {
+	return &PasskeyService{
+		passkeyRepo:   passkeyRepo,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		rp:            rp,
+		events:        events,
+		logger:        logger,
+	}
+}
+
+// BeginRegistration issues registration options for a new passkey on the signed-in user's account.
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID uuid.UUID) (*PasskeyRegistrationOptionsDTO, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("User", userID.String())
+	}
+
+	existing, err := s.passkeyRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	if len(existing) >= maxPasskeysPerUser {
+		return nil, domain.NewValidationError(fmt.Sprintf("at most %d passkeys can be registered", maxPasskeysPerUser))
+	}
+
+	exclude := make([]webauthn.CredentialDescriptor, len(existing))
+	for i, passkey := range existing {
+		exclude[i] = webauthn.CredentialDescriptor{Type: "public-key", ID: passkey.CredentialID(), Transports: passkey.Transports()}
+	}
+
+	challenge, err := s.issueChallenge(ctx, &userID, identity.WebAuthnPurposeRegistration)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user handle is the account ID: stable and free of personal data.
+	webauthnUser := webauthn.User{ID: userID[:], Name: user.Email(), DisplayName: user.FullName()}
+	return &PasskeyRegistrationOptionsDTO{
+		ChallengeID: challenge.ID(),
+		PublicKey:   s.rp.CreationOptions(challenge.Challenge(), webauthnUser, exclude),
+	}, nil
+}
+
+// FinishRegistration verifies the authenticator's response and stores the new passkey.
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID uuid.UUID, req FinishPasskeyRegistrationRequest) (*PasskeyDTO, error) {
+	challenge, err := s.challengeRepo.Consume(ctx, req.ChallengeID)
+	if err != nil || !challenge.IsValidFor(identity.WebAuthnPurposeRegistration, &userID) {
+		return nil, domain.NewValidationError(identity.ErrWebAuthnChallengeInvalid.Error())
+	}
+
+	credential, err := s.rp.VerifyRegistration(challenge.Challenge(), &req.Credential)
+	if err != nil {
+		s.logger.Info("passkey registration rejected", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, domain.NewValidationError("passkey registration could not be verified")
+	}
+
+	passkey := identity.NewPasskey(userID, credential.ID, credential.PublicKey, credential.SignCount, credential.Transports, req.Name)
+	if err := s.passkeyRepo.Save(ctx, passkey); err != nil {
+		if errors.Is(err, identity.ErrPasskeyAlreadyRegistered) {
+			return nil, domain.NewConflictError(err.Error())
+		}
+		s.logger.Error("failed to save passkey", zap.Error(err))
+		return nil, fmt.Errorf("failed to save passkey: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventPasskeyAdded, userID, map[string]string{"passkey_id": passkey.ID().String()}))
+	s.logger.Info("passkey registered", zap.String("user_id", userID.String()), zap.String("passkey_id", passkey.ID().String()))
+
+	result := toPasskeyDTO(passkey)
+	return &result, nil
+}
+
+// ListPasskeys returns the user's registered passkeys.
+func (s *PasskeyService) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]PasskeyDTO, error) {
+	passkeys, err := s.passkeyRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	dtos := make([]PasskeyDTO, len(passkeys))
+	for i, passkey := range passkeys {
+		dtos[i] = toPasskeyDTO(passkey)
+	}
+	return dtos, nil
+}
+
+// DeletePasskey removes one of the user's passkeys. The authenticator keeps its
+// copy of the credential, but it can no longer sign in.
+func (s *PasskeyService) DeletePasskey(ctx context.Context, userID, passkeyID uuid.UUID) error {
+	if err := s.passkeyRepo.Delete(ctx, userID, passkeyID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewNotFoundError("Passkey", passkeyID.String())
+		}
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventPasskeyRemoved, userID, map[string]string{"passkey_id": passkeyID.String()}))
+	s.logger.Info("passkey removed", zap.String("user_id", userID.String()), zap.String("passkey_id", passkeyID.String()))
+	return nil
+}
+
+// BeginLogin issues sign-in options. No account is named up front: the browser
+// offers the passkeys it holds and the response identifies the account.
+func (s *PasskeyService) BeginLogin(ctx context.Context) (*PasskeyLoginOptionsDTO, error) {
+	// Sign-in challenges are issued to anyone, so clear out abandoned ones as we go.
+	if err := s.challengeRepo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+		s.logger.Warn("failed to delete expired passkey challenges", zap.Error(err))
+	}
+
+	challenge, err := s.issueChallenge(ctx, nil, identity.WebAuthnPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+	return &PasskeyLoginOptionsDTO{
+		ChallengeID: challenge.ID(),
+		PublicKey:   s.rp.RequestOptions(challenge.Challenge(), nil),
+	}, nil
+}
+
+// Authenticate verifies a passkey sign-in and returns the account it belongs to.
+// Every failure is reported the same way so responses do not reveal which check failed.
+func (s *PasskeyService) Authenticate(ctx context.Context, req PasskeyLoginRequest) (*identity.User, error) {
+	failed := domain.NewUnauthorizedError("passkey sign-in failed")
+
+	challenge, err := s.challengeRepo.Consume(ctx, req.ChallengeID)
+	if err != nil || !challenge.IsValidFor(identity.WebAuthnPurposeLogin, nil) {
+		return nil, failed
+	}
+
+	passkey, err := s.passkeyRepo.FindByCredentialID(ctx, req.Credential.RawID)
+	if err != nil {
+		return nil, failed
+	}
+	userID := passkey.UserID()
+	if handle := req.Credential.Response.UserHandle; len(handle) > 0 && !bytes.Equal(handle, userID[:]) {
+		return nil, failed
+	}
+
+	signCount, err := s.rp.VerifyAssertion(challenge.Challenge(), &req.Credential, passkey.PublicKey(), passkey.SignCount())
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountRegression) {
+			s.reportSuspectedClone(ctx, passkey)
+		} else {
+			s.logger.Info("passkey sign-in rejected", zap.String("user_id", userID.String()), zap.Error(err))
+		}
+		return nil, failed
+	}
+
+	passkey.RecordUse(signCount)
+	if err := s.passkeyRepo.RecordUse(ctx, passkey); err != nil {
+		if errors.Is(err, identity.ErrPasskeySignCountRegressed) {
+			s.reportSuspectedClone(ctx, passkey)
+			return nil, failed
+		}
+		s.logger.Error("failed to record passkey use", zap.Error(err))
+		return nil, fmt.Errorf("failed to record passkey use: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, failed
+	}
+	return user, nil
+}
+
+func (s *PasskeyService) issueChallenge(ctx context.Context, userID *uuid.UUID, purpose identity.WebAuthnPurpose) (*identity.WebAuthnChallenge, error) {
+	raw, err := webauthn.GenerateChallenge()
+	if err != nil {
+		s.logger.Error("failed to generate passkey challenge", zap.Error(err))
+		return nil, err
+	}
+
+	challenge := identity.NewWebAuthnChallenge(userID, raw, purpose, time.Now().UTC().Add(webauthn.Timeout))
+	if err := s.challengeRepo.Create(ctx, challenge); err != nil {
+		s.logger.Error("failed to save passkey challenge", zap.Error(err))
+		return nil, fmt.Errorf("failed to save passkey challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+func (s *PasskeyService) reportSuspectedClone(ctx context.Context, passkey *identity.Passkey) {
+	s.logger.Warn("passkey signature counter regressed; possible cloned authenticator",
+		zap.String("user_id", passkey.UserID().String()),
+		zap.String("passkey_id", passkey.ID().String()),
+	)
+	s.publish(ctx, NewSecurityEvent(SecurityEventPasskeyCloneSuspected, passkey.UserID(), map[string]string{"passkey_id": passkey.ID().String()}))
+}
+
+func (s *PasskeyService) publish(ctx context.Context, event SecurityEvent) {
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+}
+
+// toPasskeyDTO converts a domain Passkey to a PasskeyDTO.
+func toPasskeyDTO(passkey *identity.Passkey) PasskeyDTO {
+	transports := passkey.Transports()
+	if transports == nil {
+		transports = []string{}
+	}
+	return PasskeyDTO{
+		ID:         passkey.ID(),
+		Name:       passkey.Name(),
+		Transports: transports,
+		CreatedAt:  passkey.CreatedAt(),
+		LastUsedAt: passkey.LastUsedAt(),
+	}
+}
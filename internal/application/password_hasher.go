@@ -0,0 +1,13 @@
+package application
+
+// PasswordHasher hashes new passwords and verifies stored password hashes. Stored
+// hashes describe their own algorithm and parameters, so hashes made under an older
+// configuration keep verifying and are replaced at the next successful sign-in.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded; a mismatch is not an error.
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash reports whether encoded uses another algorithm or weaker parameters
+	// than new hashes do.
+	NeedsRehash(encoded string) bool
+}
@@ -0,0 +1,20 @@
+package application
+
+import (
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+)
+
+// PasswordPolicy holds the rules every new password must meet, wherever it is set.
+type PasswordPolicy interface {
+	// Check returns an error listing every rule password breaks, or nil. personal
+	// holds the account's email and name, which the password must not contain.
+	Check(password string, personal ...string) error
+}
+
+// checkPassword applies the policy and reports any violations as a validation error.
+func checkPassword(policy PasswordPolicy, password string, personal ...string) error {
+	if err := policy.Check(password, personal...); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+)
+
+// RateLimitedError is returned when a caller must wait before repeating an action.
+// Handlers translate it into 429 Too Many Requests with a Retry-After header.
+type RateLimitedError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+// NewRateLimitedError creates a RateLimitedError.
+func NewRateLimitedError(message string, retryAfter time.Duration) *RateLimitedError {
+	return &RateLimitedError{Message: message, RetryAfter: retryAfter}
+}
+
+// Error implements the error interface.
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s; retry after %s", e.Message, e.RetryAfter.Round(time.Second))
+}
+
+// requestLimit caps how many requests one key may make per fixed window.
+type requestLimit struct {
+	scope   string
+	limit   int
+	window  time.Duration
+	message string
+}
+
+// checkRequestLimit counts a request for key and returns a RateLimitedError once the
+// current window holds more than the limit allows.
+func checkRequestLimit(ctx context.Context, counters identity.RequestCounterRepository, limit requestLimit, key string) error {
+	count, windowEndsAt, err := counters.Increment(ctx, limit.scope, key, limit.window)
+	if err != nil {
+		return fmt.Errorf("failed to check request throttle: %w", err)
+	}
+	if count > limit.limit {
+		return NewRateLimitedError(limit.message, time.Until(windowEndsAt))
+	}
+	return nil
+}
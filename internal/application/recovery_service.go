@@ -0,0 +1,344 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// recoveryLinkTTL is how long an approved recovery link stays valid.
+	recoveryLinkTTL = 24 * time.Hour
+	// maxOpenRecoveryCases caps the pending or approved cases one claimed email can have,
+	// so the public form cannot be used to flood the admin queue for an account.
+	maxOpenRecoveryCases = 3
+)
+
+// FileRecoveryRequest represents a user who lost access to their email asking for help.
+type FileRecoveryRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	FullName string `json:"full_name" binding:"required"`
+	Phone    string `json:"phone"`
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Details  string `json:"details" binding:"max=2000"`
+}
+
+// ReviewRecoveryRequest carries the admin's note on an approval or rejection.
+type ReviewRecoveryRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+// CompleteRecoveryRequest represents a user following a recovery link and choosing a new password.
+type CompleteRecoveryRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// RecoveryEventDTO represents one step of a recovery case in API responses.
+type RecoveryEventDTO struct {
+	Action    string     `json:"action"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RecoveryCaseDTO represents a recovery case in admin API responses. The token is never included.
+type RecoveryCaseDTO struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       *uuid.UUID         `json:"user_id,omitempty"`
+	ClaimedEmail string             `json:"claimed_email"`
+	FullName     string             `json:"full_name"`
+	Phone        string             `json:"phone,omitempty"`
+	ContactEmail string             `json:"contact_email"`
+	Details      string             `json:"details,omitempty"`
+	Status       string             `json:"status"`
+	ReviewedBy   *uuid.UUID         `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time         `json:"reviewed_at,omitempty"`
+	ReviewNote   string             `json:"review_note,omitempty"`
+	ExpiresAt    *time.Time         `json:"expires_at,omitempty"`
+	CompletedAt  *time.Time         `json:"completed_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Events       []RecoveryEventDTO `json:"events,omitempty"`
+}
+
+// RecoveryService implements support-assisted account recovery for users who can
+// no longer reach the email address on their account.
+type RecoveryService struct {
+	recoveryRepo   identity.RecoveryCaseRepository
+	userRepo       identity.UserRepository
+	hasher         PasswordHasher
+	passwordPolicy PasswordPolicy
+	notifier       RecoveryNotifier
+	events         SecurityEventPublisher
+	revocations    *TokenRevocationList
+	logger         *zap.Logger
+}
+
+// NewRecoveryService creates a new RecoveryService.
+func NewRecoveryService(
+	recoveryRepo identity.RecoveryCaseRepository,
+	userRepo identity.UserRepository,
+	hasher PasswordHasher,
+	passwordPolicy PasswordPolicy,
+	notifier RecoveryNotifier,
+	events SecurityEventPublisher,
+	revocations *TokenRevocationList,
+	logger *zap.Logger,
+) *RecoveryService {
+	return &RecoveryService{
+		recoveryRepo:   recoveryRepo,
+		userRepo:       userRepo,
+		hasher:         hasher,
+		passwordPolicy: passwordPolicy,
+		notifier:       notifier,
+		events:         events,
+		revocations:    revocations,
+		logger:         logger,
+	}
+}
+
+// FileRecovery opens a case for an admin to review. It succeeds whether or not the
+// claimed email belongs to an account, so the form cannot be used to probe for users.
+func (s *RecoveryService) FileRecovery(ctx context.Context, req FileRecoveryRequest) error {
+	claimed, err := identity.NewEmail(req.Email)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	open, err := s.recoveryRepo.CountOpenForEmail(ctx, claimed)
+	if err != nil {
+		s.logger.Error("failed to count open recovery cases", zap.Error(err))
+		return fmt.Errorf("failed to file recovery request: %w", err)
+	}
+	if open >= maxOpenRecoveryCases {
+		s.logger.Warn("recovery request dropped: too many open cases", zap.String("email", claimed.String()))
+		return nil
+	}
+
+	var userID *uuid.UUID
+	if user, _ := s.userRepo.FindByEmail(ctx, claimed); user != nil {
+		id := user.ID()
+		userID = &id
+	}
+
+	recoveryCase, err := identity.NewRecoveryCase(userID, claimed.String(), req.FullName, req.Phone, req.NewEmail, req.Details)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	event := identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionFiled, nil, "")
+	if err := s.recoveryRepo.Create(ctx, recoveryCase, event); err != nil {
+		s.logger.Error("failed to save recovery case", zap.Error(err))
+		return fmt.Errorf("failed to file recovery request: %w", err)
+	}
+
+	s.logger.Info("recovery case filed",
+		zap.String("case_id", recoveryCase.ID().String()),
+		zap.Bool("matched_account", userID != nil),
+	)
+	return nil
+}
+
+// ListCases returns a paginated list of recovery cases, optionally filtered by status and user.
+func (s *RecoveryService) ListCases(ctx context.Context, status string, userID *uuid.UUID, page, limit int) ([]RecoveryCaseDTO, int64, error) {
+	cases, total, err := s.recoveryRepo.List(ctx, identity.RecoveryStatus(status), userID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list recovery cases: %w", err)
+	}
+
+	dtos := make([]RecoveryCaseDTO, len(cases))
+	for i, recoveryCase := range cases {
+		dtos[i] = toRecoveryCaseDTO(recoveryCase, nil)
+	}
+	return dtos, total, nil
+}
+
+// GetCase returns a recovery case with its full history.
+func (s *RecoveryService) GetCase(ctx context.Context, caseID uuid.UUID) (*RecoveryCaseDTO, error) {
+	recoveryCase, err := s.recoveryRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("RecoveryCase", caseID.String())
+	}
+	return s.caseWithEvents(ctx, recoveryCase)
+}
+
+// ApproveCase approves a pending case: every refresh token, session and access token of
+// the account is revoked, and a single-use recovery link is sent to the requester's new contact address.
+func (s *RecoveryService) ApproveCase(ctx context.Context, actorID, caseID uuid.UUID, req ReviewRecoveryRequest) (*RecoveryCaseDTO, error) {
+	recoveryCase, err := s.recoveryRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("RecoveryCase", caseID.String())
+	}
+
+	if existing, _ := s.userRepo.FindByEmail(ctx, recoveryCase.ContactEmailVO()); existing != nil &&
+		(recoveryCase.UserID() == nil || existing.ID() != *recoveryCase.UserID()) {
+		return nil, domain.NewAlreadyExistsError("User", "email", recoveryCase.ContactEmail())
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		s.logger.Error("failed to generate recovery token", zap.Error(err))
+		return nil, fmt.Errorf("failed to generate recovery token: %w", err)
+	}
+
+	if err := recoveryCase.Approve(actorID, req.Note, token, time.Now().UTC().Add(recoveryLinkTTL)); err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	event := identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionApproved, &actorID, recoveryCase.ReviewNote())
+	if err := s.recoveryRepo.Review(ctx, recoveryCase, event); err != nil {
+		if errors.Is(err, identity.ErrRecoveryCaseNotPending) {
+			return nil, domain.NewValidationError(err.Error())
+		}
+		s.logger.Error("failed to approve recovery case", zap.Error(err))
+		return nil, fmt.Errorf("failed to approve recovery case: %w", err)
+	}
+	// The case is already approved, so a failure here is logged rather than returned.
+	if err := s.revocations.RevokeUser(ctx, *recoveryCase.UserID()); err != nil {
+		s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("user_id", recoveryCase.UserID().String()))
+	}
+
+	if err := s.notifier.SendRecoveryEmail(ctx, recoveryCase.ContactEmail(), token); err != nil {
+		s.logger.Warn("failed to enqueue recovery email", zap.Error(err), zap.String("case_id", caseID.String()))
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventAccountRecoveryApproved, *recoveryCase.UserID(), map[string]string{
+		"case_id":     caseID.String(),
+		"approved_by": actorID.String(),
+	}))
+
+	s.logger.Info("recovery case approved",
+		zap.String("case_id", caseID.String()),
+		zap.String("user_id", recoveryCase.UserID().String()),
+		zap.String("approved_by", actorID.String()),
+	)
+	return s.caseWithEvents(ctx, recoveryCase)
+}
+
+// RejectCase rejects a pending case. The account is left untouched.
+func (s *RecoveryService) RejectCase(ctx context.Context, actorID, caseID uuid.UUID, req ReviewRecoveryRequest) (*RecoveryCaseDTO, error) {
+	recoveryCase, err := s.recoveryRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("RecoveryCase", caseID.String())
+	}
+
+	if err := recoveryCase.Reject(actorID, req.Note); err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	event := identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionRejected, &actorID, recoveryCase.ReviewNote())
+	if err := s.recoveryRepo.Review(ctx, recoveryCase, event); err != nil {
+		if errors.Is(err, identity.ErrRecoveryCaseNotPending) {
+			return nil, domain.NewValidationError(err.Error())
+		}
+		s.logger.Error("failed to reject recovery case", zap.Error(err))
+		return nil, fmt.Errorf("failed to reject recovery case: %w", err)
+	}
+
+	s.logger.Info("recovery case rejected",
+		zap.String("case_id", caseID.String()),
+		zap.String("rejected_by", actorID.String()),
+	)
+	return s.caseWithEvents(ctx, recoveryCase)
+}
+
+// CompleteRecovery consumes a recovery link. The account moves to the confirmed contact
+// address, which counts as verified since the link reached it, and takes the new password.
+// Anything signed in since approval is signed out again.
+func (s *RecoveryService) CompleteRecovery(ctx context.Context, req CompleteRecoveryRequest) error {
+	recoveryCase, err := s.recoveryRepo.FindByToken(ctx, req.Token)
+	if err != nil {
+		return domain.NewValidationError(identity.ErrRecoveryLinkInvalid.Error())
+	}
+
+	if err := recoveryCase.Complete(); err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	if existing, _ := s.userRepo.FindByEmail(ctx, recoveryCase.ContactEmailVO()); existing != nil && existing.ID() != *recoveryCase.UserID() {
+		return domain.NewAlreadyExistsError("User", "email", recoveryCase.ContactEmail())
+	}
+
+	if err := checkPassword(s.passwordPolicy, req.NewPassword, recoveryCase.ContactEmail(), recoveryCase.ClaimedEmail(), recoveryCase.FullName()); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		s.logger.Error("failed to hash password", zap.Error(err))
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	event := identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionCompleted, nil, "")
+	if err := s.recoveryRepo.Complete(ctx, recoveryCase, hashedPassword, event); err != nil {
+		if errors.Is(err, identity.ErrRecoveryLinkInvalid) {
+			return domain.NewValidationError(err.Error())
+		}
+		s.logger.Error("failed to complete recovery", zap.Error(err))
+		return fmt.Errorf("failed to complete recovery: %w", err)
+	}
+	// The new password is already set, so a failure here is logged rather than returned.
+	if err := s.revocations.RevokeUser(ctx, *recoveryCase.UserID()); err != nil {
+		s.logger.Error("failed to revoke access tokens", zap.Error(err), zap.String("user_id", recoveryCase.UserID().String()))
+	}
+
+	s.publish(ctx, NewSecurityEvent(SecurityEventAccountRecovered, *recoveryCase.UserID(), map[string]string{
+		"case_id": recoveryCase.ID().String(),
+	}))
+
+	s.logger.Info("account recovered",
+		zap.String("case_id", recoveryCase.ID().String()),
+		zap.String("user_id", recoveryCase.UserID().String()),
+	)
+	return nil
+}
+
+func (s *RecoveryService) caseWithEvents(ctx context.Context, recoveryCase *identity.RecoveryCase) (*RecoveryCaseDTO, error) {
+	events, err := s.recoveryRepo.ListEvents(ctx, recoveryCase.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recovery case history: %w", err)
+	}
+	result := toRecoveryCaseDTO(recoveryCase, events)
+	return &result, nil
+}
+
+func (s *RecoveryService) publish(ctx context.Context, event SecurityEvent) {
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+	}
+}
+
+// toRecoveryCaseDTO converts a domain RecoveryCase and its history to a RecoveryCaseDTO.
+func toRecoveryCaseDTO(recoveryCase *identity.RecoveryCase, events []*identity.RecoveryEvent) RecoveryCaseDTO {
+	dto := RecoveryCaseDTO{
+		ID:           recoveryCase.ID(),
+		UserID:       recoveryCase.UserID(),
+		ClaimedEmail: recoveryCase.ClaimedEmail(),
+		FullName:     recoveryCase.FullName(),
+		Phone:        recoveryCase.Phone(),
+		ContactEmail: recoveryCase.ContactEmail(),
+		Details:      recoveryCase.Details(),
+		Status:       string(recoveryCase.Status()),
+		ReviewedBy:   recoveryCase.ReviewedBy(),
+		ReviewedAt:   recoveryCase.ReviewedAt(),
+		ReviewNote:   recoveryCase.ReviewNote(),
+		ExpiresAt:    recoveryCase.ExpiresAt(),
+		CompletedAt:  recoveryCase.CompletedAt(),
+		CreatedAt:    recoveryCase.CreatedAt(),
+	}
+	for _, event := range events {
+		dto.Events = append(dto.Events, RecoveryEventDTO{
+			Action:    string(event.Action()),
+			ActorID:   event.ActorID(),
+			Note:      event.Note(),
+			CreatedAt: event.CreatedAt(),
+		})
+	}
+	return dto
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SecurityEventType identifies a security-relevant occurrence on an account.
+type SecurityEventType string
+
+const (
+	// SecurityEventRefreshTokenReuse is emitted when an already-rotated refresh token is presented again.
+	SecurityEventRefreshTokenReuse SecurityEventType = "refresh_token_reuse"
+	// SecurityEventAccountStatusChanged is emitted when an admin suspends, bans or reinstates an account.
+	SecurityEventAccountStatusChanged SecurityEventType = "account_status_changed"
+	// SecurityEventMFAEnabled is emitted when a user confirms an authenticator app.
+	SecurityEventMFAEnabled SecurityEventType = "mfa_enabled"
+	// SecurityEventMFADisabled is emitted when a user removes their authenticator app.
+	SecurityEventMFADisabled SecurityEventType = "mfa_disabled"
+	// SecurityEventMFAPolicyChanged is emitted when an admin changes which roles must use two-factor authentication.
+	SecurityEventMFAPolicyChanged SecurityEventType = "mfa_policy_changed"
+	// SecurityEventAccountLocked is emitted when repeated failed sign-ins lock an account.
+	SecurityEventAccountLocked SecurityEventType = "account_locked"
+	// SecurityEventAccountUnlocked is emitted when an admin lifts a sign-in lockout.
+	SecurityEventAccountUnlocked SecurityEventType = "account_unlocked"
+	// SecurityEventPasskeyAdded is emitted when a user registers a passkey.
+	SecurityEventPasskeyAdded SecurityEventType = "passkey_added"
+	// SecurityEventPasskeyRemoved is emitted when a user removes a passkey.
+	SecurityEventPasskeyRemoved SecurityEventType = "passkey_removed"
+	// SecurityEventPasskeyCloneSuspected is emitted when a passkey's signature counter goes
+	// backwards, which suggests the credential was copied to another authenticator.
+	SecurityEventPasskeyCloneSuspected SecurityEventType = "passkey_clone_suspected"
+	// SecurityEventAccountRecoveryApproved is emitted when an admin approves an assisted recovery case.
+	SecurityEventAccountRecoveryApproved SecurityEventType = "account_recovery_approved"
+	// SecurityEventAccountRecovered is emitted when a user regains an account through a recovery link.
+	SecurityEventAccountRecovered SecurityEventType = "account_recovered"
+	// SecurityEventPasswordChanged is emitted when a signed-in user changes their password.
+	SecurityEventPasswordChanged SecurityEventType = "password_changed"
+	// SecurityEventEmailChanged is emitted when a user confirms a move to a new email address.
+	SecurityEventEmailChanged SecurityEventType = "email_changed"
+	// SecurityEventEmailChangeCancelled is emitted when a pending email change is cancelled from the old address.
+	SecurityEventEmailChangeCancelled SecurityEventType = "email_change_cancelled"
+	// SecurityEventOAuthClientCreated is emitted when an admin registers an OAuth client.
+	SecurityEventOAuthClientCreated SecurityEventType = "oauth_client_created"
+	// SecurityEventOAuthClientSecretRotated is emitted when an admin rotates an OAuth client's secret.
+	SecurityEventOAuthClientSecretRotated SecurityEventType = "oauth_client_secret_rotated"
+	// SecurityEventOAuthClientDisabled is emitted when an admin disables an OAuth client.
+	SecurityEventOAuthClientDisabled SecurityEventType = "oauth_client_disabled"
+	// SecurityEventAuthorizationCodeReuse is emitted when an already-redeemed OpenID Connect authorization code is presented again.
+	SecurityEventAuthorizationCodeReuse SecurityEventType = "authorization_code_reuse"
+	// SecurityEventExternalIdentityLinked is emitted when a Google or Apple account is linked to an existing user.
+	SecurityEventExternalIdentityLinked SecurityEventType = "external_identity_linked"
+)
+
+// SecurityEvent describes a security-relevant occurrence for a user.
+type SecurityEvent struct {
+	Type       SecurityEventType
+	UserID     uuid.UUID
+	Metadata   map[string]string
+	OccurredAt time.Time
+}
+
+// NewSecurityEvent creates a SecurityEvent stamped with the current UTC time.
+func NewSecurityEvent(eventType SecurityEventType, userID uuid.UUID, metadata map[string]string) SecurityEvent {
+	return SecurityEvent{
+		Type:       eventType,
+		UserID:     userID,
+		Metadata:   metadata,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// SecurityEventPublisher publishes security events for auditing and alerting.
+// TODO: replace with Kafka-backed publisher on identity.events when that topic exists.
+type SecurityEventPublisher interface {
+	Publish(ctx context.Context, event SecurityEvent) error
+}
+
+// LogOnlySecurityEventPublisher is a stub publisher that logs events without forwarding them.
+type LogOnlySecurityEventPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogOnlySecurityEventPublisher creates a new LogOnlySecurityEventPublisher.
+func NewLogOnlySecurityEventPublisher(logger *zap.Logger) *LogOnlySecurityEventPublisher {
+	return &LogOnlySecurityEventPublisher{logger: logger}
+}
+
+// Publish logs the security event without forwarding it.
+func (p *LogOnlySecurityEventPublisher) Publish(ctx context.Context, event SecurityEvent) error {
+	fields := []zap.Field{
+		zap.String("event_type", string(event.Type)),
+		zap.String("user_id", event.UserID.String()),
+		zap.Time("occurred_at", event.OccurredAt),
+	}
+	for k, v := range event.Metadata {
+		fields = append(fields, zap.String(k, v))
+	}
+	p.logger.Warn("security event (log-only)", fields...)
+	return nil
+}
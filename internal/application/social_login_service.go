@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/federation"
+	"go.uber.org/zap"
+)
+
+// IdentityProvider verifies the ID tokens of an external identity provider such as
+// Google or Apple and maps their claims. federation.Provider implements it.
+type IdentityProvider interface {
+	Name() string
+	Verify(ctx context.Context, idToken, nonce string) (*federation.Claims, error)
+}
+
+// SocialLoginRequest represents a sign-in with an ID token the app got from an
+// external identity provider's sign-in SDK.
+type SocialLoginRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+	// Nonce is the nonce the app passed to the provider; when set the token must carry it.
+	Nonce string `json:"nonce"`
+	// FullName names a new account when the ID token has no name, as with Apple, which
+	// only tells the app the user's name on their first sign-in.
+	FullName string `json:"full_name"`
+}
+
+// SocialLoginService signs users in with accounts at external identity providers.
+// A provider account is linked to one user: on first use, to the user with the same
+// email if there is one, and otherwise to a new owner account.
+type SocialLoginService struct {
+	providers    map[string]IdentityProvider
+	identityRepo identity.ExternalIdentityRepository
+	userRepo     identity.UserRepository
+	hasher       PasswordHasher
+	events       SecurityEventPublisher
+	logger       *zap.Logger
+}
+
+// NewSocialLoginService creates a new SocialLoginService with the configured providers.
+func NewSocialLoginService(
+	providers []IdentityProvider,
+	identityRepo identity.ExternalIdentityRepository,
+	userRepo identity.UserRepository,
+	hasher PasswordHasher,
+	events SecurityEventPublisher,
+	logger *zap.Logger,
+) *SocialLoginService {
+	byName := make(map[string]IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &SocialLoginService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		hasher:       hasher,
+		events:       events,
+		logger:       logger,
+	}
+}
+
+// Authenticate verifies an ID token of the named provider and returns the user its
+// account is linked to, linking it first if this is the account's first sign-in.
+//
+// Linking to an existing user needs an email the provider has verified and that the
+// user has verified too. Otherwise someone could sign up with another person's email
+// before they ever used it and take over their account once they sign in with Google.
+func (s *SocialLoginService) Authenticate(ctx context.Context, providerName string, req SocialLoginRequest) (*identity.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.NewNotFoundError("IdentityProvider", providerName)
+	}
+
+	claims, err := provider.Verify(ctx, req.IDToken, req.Nonce)
+	if err != nil {
+		s.logger.Info("external sign-in rejected", zap.String("provider", providerName), zap.Error(err))
+		return nil, domain.NewUnauthorizedError("sign-in with " + providerName + " failed")
+	}
+
+	link, err := s.identityRepo.FindByProviderSubject(ctx, providerName, claims.Subject)
+	switch {
+	case err == nil:
+		return s.linkedUser(ctx, link, claims)
+	case !errors.Is(err, domain.ErrNotFound):
+		s.logger.Error("failed to look up external identity", zap.Error(err))
+		return nil, fmt.Errorf("failed to look up external identity: %w", err)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.NewValidationError(providerName + " has not verified an email address for this account")
+	}
+	email, err := identity.NewEmail(claims.Email)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	existing := err == nil
+	switch {
+	case existing:
+		if !user.IsVerified() {
+			return nil, domain.NewConflictError("an account with this email already exists; sign in with your password and verify your email before using " + providerName)
+		}
+	case errors.Is(err, domain.ErrNotFound):
+		if user, err = s.createUser(ctx, email, claims, req.FullName); err != nil {
+			return nil, err
+		}
+	default:
+		s.logger.Error("failed to look up user by email", zap.Error(err))
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	if err := s.link(ctx, user, providerName, claims); err != nil {
+		return nil, err
+	}
+	if existing {
+		s.logger.Info("external identity linked", zap.String("user_id", user.ID().String()), zap.String("provider", providerName))
+		event := NewSecurityEvent(SecurityEventExternalIdentityLinked, user.ID(), map[string]string{"provider": providerName})
+		if err := s.events.Publish(ctx, event); err != nil {
+			s.logger.Error("failed to publish security event", zap.Error(err), zap.String("event_type", string(event.Type)))
+		}
+	}
+	return user, nil
+}
+
+// linkedUser returns the user a provider account is linked to and records the sign-in.
+func (s *SocialLoginService) linkedUser(ctx context.Context, link *identity.ExternalIdentity, claims *federation.Claims) (*identity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, link.UserID())
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("sign-in with " + link.Provider() + " failed")
+	}
+
+	link.RecordUse(claims.Email)
+	if err := s.identityRepo.RecordUse(ctx, link); err != nil {
+		// The sign-in itself is valid; only the bookkeeping failed.
+		s.logger.Warn("failed to record external identity use", zap.Error(err), zap.String("user_id", user.ID().String()))
+	}
+	return user, nil
+}
+
+// createUser creates an owner account for a provider account whose email has no user
+// yet. The email counts as verified, and the account gets a random password the user
+// never learns; they can set one with a password reset.
+func (s *SocialLoginService) createUser(ctx context.Context, email identity.Email, claims *federation.Claims, fullName string) (*identity.User, error) {
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.TrimSpace(fullName)
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(email.String(), "@")
+	}
+
+	password, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		s.logger.Error("failed to hash password", zap.Error(err))
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := identity.NewUser(email.String(), "", name, hashedPassword, auth.RoleOwner)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	user.Verify()
+	if err := s.userRepo.Save(ctx, user); err != nil {
+		s.logger.Error("failed to save user", zap.Error(err))
+		return nil, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	s.logger.Info("user registered with external identity", zap.String("user_id", user.ID().String()))
+	return user, nil
+}
+
+// link links a provider account to a user.
+func (s *SocialLoginService) link(ctx context.Context, user *identity.User, providerName string, claims *federation.Claims) error {
+	link := identity.NewExternalIdentity(user.ID(), providerName, claims.Subject, claims.Email)
+	link.RecordUse(claims.Email)
+	if err := s.identityRepo.Create(ctx, link); err != nil {
+		if errors.Is(err, identity.ErrExternalIdentityAlreadyLinked) {
+			return domain.NewConflictError("another " + providerName + " account is already linked to this user")
+		}
+		s.logger.Error("failed to link external identity", zap.Error(err))
+		return fmt.Errorf("failed to link external identity: %w", err)
+	}
+	return nil
+}
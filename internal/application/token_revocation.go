@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultTokenRevocationSyncInterval is how often the in-memory revocation list picks
+// up revocations made by other replicas.
+const DefaultTokenRevocationSyncInterval = 5 * time.Second
+
+// ErrTokenRevoked is returned when a correctly signed, unexpired access token has been revoked.
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+type tokenRevocationKey struct {
+	scope identity.TokenRevocationScope
+	key   string
+}
+
+// TokenRevocationList withdraws access tokens before they expire: one token by its
+// jti, every token of a session, or every token of a user. Revocations are stored in
+// Postgres and mirrored in memory, so checking a token costs no database round trip;
+// Sync picks up revocations made by other replicas. A revocation is dropped once the
+// tokens it covers have expired, which keeps the mirror small.
+type TokenRevocationList struct {
+	repo          identity.TokenRevocationRepository
+	tokenLifetime time.Duration
+	logger        *zap.Logger
+
+	mu      sync.RWMutex
+	entries map[tokenRevocationKey]*identity.TokenRevocation
+}
+
+// NewTokenRevocationList creates a new, empty TokenRevocationList for access tokens
+// that live for tokenLifetime. Call Sync before serving to load stored revocations.
+func NewTokenRevocationList(repo identity.TokenRevocationRepository, tokenLifetime time.Duration, logger *zap.Logger) *TokenRevocationList {
+	return &TokenRevocationList{
+		repo:          repo,
+		tokenLifetime: tokenLifetime,
+		logger:        logger,
+		entries:       make(map[tokenRevocationKey]*identity.TokenRevocation),
+	}
+}
+
+// RevokeToken revokes the access token with the given jti.
+func (l *TokenRevocationList) RevokeToken(ctx context.Context, userID uuid.UUID, tokenID string) error {
+	return l.revoke(ctx, identity.NewTokenRevocation(identity.TokenRevocationScopeToken, tokenID, userID, l.tokenLifetime))
+}
+
+// RevokeSession revokes every access token issued so far for the session.
+func (l *TokenRevocationList) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	return l.revoke(ctx, identity.NewTokenRevocation(identity.TokenRevocationScopeSession, sessionID.String(), userID, l.tokenLifetime))
+}
+
+// RevokeUser revokes every access token issued so far to the user.
+func (l *TokenRevocationList) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	return l.revoke(ctx, identity.NewTokenRevocation(identity.TokenRevocationScopeUser, userID.String(), userID, l.tokenLifetime))
+}
+
+func (l *TokenRevocationList) revoke(ctx context.Context, revocation *identity.TokenRevocation) error {
+	if err := l.repo.Create(ctx, revocation); err != nil {
+		return fmt.Errorf("failed to store token revocation: %w", err)
+	}
+	l.mu.Lock()
+	l.add(revocation)
+	l.mu.Unlock()
+	return nil
+}
+
+// IsRevoked reports whether the token the claims belong to has been revoked, by its
+// jti, its session or its user. Tokens issued before jti was introduced can only be
+// revoked by session or user.
+func (l *TokenRevocationList) IsRevoked(claims *tokens.Claims) bool {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	keys := []tokenRevocationKey{
+		{identity.TokenRevocationScopeSession, claims.SessionID.String()},
+		{identity.TokenRevocationScopeUser, claims.UserID.String()},
+	}
+	if claims.ID != "" {
+		keys = append(keys, tokenRevocationKey{identity.TokenRevocationScopeToken, claims.ID})
+	}
+
+	now := time.Now().UTC()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, key := range keys {
+		if revocation, ok := l.entries[key]; ok && !revocation.IsExpired(now) && revocation.Covers(issuedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync loads the stored revocations into memory and forgets expired ones. Revocations
+// are never lifted, so entries are merged rather than replaced and one made locally
+// while Sync runs is kept.
+func (l *TokenRevocationList) Sync(ctx context.Context) error {
+	now := time.Now().UTC()
+	revocations, err := l.repo.ListActive(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to load token revocations: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, revocation := range revocations {
+		l.add(revocation)
+	}
+	for key, revocation := range l.entries {
+		if revocation.IsExpired(now) {
+			delete(l.entries, key)
+		}
+	}
+	return nil
+}
+
+// Run syncs the list and deletes expired revocations from storage every interval
+// until ctx is cancelled.
+func (l *TokenRevocationList) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Sync(ctx); err != nil {
+				l.logger.Warn("failed to sync token revocations", zap.Error(err))
+			}
+			if err := l.repo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+				l.logger.Warn("failed to delete expired token revocations", zap.Error(err))
+			}
+		}
+	}
+}
+
+// add keeps the later of two revocations of the same scope and key. Callers hold mu.
+func (l *TokenRevocationList) add(revocation *identity.TokenRevocation) {
+	key := tokenRevocationKey{revocation.Scope(), revocation.Key()}
+	if existing, ok := l.entries[key]; ok && !revocation.RevokedAt().After(existing.RevokedAt()) {
+		return
+	}
+	l.entries[key] = revocation
+}
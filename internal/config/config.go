@@ -10,6 +10,57 @@ type ServiceConfig struct {
 	AppEnv    string
 	DBConfig  config.DatabaseConfig
 	JWTConfig config.JWTConfig
+	// TokenPepper keys the HMAC under which refresh and reset tokens are stored.
+	TokenPepper string
+	// SigningKeysDir holds the <kid>.pem private keys access tokens are signed with.
+	SigningKeysDir string
+	// ActiveKeyID names the key in SigningKeysDir that signs new tokens; all others only verify.
+	ActiveKeyID string
+	// SigningAlgorithm is RS256 or EdDSA; used when generating a development key.
+	SigningAlgorithm string
+	// TokenIssuer is the "iss" claim of issued tokens.
+	TokenIssuer string
+	// EmailVerificationRequiredRoles is a comma-separated list of roles that must verify
+	// their email before protected actions.
+	EmailVerificationRequiredRoles string
+	// MFAIssuer is the account issuer authenticator apps display next to codes.
+	MFAIssuer string
+	// MFAEncryptionKey keys the encryption of authenticator secrets at rest.
+	MFAEncryptionKey string
+	// WebAuthnRPID is the domain passkeys are bound to, e.g. "kilat.my".
+	WebAuthnRPID string
+	// WebAuthnRPName is the service name authenticators show when registering a passkey.
+	WebAuthnRPName string
+	// WebAuthnOrigins is a comma-separated list of the web origins passkey ceremonies may run on.
+	WebAuthnOrigins string
+	// RegistrationMode is "immediate" (the default) or "verify_first"; see application.RegistrationMode.
+	RegistrationMode string
+	// PasswordHashAlgorithm is "bcrypt" (the default) or "argon2id" for new password hashes.
+	PasswordHashAlgorithm string
+	// BcryptCost is the bcrypt cost for new hashes; 0 means bcrypt's default.
+	BcryptCost int
+	// Argon2idMemoryKiB, Argon2idIterations and Argon2idParallelism are the argon2id
+	// parameters for new hashes; 0 means the package default.
+	Argon2idMemoryKiB   int
+	Argon2idIterations  int
+	Argon2idParallelism int
+	// PasswordMinLength is the minimum length of new passwords; 0 means 8.
+	PasswordMinLength int
+	// BreachedPasswordsFile optionally names a local file of SHA-1 prefixes of breached
+	// passwords that new passwords are checked against.
+	BreachedPasswordsFile string
+	// IntrospectionCacheTTL is how long introspection answers are cached, e.g. "10s";
+	// empty means 10 seconds.
+	IntrospectionCacheTTL string
+	// OIDCIssuer is the public base URL of the OpenID Connect provider, e.g.
+	// "https://id.kilat.my". Outside development, leaving it unset disables OIDC.
+	OIDCIssuer string
+	// GoogleClientIDs is a comma-separated list of the OAuth client IDs of our apps at
+	// Google; Sign in with Google is off when empty.
+	GoogleClientIDs string
+	// AppleClientIDs is a comma-separated list of the bundle and services IDs of our
+	// apps at Apple; Sign in with Apple is off when empty.
+	AppleClientIDs string
 }
 
 // Load reads the service configuration from environment variables.
@@ -20,9 +71,32 @@ func Load() (*ServiceConfig, error) {
 	}
 
 	return &ServiceConfig{
-		Port:      config.GetServicePort(v, "SERVICE_PORT"),
-		AppEnv:    config.GetAppEnv(v),
-		DBConfig:  config.LoadDatabaseConfig(v, "DB_NAME"),
-		JWTConfig: config.LoadJWTConfig(v),
+		Port:                           config.GetServicePort(v, "SERVICE_PORT"),
+		AppEnv:                         config.GetAppEnv(v),
+		DBConfig:                       config.LoadDatabaseConfig(v, "DB_NAME"),
+		JWTConfig:                      config.LoadJWTConfig(v),
+		TokenPepper:                    v.GetString("TOKEN_PEPPER"),
+		SigningKeysDir:                 v.GetString("JWT_KEYS_DIR"),
+		ActiveKeyID:                    v.GetString("JWT_ACTIVE_KID"),
+		SigningAlgorithm:               v.GetString("JWT_SIGNING_ALG"),
+		TokenIssuer:                    v.GetString("JWT_ISSUER"),
+		EmailVerificationRequiredRoles: v.GetString("EMAIL_VERIFICATION_REQUIRED_ROLES"),
+		MFAIssuer:                      v.GetString("MFA_ISSUER"),
+		MFAEncryptionKey:               v.GetString("MFA_ENCRYPTION_KEY"),
+		WebAuthnRPID:                   v.GetString("WEBAUTHN_RP_ID"),
+		WebAuthnRPName:                 v.GetString("WEBAUTHN_RP_NAME"),
+		WebAuthnOrigins:                v.GetString("WEBAUTHN_ORIGINS"),
+		RegistrationMode:               v.GetString("REGISTRATION_MODE"),
+		PasswordHashAlgorithm:          v.GetString("PASSWORD_HASH_ALGORITHM"),
+		BcryptCost:                     v.GetInt("PASSWORD_BCRYPT_COST"),
+		Argon2idMemoryKiB:              v.GetInt("PASSWORD_ARGON2_MEMORY_KIB"),
+		Argon2idIterations:             v.GetInt("PASSWORD_ARGON2_ITERATIONS"),
+		Argon2idParallelism:            v.GetInt("PASSWORD_ARGON2_PARALLELISM"),
+		PasswordMinLength:              v.GetInt("PASSWORD_MIN_LENGTH"),
+		BreachedPasswordsFile:          v.GetString("BREACHED_PASSWORDS_FILE"),
+		IntrospectionCacheTTL:          v.GetString("INTROSPECTION_CACHE_TTL"),
+		OIDCIssuer:                     v.GetString("OIDC_ISSUER"),
+		GoogleClientIDs:                v.GetString("GOOGLE_CLIENT_IDS"),
+		AppleClientIDs:                 v.GetString("APPLE_CLIENT_IDS"),
 	}, nil
 }
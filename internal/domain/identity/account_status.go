@@ -0,0 +1,105 @@
+package identity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountStatus is the lifecycle state of a user account.
+type AccountStatus string
+
+const (
+	// AccountActive accounts may sign in and obtain tokens.
+	AccountActive AccountStatus = "active"
+	// AccountSuspended accounts are blocked, optionally until an expiry after which they become active again.
+	AccountSuspended AccountStatus = "suspended"
+	// AccountBanned accounts are blocked until an admin lifts the ban.
+	AccountBanned AccountStatus = "banned"
+	// AccountDeleted accounts are closed and can never sign in again.
+	AccountDeleted AccountStatus = "deleted"
+)
+
+var (
+	// ErrAccountSuspended is returned when a suspended account tries to authenticate.
+	ErrAccountSuspended = errors.New("account is suspended")
+	// ErrAccountBanned is returned when a banned account tries to authenticate.
+	ErrAccountBanned = errors.New("account is banned")
+	// ErrAccountDeleted is returned when a deleted account tries to authenticate.
+	ErrAccountDeleted = errors.New("account is deleted")
+)
+
+// AccountStanding records an account's status together with why, by whom and
+// until when it was set.
+type AccountStanding struct {
+	status    AccountStatus
+	reason    string
+	changedBy *uuid.UUID
+	changedAt time.Time
+	expiresAt *time.Time
+}
+
+// NewActiveStanding returns the standing of a freshly created account.
+func NewActiveStanding(now time.Time) AccountStanding {
+	return AccountStanding{status: AccountActive, changedAt: now}
+}
+
+// ReconstructAccountStanding rebuilds an AccountStanding from persistence data.
+func ReconstructAccountStanding(
+	status AccountStatus,
+	reason string,
+	changedBy *uuid.UUID,
+	changedAt time.Time,
+	expiresAt *time.Time,
+) AccountStanding {
+	if status == "" {
+		status = AccountActive
+	}
+	return AccountStanding{
+		status:    status,
+		reason:    reason,
+		changedBy: changedBy,
+		changedAt: changedAt,
+		expiresAt: expiresAt,
+	}
+}
+
+// StoredStatus returns the status as persisted, without applying suspension expiry.
+func (s AccountStanding) StoredStatus() AccountStatus { return s.status }
+
+// Status returns the effective status at the given time. A suspension whose
+// expiry has passed is reported as active.
+func (s AccountStanding) Status(now time.Time) AccountStatus {
+	if s.status == AccountSuspended && s.expiresAt != nil && !now.Before(*s.expiresAt) {
+		return AccountActive
+	}
+	return s.status
+}
+
+// Reason returns the reason given for the current status.
+func (s AccountStanding) Reason() string { return s.reason }
+
+// ChangedBy returns the admin who last changed the status, or nil for system changes.
+func (s AccountStanding) ChangedBy() *uuid.UUID { return s.changedBy }
+
+// ChangedAt returns when the status was last changed.
+func (s AccountStanding) ChangedAt() time.Time { return s.changedAt }
+
+// ExpiresAt returns when a timed suspension ends, or nil if it does not expire.
+func (s AccountStanding) ExpiresAt() *time.Time { return s.expiresAt }
+
+// CheckCanAuthenticate returns an error describing why the account may not sign
+// in or obtain tokens at the given time, or nil if it may.
+func (s AccountStanding) CheckCanAuthenticate(now time.Time) error {
+	switch s.Status(now) {
+	case AccountSuspended:
+		return ErrAccountSuspended
+	case AccountBanned:
+		return ErrAccountBanned
+	case AccountDeleted:
+		return ErrAccountDeleted
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,174 @@
+package identity
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrAuthorizationCodeInvalid is returned when an authorization code cannot be
+// redeemed: it expired, was already used, or was issued to another client, redirect
+// URI or PKCE challenge.
+var ErrAuthorizationCodeInvalid = errors.New("authorization code is invalid")
+
+// AuthorizationCode is the single-use code an OpenID Connect client gets at its
+// redirect URI once the user has signed in, and exchanges for tokens. It is bound to
+// the client, the exact redirect URI and a PKCE S256 challenge (RFC 7636).
+type AuthorizationCode struct {
+	id            uuid.UUID
+	code          string
+	clientID      string
+	userID        uuid.UUID
+	redirectURI   string
+	scopes        []string
+	nonce         string
+	codeChallenge string
+	authMethods   []string
+	authTime      time.Time
+	sessionID     *uuid.UUID
+	expiresAt     time.Time
+	consumedAt    *time.Time
+	createdAt     time.Time
+}
+
+// NewAuthorizationCode creates a new, unused AuthorizationCode for a user who signed in
+// with authMethods at authTime.
+func NewAuthorizationCode(
+	code, clientID string,
+	userID uuid.UUID,
+	redirectURI string,
+	scopes []string,
+	nonce, codeChallenge string,
+	authMethods []string,
+	authTime, expiresAt time.Time,
+) *AuthorizationCode {
+	return &AuthorizationCode{
+		id:            uuid.New(),
+		code:          code,
+		clientID:      clientID,
+		userID:        userID,
+		redirectURI:   redirectURI,
+		scopes:        scopes,
+		nonce:         nonce,
+		codeChallenge: codeChallenge,
+		authMethods:   authMethods,
+		authTime:      authTime,
+		expiresAt:     expiresAt,
+		createdAt:     time.Now().UTC(),
+	}
+}
+
+// ReconstructAuthorizationCode rebuilds an AuthorizationCode from persistence data. The raw code is not known.
+func ReconstructAuthorizationCode(
+	id uuid.UUID,
+	clientID string,
+	userID uuid.UUID,
+	redirectURI string,
+	scopes []string,
+	nonce, codeChallenge string,
+	authMethods []string,
+	authTime time.Time,
+	sessionID *uuid.UUID,
+	expiresAt time.Time,
+	consumedAt *time.Time,
+	createdAt time.Time,
+) *AuthorizationCode {
+	return &AuthorizationCode{
+		id:            id,
+		clientID:      clientID,
+		userID:        userID,
+		redirectURI:   redirectURI,
+		scopes:        scopes,
+		nonce:         nonce,
+		codeChallenge: codeChallenge,
+		authMethods:   authMethods,
+		authTime:      authTime,
+		sessionID:     sessionID,
+		expiresAt:     expiresAt,
+		consumedAt:    consumedAt,
+		createdAt:     createdAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the code's unique identifier.
+func (a *AuthorizationCode) ID() uuid.UUID { return a.id }
+
+// Code returns the raw code if it was just created, or empty.
+func (a *AuthorizationCode) Code() string { return a.code }
+
+// ClientID returns the client the code was issued to.
+func (a *AuthorizationCode) ClientID() string { return a.clientID }
+
+// UserID returns the user who signed in.
+func (a *AuthorizationCode) UserID() uuid.UUID { return a.userID }
+
+// RedirectURI returns the redirect URI the code was sent to.
+func (a *AuthorizationCode) RedirectURI() string { return a.redirectURI }
+
+// Scopes returns the scopes granted with the code.
+func (a *AuthorizationCode) Scopes() []string { return a.scopes }
+
+// Nonce returns the client's nonce, echoed in the ID token, or empty.
+func (a *AuthorizationCode) Nonce() string { return a.nonce }
+
+// CodeChallenge returns the PKCE S256 challenge.
+func (a *AuthorizationCode) CodeChallenge() string { return a.codeChallenge }
+
+// AuthMethods returns how the user signed in.
+func (a *AuthorizationCode) AuthMethods() []string { return a.authMethods }
+
+// AuthTime returns when the user signed in.
+func (a *AuthorizationCode) AuthTime() time.Time { return a.authTime }
+
+// SessionID returns the session started when the code was redeemed, or nil.
+func (a *AuthorizationCode) SessionID() *uuid.UUID { return a.sessionID }
+
+// ExpiresAt returns the expiration timestamp.
+func (a *AuthorizationCode) ExpiresAt() time.Time { return a.expiresAt }
+
+// ConsumedAt returns when the code was redeemed, or nil.
+func (a *AuthorizationCode) ConsumedAt() *time.Time { return a.consumedAt }
+
+// CreatedAt returns the creation timestamp.
+func (a *AuthorizationCode) CreatedAt() time.Time { return a.createdAt }
+
+// --- Behavior ---
+
+// IsConsumed returns true if the code has already been redeemed.
+func (a *AuthorizationCode) IsConsumed() bool { return a.consumedAt != nil }
+
+// IsExpired checks whether the code has expired.
+func (a *AuthorizationCode) IsExpired() bool {
+	return time.Now().UTC().After(a.expiresAt)
+}
+
+// CheckRedemption verifies that a token request may redeem the code: it is unused and
+// unexpired, and the client, redirect URI and PKCE code verifier all match.
+func (a *AuthorizationCode) CheckRedemption(clientID, redirectURI, codeVerifier string) error {
+	if a.IsConsumed() || a.IsExpired() {
+		return ErrAuthorizationCodeInvalid
+	}
+	if clientID != a.clientID || redirectURI != a.redirectURI {
+		return ErrAuthorizationCodeInvalid
+	}
+	if !VerifyPKCE(a.codeChallenge, codeVerifier) {
+		return ErrAuthorizationCodeInvalid
+	}
+	return nil
+}
+
+// VerifyPKCE reports whether verifier matches an S256 code challenge (RFC 7636 section 4.6).
+func VerifyPKCE(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
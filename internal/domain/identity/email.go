@@ -8,7 +8,11 @@ import (
 
 var emailRegexp = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
-// Email is a value object representing a validated email address.
+// gmailDomains deliver to the same Gmail mailbox whatever dots the local part contains.
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// Email is a value object representing a validated email address. Addresses are
+// stored and compared in lowercase, so every lookup by email should go through NewEmail.
 type Email struct {
 	value string
 }
@@ -30,3 +34,22 @@ func (e Email) String() string { return e.value }
 
 // Equals checks equality with another Email.
 func (e Email) Equals(other Email) bool { return e.value == other.value }
+
+// Canonical returns the mailbox the address most likely delivers to, for spotting one
+// person behind several accounts: a "+tag" suffix is dropped from the local part and,
+// for Gmail, so are dots, with googlemail.com folded into gmail.com. It is a matching
+// key only; mail always goes to the address as given.
+func (e Email) Canonical() string {
+	local, domain, ok := strings.Cut(e.value, "@")
+	if !ok {
+		return e.value
+	}
+	if tagged, _, found := strings.Cut(local, "+"); found && tagged != "" {
+		local = tagged
+	}
+	if gmailDomains[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
@@ -0,0 +1,161 @@
+package identity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChangeStatus is the derived lifecycle state of an EmailChange.
+type EmailChangeStatus string
+
+const (
+	// EmailChangePending changes wait for the new address to confirm them.
+	EmailChangePending EmailChangeStatus = "pending"
+	// EmailChangeConfirmed changes have moved the account to the new address.
+	EmailChangeConfirmed EmailChangeStatus = "confirmed"
+	// EmailChangeCancelled changes were called off from the old address or replaced by a newer request.
+	EmailChangeCancelled EmailChangeStatus = "cancelled"
+	// EmailChangeExpired changes passed their expiry without being confirmed.
+	EmailChangeExpired EmailChangeStatus = "expired"
+)
+
+// ErrEmailChangeNotPending is returned when confirming or cancelling an email change
+// that is already confirmed, cancelled or expired.
+var ErrEmailChangeNotPending = errors.New("email change is no longer pending")
+
+// EmailChange is a user's request to move their account to a new email address.
+// It carries two single-use tokens: the confirm token goes to the new address and
+// proves the user controls it, the cancel token goes to the old address so its
+// owner can stop a change they did not ask for. Neither token is stored in clear.
+type EmailChange struct {
+	id           uuid.UUID
+	userID       uuid.UUID
+	oldEmail     string
+	newEmail     Email
+	confirmToken string
+	cancelToken  string
+	expiresAt    time.Time
+	confirmedAt  *time.Time
+	cancelledAt  *time.Time
+	createdAt    time.Time
+}
+
+// NewEmailChange creates a new pending EmailChange from oldEmail to newEmail.
+func NewEmailChange(userID uuid.UUID, oldEmail, newEmail, confirmToken, cancelToken string, expiresAt time.Time) (*EmailChange, error) {
+	emailVO, err := NewEmail(newEmail)
+	if err != nil {
+		return nil, err
+	}
+	return &EmailChange{
+		id:           uuid.New(),
+		userID:       userID,
+		oldEmail:     oldEmail,
+		newEmail:     emailVO,
+		confirmToken: confirmToken,
+		cancelToken:  cancelToken,
+		expiresAt:    expiresAt,
+		createdAt:    time.Now().UTC(),
+	}, nil
+}
+
+// ReconstructEmailChange rebuilds an EmailChange from persistence data. Only the
+// token it was looked up by is known; the other is empty.
+func ReconstructEmailChange(
+	id, userID uuid.UUID,
+	oldEmail, newEmail string,
+	confirmToken, cancelToken string,
+	expiresAt time.Time,
+	confirmedAt, cancelledAt *time.Time,
+	createdAt time.Time,
+) *EmailChange {
+	return &EmailChange{
+		id:           id,
+		userID:       userID,
+		oldEmail:     oldEmail,
+		newEmail:     Email{value: newEmail},
+		confirmToken: confirmToken,
+		cancelToken:  cancelToken,
+		expiresAt:    expiresAt,
+		confirmedAt:  confirmedAt,
+		cancelledAt:  cancelledAt,
+		createdAt:    createdAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the email change's unique identifier.
+func (c *EmailChange) ID() uuid.UUID { return c.id }
+
+// UserID returns the owning user's ID.
+func (c *EmailChange) UserID() uuid.UUID { return c.userID }
+
+// OldEmail returns the address the account had when the change was requested.
+func (c *EmailChange) OldEmail() string { return c.oldEmail }
+
+// NewEmail returns the address the account moves to once confirmed.
+func (c *EmailChange) NewEmail() string { return c.newEmail.String() }
+
+// NewEmailVO returns the new address as a value object.
+func (c *EmailChange) NewEmailVO() Email { return c.newEmail }
+
+// ConfirmToken returns the raw token sent to the new address, if known.
+func (c *EmailChange) ConfirmToken() string { return c.confirmToken }
+
+// CancelToken returns the raw token sent to the old address, if known.
+func (c *EmailChange) CancelToken() string { return c.cancelToken }
+
+// ExpiresAt returns the expiration timestamp.
+func (c *EmailChange) ExpiresAt() time.Time { return c.expiresAt }
+
+// ConfirmedAt returns when the change was confirmed, or nil.
+func (c *EmailChange) ConfirmedAt() *time.Time { return c.confirmedAt }
+
+// CancelledAt returns when the change was cancelled, or nil.
+func (c *EmailChange) CancelledAt() *time.Time { return c.cancelledAt }
+
+// CreatedAt returns the creation timestamp.
+func (c *EmailChange) CreatedAt() time.Time { return c.createdAt }
+
+// --- Behavior ---
+
+// Status returns the change's current lifecycle state.
+func (c *EmailChange) Status() EmailChangeStatus {
+	switch {
+	case c.confirmedAt != nil:
+		return EmailChangeConfirmed
+	case c.cancelledAt != nil:
+		return EmailChangeCancelled
+	case time.Now().UTC().After(c.expiresAt):
+		return EmailChangeExpired
+	default:
+		return EmailChangePending
+	}
+}
+
+// IsPending returns true if the change can still be confirmed or cancelled.
+func (c *EmailChange) IsPending() bool {
+	return c.Status() == EmailChangePending
+}
+
+// Confirm records that the new address confirmed the change.
+func (c *EmailChange) Confirm() error {
+	if !c.IsPending() {
+		return ErrEmailChangeNotPending
+	}
+	now := time.Now().UTC()
+	c.confirmedAt = &now
+	return nil
+}
+
+// Cancel calls off a pending change.
+func (c *EmailChange) Cancel() error {
+	if !c.IsPending() {
+		return ErrEmailChangeNotPending
+	}
+	now := time.Now().UTC()
+	c.cancelledAt = &now
+	return nil
+}
@@ -0,0 +1,91 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerification represents a single-use token proving control of an email address.
+// The address is recorded so that a token issued before an email change cannot verify the new one.
+type EmailVerification struct {
+	id        uuid.UUID
+	userID    uuid.UUID
+	email     string
+	token     string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// NewEmailVerification creates a new EmailVerification for a user's email address.
+func NewEmailVerification(userID uuid.UUID, email, token string, expiresAt time.Time) *EmailVerification {
+	return &EmailVerification{
+		id:        uuid.New(),
+		userID:    userID,
+		email:     email,
+		token:     token,
+		expiresAt: expiresAt,
+		usedAt:    nil,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// ReconstructEmailVerification rebuilds an EmailVerification from persistence data.
+func ReconstructEmailVerification(
+	id, userID uuid.UUID,
+	email, token string,
+	expiresAt time.Time,
+	usedAt *time.Time,
+	createdAt time.Time,
+) *EmailVerification {
+	return &EmailVerification{
+		id:        id,
+		userID:    userID,
+		email:     email,
+		token:     token,
+		expiresAt: expiresAt,
+		usedAt:    usedAt,
+		createdAt: createdAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the verification token's unique identifier.
+func (v *EmailVerification) ID() uuid.UUID { return v.id }
+
+// UserID returns the owning user's ID.
+func (v *EmailVerification) UserID() uuid.UUID { return v.userID }
+
+// Email returns the address the token verifies.
+func (v *EmailVerification) Email() string { return v.email }
+
+// Token returns the verification token string.
+func (v *EmailVerification) Token() string { return v.token }
+
+// ExpiresAt returns the expiration timestamp.
+func (v *EmailVerification) ExpiresAt() time.Time { return v.expiresAt }
+
+// UsedAt returns when the token was used, or nil if unused.
+func (v *EmailVerification) UsedAt() *time.Time { return v.usedAt }
+
+// CreatedAt returns the creation timestamp.
+func (v *EmailVerification) CreatedAt() time.Time { return v.createdAt }
+
+// --- Behavior ---
+
+// IsExpired checks whether the token has expired.
+func (v *EmailVerification) IsExpired() bool {
+	return time.Now().UTC().After(v.expiresAt)
+}
+
+// IsUsed returns true if the token has already been used.
+func (v *EmailVerification) IsUsed() bool {
+	return v.usedAt != nil
+}
+
+// IsValid returns true if the token is neither used nor expired.
+func (v *EmailVerification) IsValid() bool {
+	return !v.IsUsed() && !v.IsExpired()
+}
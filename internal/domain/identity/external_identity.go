@@ -0,0 +1,90 @@
+package identity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrExternalIdentityAlreadyLinked is returned when a provider account is already
+// linked to a user, or the user already has an account of that provider linked.
+var ErrExternalIdentityAlreadyLinked = errors.New("external identity is already linked")
+
+// ExternalIdentity links an account at an external identity provider, such as Google
+// or Apple, to a user so the user can sign in with it. The provider's subject is the
+// stable identifier of that account; the email is only what the provider last reported.
+type ExternalIdentity struct {
+	id         uuid.UUID
+	userID     uuid.UUID
+	provider   string
+	subject    string
+	email      string
+	createdAt  time.Time
+	lastUsedAt *time.Time
+}
+
+// NewExternalIdentity links the provider account subject to a user.
+func NewExternalIdentity(userID uuid.UUID, provider, subject, email string) *ExternalIdentity {
+	return &ExternalIdentity{
+		id:        uuid.New(),
+		userID:    userID,
+		provider:  provider,
+		subject:   subject,
+		email:     email,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// ReconstructExternalIdentity rebuilds an ExternalIdentity from persistence data.
+func ReconstructExternalIdentity(
+	id, userID uuid.UUID,
+	provider, subject, email string,
+	createdAt time.Time,
+	lastUsedAt *time.Time,
+) *ExternalIdentity {
+	return &ExternalIdentity{
+		id:         id,
+		userID:     userID,
+		provider:   provider,
+		subject:    subject,
+		email:      email,
+		createdAt:  createdAt,
+		lastUsedAt: lastUsedAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the link's unique identifier.
+func (e *ExternalIdentity) ID() uuid.UUID { return e.id }
+
+// UserID returns the user the provider account is linked to.
+func (e *ExternalIdentity) UserID() uuid.UUID { return e.userID }
+
+// Provider returns the name of the identity provider, e.g. "google".
+func (e *ExternalIdentity) Provider() string { return e.provider }
+
+// Subject returns the provider's identifier of the account.
+func (e *ExternalIdentity) Subject() string { return e.subject }
+
+// Email returns the email address the provider reported for the account.
+func (e *ExternalIdentity) Email() string { return e.email }
+
+// CreatedAt returns when the account was linked.
+func (e *ExternalIdentity) CreatedAt() time.Time { return e.createdAt }
+
+// LastUsedAt returns when the account was last used to sign in, or nil if never.
+func (e *ExternalIdentity) LastUsedAt() *time.Time { return e.lastUsedAt }
+
+// --- Behaviors ---
+
+// RecordUse records a sign-in with the provider account and the email it reported,
+// if any; Apple may leave the email out after the first sign-in.
+func (e *ExternalIdentity) RecordUse(email string) {
+	now := time.Now().UTC()
+	if email != "" {
+		e.email = email
+	}
+	e.lastUsedAt = &now
+}
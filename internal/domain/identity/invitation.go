@@ -0,0 +1,164 @@
+package identity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/google/uuid"
+)
+
+// InvitationStatus is the derived lifecycle state of an Invitation.
+type InvitationStatus string
+
+const (
+	// InvitationPending invitations can still be accepted.
+	InvitationPending InvitationStatus = "pending"
+	// InvitationAccepted invitations have been used to create an account.
+	InvitationAccepted InvitationStatus = "accepted"
+	// InvitationRevoked invitations were withdrawn by an admin.
+	InvitationRevoked InvitationStatus = "revoked"
+	// InvitationExpired invitations passed their expiry without being accepted.
+	InvitationExpired InvitationStatus = "expired"
+)
+
+// ErrInvitationNotPending is returned when accepting or revoking an invitation
+// that is already accepted, revoked or expired.
+var ErrInvitationNotPending = errors.New("invitation is no longer pending")
+
+// Invitation is a single-use, expiring offer from an admin for an email address
+// to create an account with a given role.
+type Invitation struct {
+	id             uuid.UUID
+	email          Email
+	role           auth.UserRole
+	token          string
+	invitedBy      uuid.UUID
+	expiresAt      time.Time
+	acceptedAt     *time.Time
+	acceptedUserID *uuid.UUID
+	revokedAt      *time.Time
+	createdAt      time.Time
+}
+
+// NewInvitation creates a new pending Invitation.
+func NewInvitation(email string, role auth.UserRole, invitedBy uuid.UUID, token string, expiresAt time.Time) (*Invitation, error) {
+	emailVO, err := NewEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return &Invitation{
+		id:        uuid.New(),
+		email:     emailVO,
+		role:      role,
+		token:     token,
+		invitedBy: invitedBy,
+		expiresAt: expiresAt,
+		createdAt: time.Now().UTC(),
+	}, nil
+}
+
+// ReconstructInvitation rebuilds an Invitation from persistence data.
+func ReconstructInvitation(
+	id uuid.UUID,
+	email string,
+	role auth.UserRole,
+	token string,
+	invitedBy uuid.UUID,
+	expiresAt time.Time,
+	acceptedAt *time.Time,
+	acceptedUserID *uuid.UUID,
+	revokedAt *time.Time,
+	createdAt time.Time,
+) *Invitation {
+	return &Invitation{
+		id:             id,
+		email:          Email{value: email},
+		role:           role,
+		token:          token,
+		invitedBy:      invitedBy,
+		expiresAt:      expiresAt,
+		acceptedAt:     acceptedAt,
+		acceptedUserID: acceptedUserID,
+		revokedAt:      revokedAt,
+		createdAt:      createdAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the invitation's unique identifier.
+func (i *Invitation) ID() uuid.UUID { return i.id }
+
+// Email returns the invited email address.
+func (i *Invitation) Email() string { return i.email.String() }
+
+// EmailVO returns the invited email address as a value object.
+func (i *Invitation) EmailVO() Email { return i.email }
+
+// Role returns the role the account will be created with.
+func (i *Invitation) Role() auth.UserRole { return i.role }
+
+// Token returns the raw invitation token. It is empty when the invitation was
+// loaded without its token, since only the token hash is stored.
+func (i *Invitation) Token() string { return i.token }
+
+// InvitedBy returns the ID of the admin who sent the invitation.
+func (i *Invitation) InvitedBy() uuid.UUID { return i.invitedBy }
+
+// ExpiresAt returns the expiration timestamp.
+func (i *Invitation) ExpiresAt() time.Time { return i.expiresAt }
+
+// AcceptedAt returns when the invitation was accepted, or nil.
+func (i *Invitation) AcceptedAt() *time.Time { return i.acceptedAt }
+
+// AcceptedUserID returns the account created from the invitation, or nil.
+func (i *Invitation) AcceptedUserID() *uuid.UUID { return i.acceptedUserID }
+
+// RevokedAt returns when the invitation was revoked, or nil.
+func (i *Invitation) RevokedAt() *time.Time { return i.revokedAt }
+
+// CreatedAt returns the creation timestamp.
+func (i *Invitation) CreatedAt() time.Time { return i.createdAt }
+
+// --- Behavior ---
+
+// Status returns the invitation's current lifecycle state.
+func (i *Invitation) Status() InvitationStatus {
+	switch {
+	case i.acceptedAt != nil:
+		return InvitationAccepted
+	case i.revokedAt != nil:
+		return InvitationRevoked
+	case time.Now().UTC().After(i.expiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+// IsPending returns true if the invitation can still be accepted or revoked.
+func (i *Invitation) IsPending() bool {
+	return i.Status() == InvitationPending
+}
+
+// Accept records that the invitation was used to create the given account.
+func (i *Invitation) Accept(userID uuid.UUID) error {
+	if !i.IsPending() {
+		return ErrInvitationNotPending
+	}
+	now := time.Now().UTC()
+	i.acceptedAt = &now
+	i.acceptedUserID = &userID
+	return nil
+}
+
+// Revoke withdraws a pending invitation.
+func (i *Invitation) Revoke() error {
+	if !i.IsPending() {
+		return ErrInvitationNotPending
+	}
+	now := time.Now().UTC()
+	i.revokedAt = &now
+	return nil
+}
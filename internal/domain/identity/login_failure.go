@@ -0,0 +1,56 @@
+package identity
+
+import (
+	"time"
+)
+
+// LoginFailureScope is what a failed sign-in counter is kept for.
+type LoginFailureScope string
+
+const (
+	// LoginFailureScopeAccount counts failures against one account, keyed by its normalized email.
+	LoginFailureScopeAccount LoginFailureScope = "account"
+	// LoginFailureScopeIP counts failures from one client IP address across all accounts.
+	LoginFailureScopeIP LoginFailureScope = "ip"
+)
+
+// LoginFailure is the running count of consecutive failed sign-ins for one account or
+// IP address, and the lockout it earned, if any. A success resets the account count.
+type LoginFailure struct {
+	scope        LoginFailureScope
+	key          string
+	failures     int
+	lastFailedAt time.Time
+	lockedUntil  *time.Time
+}
+
+// ReconstructLoginFailure rebuilds a LoginFailure from persistence data.
+func ReconstructLoginFailure(scope LoginFailureScope, key string, failures int, lastFailedAt time.Time, lockedUntil *time.Time) *LoginFailure {
+	return &LoginFailure{
+		scope:        scope,
+		key:          key,
+		failures:     failures,
+		lastFailedAt: lastFailedAt,
+		lockedUntil:  lockedUntil,
+	}
+}
+
+// Scope returns what the counter is kept for.
+func (f *LoginFailure) Scope() LoginFailureScope { return f.scope }
+
+// Key returns the normalized email or IP address the counter is kept for.
+func (f *LoginFailure) Key() string { return f.key }
+
+// Failures returns the number of consecutive failures.
+func (f *LoginFailure) Failures() int { return f.failures }
+
+// LastFailedAt returns when the most recent failure happened.
+func (f *LoginFailure) LastFailedAt() time.Time { return f.lastFailedAt }
+
+// LockedUntil returns when the current or last lockout ends, or nil if there was none.
+func (f *LoginFailure) LockedUntil() *time.Time { return f.lockedUntil }
+
+// IsLocked reports whether the lockout is in effect at the given time.
+func (f *LoginFailure) IsLocked(now time.Time) bool {
+	return f.lockedUntil != nil && now.Before(*f.lockedUntil)
+}
@@ -0,0 +1,238 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOAuthClientDisabled is returned when using, rotating or disabling a disabled client.
+	ErrOAuthClientDisabled = errors.New("oauth client is disabled")
+	// ErrScopeNotAllowed is returned when a client asks for a scope it was not granted.
+	ErrScopeNotAllowed = errors.New("scope not allowed for this client")
+)
+
+// scopePattern is the form of a scope name, e.g. "bookings:read".
+var scopePattern = regexp.MustCompile(`^[a-z][a-z0-9_.:-]{0,63}$`)
+
+// OAuthClient is an application registered with the service: another service
+// obtaining access tokens of its own with the client credentials grant, or a web app
+// signing users in with OpenID Connect, which needs redirect URIs. Its secret is only
+// known in clear when it is created or rotated; storage keeps a keyed hash.
+type OAuthClient struct {
+	id              uuid.UUID
+	clientID        string
+	name            string
+	secret          string
+	scopes          []string
+	redirectURIs    []string
+	createdBy       uuid.UUID
+	secretRotatedAt time.Time
+	disabledAt      *time.Time
+	createdAt       time.Time
+	updatedAt       time.Time
+}
+
+// NewOAuthClient creates a new enabled OAuthClient allowed the given scopes and
+// redirect URIs. Clients without redirect URIs cannot sign users in.
+func NewOAuthClient(clientID, name, secret string, scopes, redirectURIs []string, createdBy uuid.UUID) (*OAuthClient, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("client name is required")
+	}
+	normalized, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	redirects, err := normalizeRedirectURIs(redirectURIs)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	return &OAuthClient{
+		id:              uuid.New(),
+		clientID:        clientID,
+		name:            name,
+		secret:          secret,
+		scopes:          normalized,
+		redirectURIs:    redirects,
+		createdBy:       createdBy,
+		secretRotatedAt: now,
+		createdAt:       now,
+		updatedAt:       now,
+	}, nil
+}
+
+// ReconstructOAuthClient rebuilds an OAuthClient from persistence data. The secret is not known.
+func ReconstructOAuthClient(
+	id uuid.UUID,
+	clientID, name string,
+	scopes, redirectURIs []string,
+	createdBy uuid.UUID,
+	secretRotatedAt time.Time,
+	disabledAt *time.Time,
+	createdAt, updatedAt time.Time,
+) *OAuthClient {
+	return &OAuthClient{
+		id:              id,
+		clientID:        clientID,
+		name:            name,
+		scopes:          scopes,
+		redirectURIs:    redirectURIs,
+		createdBy:       createdBy,
+		secretRotatedAt: secretRotatedAt,
+		disabledAt:      disabledAt,
+		createdAt:       createdAt,
+		updatedAt:       updatedAt,
+	}
+}
+
+// normalizeScopes checks each scope's form and drops duplicates, keeping order.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scopePattern.MatchString(scope) {
+			return nil, fmt.Errorf("invalid scope %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeRedirectURIs checks that each redirect URI is an absolute https URL without
+// a fragment, or plain http on a loopback host for local development, and drops
+// duplicates. URIs are kept exactly as given because they are matched exactly.
+func normalizeRedirectURIs(uris []string) ([]string, error) {
+	seen := make(map[string]bool, len(uris))
+	normalized := make([]string, 0, len(uris))
+	for _, raw := range uris {
+		u, err := url.Parse(raw)
+		if err != nil || !u.IsAbs() || u.Host == "" || u.Fragment != "" || u.User != nil {
+			return nil, fmt.Errorf("invalid redirect URI %q", raw)
+		}
+		loopback := u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1" || u.Hostname() == "::1"
+		if u.Scheme != "https" && !(u.Scheme == "http" && loopback) {
+			return nil, fmt.Errorf("redirect URI %q must use https", raw)
+		}
+		if !seen[raw] {
+			seen[raw] = true
+			normalized = append(normalized, raw)
+		}
+	}
+	return normalized, nil
+}
+
+// --- Getters ---
+
+// ID returns the client's internal identifier.
+func (c *OAuthClient) ID() uuid.UUID { return c.id }
+
+// ClientID returns the public identifier the client authenticates with.
+func (c *OAuthClient) ClientID() string { return c.clientID }
+
+// Name returns the client's display name, usually the calling service.
+func (c *OAuthClient) Name() string { return c.name }
+
+// Secret returns the raw secret if it was just created or rotated, or empty.
+func (c *OAuthClient) Secret() string { return c.secret }
+
+// Scopes returns the scopes the client may request.
+func (c *OAuthClient) Scopes() []string { return c.scopes }
+
+// RedirectURIs returns the URIs users may be sent back to after signing in.
+func (c *OAuthClient) RedirectURIs() []string { return c.redirectURIs }
+
+// CreatedBy returns the admin who registered the client.
+func (c *OAuthClient) CreatedBy() uuid.UUID { return c.createdBy }
+
+// SecretRotatedAt returns when the current secret was issued.
+func (c *OAuthClient) SecretRotatedAt() time.Time { return c.secretRotatedAt }
+
+// DisabledAt returns when the client was disabled, or nil.
+func (c *OAuthClient) DisabledAt() *time.Time { return c.disabledAt }
+
+// CreatedAt returns the creation timestamp.
+func (c *OAuthClient) CreatedAt() time.Time { return c.createdAt }
+
+// UpdatedAt returns the last modification timestamp.
+func (c *OAuthClient) UpdatedAt() time.Time { return c.updatedAt }
+
+// --- Behavior ---
+
+// IsActive returns true if the client may obtain tokens.
+func (c *OAuthClient) IsActive() bool { return c.disabledAt == nil }
+
+// HasRedirectURI reports whether uri is one of the client's registered redirect URIs.
+// The comparison is exact: no prefix, case or query string leeway.
+func (c *OAuthClient) HasRedirectURI(uri string) bool {
+	for _, registered := range c.redirectURIs {
+		if registered == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantScopes returns the scopes a token request gets: every allowed scope when none
+// are requested, otherwise the requested ones, which must all be allowed.
+func (c *OAuthClient) GrantScopes(requested []string) ([]string, error) {
+	if !c.IsActive() {
+		return nil, ErrOAuthClientDisabled
+	}
+	if len(requested) == 0 {
+		return c.scopes, nil
+	}
+	allowed := make(map[string]bool, len(c.scopes))
+	for _, scope := range c.scopes {
+		allowed[scope] = true
+	}
+	granted := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, scope := range requested {
+		if !allowed[scope] {
+			return nil, ErrScopeNotAllowed
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			granted = append(granted, scope)
+		}
+	}
+	return granted, nil
+}
+
+// RotateSecret replaces the client's secret. The old secret stops working at once.
+func (c *OAuthClient) RotateSecret(secret string) error {
+	if !c.IsActive() {
+		return ErrOAuthClientDisabled
+	}
+	now := time.Now().UTC()
+	c.secret = secret
+	c.secretRotatedAt = now
+	c.updatedAt = now
+	return nil
+}
+
+// Disable stops the client from obtaining new tokens. Tokens already issued stay
+// valid until they expire.
+func (c *OAuthClient) Disable() error {
+	if !c.IsActive() {
+		return ErrOAuthClientDisabled
+	}
+	now := time.Now().UTC()
+	c.disabledAt = &now
+	c.updatedAt = now
+	return nil
+}
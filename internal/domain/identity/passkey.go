@@ -0,0 +1,190 @@
+package identity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPasskeyAlreadyRegistered is returned when a credential ID is already registered.
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+	// ErrPasskeySignCountRegressed is returned when recording a use whose signature
+	// counter is not above the stored one, which suggests a cloned authenticator.
+	ErrPasskeySignCountRegressed = errors.New("passkey signature counter did not increase")
+	// ErrWebAuthnChallengeInvalid is returned when a ceremony challenge is unknown,
+	// expired, already used or was issued for another purpose or user.
+	ErrWebAuthnChallengeInvalid = errors.New("passkey challenge is invalid or expired")
+)
+
+// Passkey is a WebAuthn credential a user registered to sign in without a password.
+// Only the public key is stored; the private key never leaves the authenticator.
+type Passkey struct {
+	id           uuid.UUID
+	userID       uuid.UUID
+	credentialID []byte
+	publicKey    []byte
+	signCount    uint32
+	transports   []string
+	name         string
+	createdAt    time.Time
+	lastUsedAt   *time.Time
+}
+
+// NewPasskey creates a passkey from a verified registration. publicKey is the COSE_Key
+// the authenticator returned.
+func NewPasskey(userID uuid.UUID, credentialID, publicKey []byte, signCount uint32, transports []string, name string) *Passkey {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey"
+	}
+	return &Passkey{
+		id:           uuid.New(),
+		userID:       userID,
+		credentialID: credentialID,
+		publicKey:    publicKey,
+		signCount:    signCount,
+		transports:   transports,
+		name:         name,
+		createdAt:    time.Now().UTC(),
+	}
+}
+
+// ReconstructPasskey rebuilds a Passkey from persistence data.
+func ReconstructPasskey(
+	id, userID uuid.UUID,
+	credentialID, publicKey []byte,
+	signCount uint32,
+	transports []string,
+	name string,
+	createdAt time.Time,
+	lastUsedAt *time.Time,
+) *Passkey {
+	return &Passkey{
+		id:           id,
+		userID:       userID,
+		credentialID: credentialID,
+		publicKey:    publicKey,
+		signCount:    signCount,
+		transports:   transports,
+		name:         name,
+		createdAt:    createdAt,
+		lastUsedAt:   lastUsedAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the passkey's unique identifier.
+func (p *Passkey) ID() uuid.UUID { return p.id }
+
+// UserID returns the owner's ID.
+func (p *Passkey) UserID() uuid.UUID { return p.userID }
+
+// CredentialID returns the authenticator-assigned credential ID.
+func (p *Passkey) CredentialID() []byte { return p.credentialID }
+
+// PublicKey returns the credential public key in COSE_Key form.
+func (p *Passkey) PublicKey() []byte { return p.publicKey }
+
+// SignCount returns the last signature counter the authenticator reported.
+func (p *Passkey) SignCount() uint32 { return p.signCount }
+
+// Transports returns how the browser can reach the authenticator, e.g. "internal" or "hybrid".
+func (p *Passkey) Transports() []string { return p.transports }
+
+// Name returns the label the user gave the passkey.
+func (p *Passkey) Name() string { return p.name }
+
+// CreatedAt returns when the passkey was registered.
+func (p *Passkey) CreatedAt() time.Time { return p.createdAt }
+
+// LastUsedAt returns when the passkey last signed the user in, or nil.
+func (p *Passkey) LastUsedAt() *time.Time { return p.lastUsedAt }
+
+// --- Behavior ---
+
+// RecordUse records a successful sign-in with the authenticator's new signature counter.
+func (p *Passkey) RecordUse(signCount uint32) {
+	now := time.Now().UTC()
+	p.signCount = signCount
+	p.lastUsedAt = &now
+}
+
+// WebAuthnPurpose is the ceremony a WebAuthnChallenge was issued for.
+type WebAuthnPurpose string
+
+const (
+	// WebAuthnPurposeRegistration challenges register a new passkey for a signed-in user.
+	WebAuthnPurposeRegistration WebAuthnPurpose = "registration"
+	// WebAuthnPurposeLogin challenges sign a user in with an existing passkey.
+	WebAuthnPurposeLogin WebAuthnPurpose = "login"
+)
+
+// WebAuthnChallenge is a single-use random challenge an authenticator must sign,
+// held server-side between the begin and finish steps of a ceremony.
+type WebAuthnChallenge struct {
+	id        uuid.UUID
+	userID    *uuid.UUID
+	challenge []byte
+	purpose   WebAuthnPurpose
+	expiresAt time.Time
+	createdAt time.Time
+}
+
+// NewWebAuthnChallenge creates a challenge. userID is nil for sign-in, where the
+// user is not known until the authenticator picks a passkey.
+func NewWebAuthnChallenge(userID *uuid.UUID, challenge []byte, purpose WebAuthnPurpose, expiresAt time.Time) *WebAuthnChallenge {
+	return &WebAuthnChallenge{
+		id:        uuid.New(),
+		userID:    userID,
+		challenge: challenge,
+		purpose:   purpose,
+		expiresAt: expiresAt,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// ReconstructWebAuthnChallenge rebuilds a WebAuthnChallenge from persistence data.
+func ReconstructWebAuthnChallenge(id uuid.UUID, userID *uuid.UUID, challenge []byte, purpose WebAuthnPurpose, expiresAt, createdAt time.Time) *WebAuthnChallenge {
+	return &WebAuthnChallenge{
+		id:        id,
+		userID:    userID,
+		challenge: challenge,
+		purpose:   purpose,
+		expiresAt: expiresAt,
+		createdAt: createdAt,
+	}
+}
+
+// ID returns the challenge's unique identifier.
+func (c *WebAuthnChallenge) ID() uuid.UUID { return c.id }
+
+// UserID returns the user a registration challenge was issued to, or nil for sign-in.
+func (c *WebAuthnChallenge) UserID() *uuid.UUID { return c.userID }
+
+// Challenge returns the random bytes the authenticator signs.
+func (c *WebAuthnChallenge) Challenge() []byte { return c.challenge }
+
+// Purpose returns the ceremony the challenge was issued for.
+func (c *WebAuthnChallenge) Purpose() WebAuthnPurpose { return c.purpose }
+
+// ExpiresAt returns when the challenge stops being accepted.
+func (c *WebAuthnChallenge) ExpiresAt() time.Time { return c.expiresAt }
+
+// CreatedAt returns when the challenge was issued.
+func (c *WebAuthnChallenge) CreatedAt() time.Time { return c.createdAt }
+
+// IsValidFor reports whether the challenge can finish the given ceremony for the
+// given user (nil for sign-in) right now.
+func (c *WebAuthnChallenge) IsValidFor(purpose WebAuthnPurpose, userID *uuid.UUID) bool {
+	if c.purpose != purpose || time.Now().UTC().After(c.expiresAt) {
+		return false
+	}
+	if userID == nil || c.userID == nil {
+		return userID == nil && c.userID == nil
+	}
+	return *userID == *c.userID
+}
@@ -6,23 +6,50 @@ import (
 	"strings"
 )
 
-var phoneRegexp = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+// defaultCountryCode is assumed for numbers entered without an international prefix.
+const defaultCountryCode = "60"
 
-// Phone is a value object representing a validated phone number.
+var (
+	phoneRegexp          = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+	phoneSeparatorRegexp = regexp.MustCompile(`[\s\-().]`)
+	e164Regexp           = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+)
+
+// Phone is a value object representing a validated phone number in E.164 form
+// (e.g. +60123456789), so the same number always compares equal however it was typed.
 type Phone struct {
 	value string
 }
 
-// NewPhone creates a validated Phone value object. Empty string is allowed (optional field).
+// NewPhone creates a validated, normalized Phone value object. Empty string is allowed (optional field).
+// Separators are stripped; a leading 00 becomes +, and numbers without a country code are
+// taken to be Malaysian (012-345 6789 and 60123456789 both become +60123456789).
 func NewPhone(raw string) (Phone, error) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
 		return Phone{value: ""}, nil
 	}
-	if !phoneRegexp.MatchString(trimmed) {
+	compact := phoneSeparatorRegexp.ReplaceAllString(trimmed, "")
+	if !phoneRegexp.MatchString(compact) {
+		return Phone{}, fmt.Errorf("invalid phone format: %s", trimmed)
+	}
+
+	normalized := compact
+	switch {
+	case strings.HasPrefix(compact, "+"):
+	case strings.HasPrefix(compact, "00"):
+		normalized = "+" + compact[2:]
+	case strings.HasPrefix(compact, "0"):
+		normalized = "+" + defaultCountryCode + compact[1:]
+	case strings.HasPrefix(compact, defaultCountryCode):
+		normalized = "+" + compact
+	default:
+		normalized = "+" + defaultCountryCode + compact
+	}
+	if !e164Regexp.MatchString(normalized) {
 		return Phone{}, fmt.Errorf("invalid phone format: %s", trimmed)
 	}
-	return Phone{value: trimmed}, nil
+	return Phone{value: normalized}, nil
 }
 
 // String returns the phone number as a string.
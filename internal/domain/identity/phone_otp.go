@@ -0,0 +1,143 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OTPPurpose distinguishes what a one-time code was issued for, so a code sent for
+// one flow cannot be redeemed in another.
+type OTPPurpose string
+
+const (
+	// OTPPurposePhoneVerification codes prove control of a user's phone number.
+	OTPPurposePhoneVerification OTPPurpose = "phone_verification"
+	// OTPPurposeLogin codes sign a user in without a password.
+	OTPPurposeLogin OTPPurpose = "login"
+)
+
+// PhoneOTP is a short-lived one-time code sent by SMS. Only a keyed hash of the
+// code is kept; wrong guesses are counted and the code is dead once they reach
+// the limit.
+type PhoneOTP struct {
+	id          uuid.UUID
+	userID      *uuid.UUID
+	phone       string
+	purpose     OTPPurpose
+	codeHash    string
+	attempts    int
+	maxAttempts int
+	expiresAt   time.Time
+	consumedAt  *time.Time
+	createdAt   time.Time
+}
+
+// NewPhoneOTP creates a new PhoneOTP. userID is nil when the code is not tied to a known account.
+func NewPhoneOTP(userID *uuid.UUID, phone string, purpose OTPPurpose, codeHash string, maxAttempts int, expiresAt time.Time) *PhoneOTP {
+	return &PhoneOTP{
+		id:          uuid.New(),
+		userID:      userID,
+		phone:       phone,
+		purpose:     purpose,
+		codeHash:    codeHash,
+		attempts:    0,
+		maxAttempts: maxAttempts,
+		expiresAt:   expiresAt,
+		consumedAt:  nil,
+		createdAt:   time.Now().UTC(),
+	}
+}
+
+// ReconstructPhoneOTP rebuilds a PhoneOTP from persistence data.
+func ReconstructPhoneOTP(
+	id uuid.UUID,
+	userID *uuid.UUID,
+	phone string,
+	purpose OTPPurpose,
+	codeHash string,
+	attempts, maxAttempts int,
+	expiresAt time.Time,
+	consumedAt *time.Time,
+	createdAt time.Time,
+) *PhoneOTP {
+	return &PhoneOTP{
+		id:          id,
+		userID:      userID,
+		phone:       phone,
+		purpose:     purpose,
+		codeHash:    codeHash,
+		attempts:    attempts,
+		maxAttempts: maxAttempts,
+		expiresAt:   expiresAt,
+		consumedAt:  consumedAt,
+		createdAt:   createdAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the code's unique identifier.
+func (o *PhoneOTP) ID() uuid.UUID { return o.id }
+
+// UserID returns the account the code was issued for, or nil.
+func (o *PhoneOTP) UserID() *uuid.UUID { return o.userID }
+
+// Phone returns the number the code was sent to.
+func (o *PhoneOTP) Phone() string { return o.phone }
+
+// Purpose returns what the code may be redeemed for.
+func (o *PhoneOTP) Purpose() OTPPurpose { return o.purpose }
+
+// CodeHash returns the keyed hash of the code.
+func (o *PhoneOTP) CodeHash() string { return o.codeHash }
+
+// Attempts returns the number of wrong guesses so far.
+func (o *PhoneOTP) Attempts() int { return o.attempts }
+
+// MaxAttempts returns how many wrong guesses are allowed.
+func (o *PhoneOTP) MaxAttempts() int { return o.maxAttempts }
+
+// ExpiresAt returns the expiration timestamp.
+func (o *PhoneOTP) ExpiresAt() time.Time { return o.expiresAt }
+
+// ConsumedAt returns when the code was redeemed, or nil.
+func (o *PhoneOTP) ConsumedAt() *time.Time { return o.consumedAt }
+
+// CreatedAt returns the creation timestamp.
+func (o *PhoneOTP) CreatedAt() time.Time { return o.createdAt }
+
+// --- Behavior ---
+
+// IsExpired checks whether the code has expired.
+func (o *PhoneOTP) IsExpired() bool {
+	return time.Now().UTC().After(o.expiresAt)
+}
+
+// IsConsumed returns true if the code has already been redeemed.
+func (o *PhoneOTP) IsConsumed() bool {
+	return o.consumedAt != nil
+}
+
+// AttemptsExhausted returns true once the wrong-guess limit has been reached.
+func (o *PhoneOTP) AttemptsExhausted() bool {
+	return o.attempts >= o.maxAttempts
+}
+
+// RemainingAttempts returns how many more wrong guesses are allowed.
+func (o *PhoneOTP) RemainingAttempts() int {
+	if o.AttemptsExhausted() {
+		return 0
+	}
+	return o.maxAttempts - o.attempts
+}
+
+// IsRedeemable returns true if the code is unexpired, unused and has attempts left.
+func (o *PhoneOTP) IsRedeemable() bool {
+	return !o.IsConsumed() && !o.IsExpired() && !o.AttemptsExhausted()
+}
+
+// RecordFailedAttempt counts a wrong guess.
+func (o *PhoneOTP) RecordFailedAttempt() {
+	o.attempts++
+}
@@ -0,0 +1,274 @@
+package identity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryStatus is the lifecycle state of a RecoveryCase.
+type RecoveryStatus string
+
+const (
+	// RecoveryPending cases wait for an admin to verify the requester.
+	RecoveryPending RecoveryStatus = "pending"
+	// RecoveryApproved cases have a recovery link out to the confirmed contact.
+	RecoveryApproved RecoveryStatus = "approved"
+	// RecoveryRejected cases were turned down by an admin.
+	RecoveryRejected RecoveryStatus = "rejected"
+	// RecoveryCompleted cases were used to regain the account.
+	RecoveryCompleted RecoveryStatus = "completed"
+)
+
+// RecoveryAction names a step recorded in a recovery case's history.
+type RecoveryAction string
+
+const (
+	// RecoveryActionFiled records the requester opening the case.
+	RecoveryActionFiled RecoveryAction = "filed"
+	// RecoveryActionApproved records an admin approving the case and sending the link.
+	RecoveryActionApproved RecoveryAction = "approved"
+	// RecoveryActionRejected records an admin rejecting the case.
+	RecoveryActionRejected RecoveryAction = "rejected"
+	// RecoveryActionCompleted records the user regaining the account through the link.
+	RecoveryActionCompleted RecoveryAction = "completed"
+)
+
+var (
+	// ErrRecoveryCaseNotPending is returned when reviewing a case that was already reviewed.
+	ErrRecoveryCaseNotPending = errors.New("recovery case is no longer pending")
+	// ErrRecoveryNoMatchingAccount is returned when approving a case whose details matched no account.
+	ErrRecoveryNoMatchingAccount = errors.New("recovery case does not match an account")
+	// ErrRecoveryLinkInvalid is returned when a recovery link is used after expiry, rejection or completion.
+	ErrRecoveryLinkInvalid = errors.New("recovery link is invalid or expired")
+)
+
+// RecoveryCase is a support-assisted request to regain an account whose email
+// address is no longer reachable. The requester supplies identifying details and a
+// new contact address; an admin verifies them out of band, and approval sends a
+// single-use recovery link to that contact.
+type RecoveryCase struct {
+	id           uuid.UUID
+	userID       *uuid.UUID
+	claimedEmail string
+	fullName     string
+	phone        string
+	contactEmail Email
+	details      string
+	status       RecoveryStatus
+	reviewedBy   *uuid.UUID
+	reviewedAt   *time.Time
+	reviewNote   string
+	token        string
+	expiresAt    *time.Time
+	completedAt  *time.Time
+	createdAt    time.Time
+}
+
+// NewRecoveryCase opens a pending case. userID is the account the claimed email
+// belongs to, or nil when it matches none; the admin sees either way.
+func NewRecoveryCase(userID *uuid.UUID, claimedEmail, fullName, phone, contactEmail, details string) (*RecoveryCase, error) {
+	contact, err := NewEmail(contactEmail)
+	if err != nil {
+		return nil, err
+	}
+	return &RecoveryCase{
+		id:           uuid.New(),
+		userID:       userID,
+		claimedEmail: strings.TrimSpace(claimedEmail),
+		fullName:     strings.TrimSpace(fullName),
+		phone:        phone,
+		contactEmail: contact,
+		details:      strings.TrimSpace(details),
+		status:       RecoveryPending,
+		createdAt:    time.Now().UTC(),
+	}, nil
+}
+
+// ReconstructRecoveryCase rebuilds a RecoveryCase from persistence data.
+func ReconstructRecoveryCase(
+	id uuid.UUID,
+	userID *uuid.UUID,
+	claimedEmail, fullName, phone, contactEmail, details string,
+	status RecoveryStatus,
+	reviewedBy *uuid.UUID,
+	reviewedAt *time.Time,
+	reviewNote string,
+	token string,
+	expiresAt, completedAt *time.Time,
+	createdAt time.Time,
+) *RecoveryCase {
+	return &RecoveryCase{
+		id:           id,
+		userID:       userID,
+		claimedEmail: claimedEmail,
+		fullName:     fullName,
+		phone:        phone,
+		contactEmail: Email{value: contactEmail},
+		details:      details,
+		status:       status,
+		reviewedBy:   reviewedBy,
+		reviewedAt:   reviewedAt,
+		reviewNote:   reviewNote,
+		token:        token,
+		expiresAt:    expiresAt,
+		completedAt:  completedAt,
+		createdAt:    createdAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the case's unique identifier.
+func (c *RecoveryCase) ID() uuid.UUID { return c.id }
+
+// UserID returns the account being recovered, or nil if the claimed email matched none.
+func (c *RecoveryCase) UserID() *uuid.UUID { return c.userID }
+
+// ClaimedEmail returns the account email the requester says they had.
+func (c *RecoveryCase) ClaimedEmail() string { return c.claimedEmail }
+
+// FullName returns the name the requester gave.
+func (c *RecoveryCase) FullName() string { return c.fullName }
+
+// Phone returns the phone number the requester gave.
+func (c *RecoveryCase) Phone() string { return c.phone }
+
+// ContactEmail returns the new address the recovery link goes to and the account moves to.
+func (c *RecoveryCase) ContactEmail() string { return c.contactEmail.String() }
+
+// ContactEmailVO returns the confirmed contact address as a value object.
+func (c *RecoveryCase) ContactEmailVO() Email { return c.contactEmail }
+
+// Details returns the requester's free-text evidence for the admin.
+func (c *RecoveryCase) Details() string { return c.details }
+
+// Status returns the case's lifecycle state as stored.
+func (c *RecoveryCase) Status() RecoveryStatus { return c.status }
+
+// ReviewedBy returns the admin who approved or rejected the case, or nil.
+func (c *RecoveryCase) ReviewedBy() *uuid.UUID { return c.reviewedBy }
+
+// ReviewedAt returns when the case was approved or rejected, or nil.
+func (c *RecoveryCase) ReviewedAt() *time.Time { return c.reviewedAt }
+
+// ReviewNote returns the admin's note on the review.
+func (c *RecoveryCase) ReviewNote() string { return c.reviewNote }
+
+// Token returns the raw recovery token. It is only set right after approval or
+// when the case was loaded by token, since only its hash is stored.
+func (c *RecoveryCase) Token() string { return c.token }
+
+// ExpiresAt returns when the recovery link expires, or nil before approval.
+func (c *RecoveryCase) ExpiresAt() *time.Time { return c.expiresAt }
+
+// CompletedAt returns when the account was recovered, or nil.
+func (c *RecoveryCase) CompletedAt() *time.Time { return c.completedAt }
+
+// CreatedAt returns when the case was filed.
+func (c *RecoveryCase) CreatedAt() time.Time { return c.createdAt }
+
+// --- Behavior ---
+
+// Approve records an admin's approval and attaches the recovery link token.
+func (c *RecoveryCase) Approve(adminID uuid.UUID, note, token string, expiresAt time.Time) error {
+	if c.status != RecoveryPending {
+		return ErrRecoveryCaseNotPending
+	}
+	if c.userID == nil {
+		return ErrRecoveryNoMatchingAccount
+	}
+	c.review(adminID, note, RecoveryApproved)
+	c.token = token
+	c.expiresAt = &expiresAt
+	return nil
+}
+
+// Reject records an admin's rejection.
+func (c *RecoveryCase) Reject(adminID uuid.UUID, note string) error {
+	if c.status != RecoveryPending {
+		return ErrRecoveryCaseNotPending
+	}
+	c.review(adminID, note, RecoveryRejected)
+	return nil
+}
+
+// Complete records that the recovery link was used.
+func (c *RecoveryCase) Complete() error {
+	if c.status != RecoveryApproved || c.expiresAt == nil || time.Now().UTC().After(*c.expiresAt) {
+		return ErrRecoveryLinkInvalid
+	}
+	now := time.Now().UTC()
+	c.status = RecoveryCompleted
+	c.completedAt = &now
+	return nil
+}
+
+func (c *RecoveryCase) review(adminID uuid.UUID, note string, status RecoveryStatus) {
+	now := time.Now().UTC()
+	c.status = status
+	c.reviewedBy = &adminID
+	c.reviewedAt = &now
+	c.reviewNote = strings.TrimSpace(note)
+}
+
+// RecoveryEvent is one step in a recovery case's history, recorded against the
+// case and the account it concerns.
+type RecoveryEvent struct {
+	id        uuid.UUID
+	caseID    uuid.UUID
+	userID    *uuid.UUID
+	action    RecoveryAction
+	actorID   *uuid.UUID
+	note      string
+	createdAt time.Time
+}
+
+// NewRecoveryEvent records an action on the case. actorID is nil for steps the requester took.
+func NewRecoveryEvent(recoveryCase *RecoveryCase, action RecoveryAction, actorID *uuid.UUID, note string) *RecoveryEvent {
+	return &RecoveryEvent{
+		id:        uuid.New(),
+		caseID:    recoveryCase.ID(),
+		userID:    recoveryCase.UserID(),
+		action:    action,
+		actorID:   actorID,
+		note:      note,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// ReconstructRecoveryEvent rebuilds a RecoveryEvent from persistence data.
+func ReconstructRecoveryEvent(id, caseID uuid.UUID, userID *uuid.UUID, action RecoveryAction, actorID *uuid.UUID, note string, createdAt time.Time) *RecoveryEvent {
+	return &RecoveryEvent{
+		id:        id,
+		caseID:    caseID,
+		userID:    userID,
+		action:    action,
+		actorID:   actorID,
+		note:      note,
+		createdAt: createdAt,
+	}
+}
+
+// ID returns the event's unique identifier.
+func (e *RecoveryEvent) ID() uuid.UUID { return e.id }
+
+// CaseID returns the recovery case the event belongs to.
+func (e *RecoveryEvent) CaseID() uuid.UUID { return e.caseID }
+
+// UserID returns the account the event concerns, or nil.
+func (e *RecoveryEvent) UserID() *uuid.UUID { return e.userID }
+
+// Action returns what happened.
+func (e *RecoveryEvent) Action() RecoveryAction { return e.action }
+
+// ActorID returns the admin who acted, or nil for the requester.
+func (e *RecoveryEvent) ActorID() *uuid.UUID { return e.actorID }
+
+// Note returns the note recorded with the event.
+func (e *RecoveryEvent) Note() string { return e.note }
+
+// CreatedAt returns when the event happened.
+func (e *RecoveryEvent) CreatedAt() time.Time { return e.createdAt }
@@ -1,28 +1,44 @@
 package identity
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrRefreshTokenReused is returned when a refresh token that has already been
+// rotated is presented again, which indicates the token may have been stolen.
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+// ErrRefreshTokenRevoked is returned when a refresh token is revoked, for example by a
+// logout, before it could be rotated. Unlike reuse it does not point to a stolen token.
+var ErrRefreshTokenRevoked = errors.New("refresh token has been revoked")
+
 // RefreshToken represents a refresh token entity linked to a user.
+// Every token belongs to a family: the first token issued for a session starts the
+// family and each rotation issues a successor within it. The family ID is the ID of
+// the owning Session.
 type RefreshToken struct {
 	id        uuid.UUID
 	userID    uuid.UUID
+	familyID  uuid.UUID
 	token     string
 	expiresAt time.Time
+	usedAt    *time.Time
 	revoked   bool
 	createdAt time.Time
 }
 
-// NewRefreshToken creates a new RefreshToken.
-func NewRefreshToken(userID uuid.UUID, token string, expiresAt time.Time) *RefreshToken {
+// NewRefreshToken creates the first RefreshToken of the given family.
+func NewRefreshToken(userID, familyID uuid.UUID, token string, expiresAt time.Time) *RefreshToken {
 	return &RefreshToken{
 		id:        uuid.New(),
 		userID:    userID,
+		familyID:  familyID,
 		token:     token,
 		expiresAt: expiresAt,
+		usedAt:    nil,
 		revoked:   false,
 		createdAt: time.Now().UTC(),
 	}
@@ -30,17 +46,20 @@ func NewRefreshToken(userID uuid.UUID, token string, expiresAt time.Time) *Refre
 
 // ReconstructRefreshToken rebuilds a RefreshToken from persistence data.
 func ReconstructRefreshToken(
-	id, userID uuid.UUID,
+	id, userID, familyID uuid.UUID,
 	token string,
 	expiresAt time.Time,
+	usedAt *time.Time,
 	revoked bool,
 	createdAt time.Time,
 ) *RefreshToken {
 	return &RefreshToken{
 		id:        id,
 		userID:    userID,
+		familyID:  familyID,
 		token:     token,
 		expiresAt: expiresAt,
+		usedAt:    usedAt,
 		revoked:   revoked,
 		createdAt: createdAt,
 	}
@@ -54,12 +73,18 @@ func (t *RefreshToken) ID() uuid.UUID { return t.id }
 // UserID returns the owning user's ID.
 func (t *RefreshToken) UserID() uuid.UUID { return t.userID }
 
+// FamilyID returns the ID of the token family this token belongs to.
+func (t *RefreshToken) FamilyID() uuid.UUID { return t.familyID }
+
 // Token returns the token string.
 func (t *RefreshToken) Token() string { return t.token }
 
 // ExpiresAt returns the expiration timestamp.
 func (t *RefreshToken) ExpiresAt() time.Time { return t.expiresAt }
 
+// UsedAt returns when the token was rotated, or nil if it has not been used.
+func (t *RefreshToken) UsedAt() *time.Time { return t.usedAt }
+
 // Revoked returns whether the token has been revoked.
 func (t *RefreshToken) Revoked() bool { return t.revoked }
 
@@ -68,17 +93,37 @@ func (t *RefreshToken) CreatedAt() time.Time { return t.createdAt }
 
 // --- Behavior ---
 
+// Rotate returns the successor token in the same family. The receiver is not
+// modified; the repository marks it used when the successor is persisted.
+func (t *RefreshToken) Rotate(token string, expiresAt time.Time) *RefreshToken {
+	return &RefreshToken{
+		id:        uuid.New(),
+		userID:    t.userID,
+		familyID:  t.familyID,
+		token:     token,
+		expiresAt: expiresAt,
+		usedAt:    nil,
+		revoked:   false,
+		createdAt: time.Now().UTC(),
+	}
+}
+
 // Revoke marks the token as revoked.
 func (t *RefreshToken) Revoke() {
 	t.revoked = true
 }
 
+// IsUsed returns true if the token has already been rotated.
+func (t *RefreshToken) IsUsed() bool {
+	return t.usedAt != nil
+}
+
 // IsExpired checks whether the token has expired.
 func (t *RefreshToken) IsExpired() bool {
 	return time.Now().UTC().After(t.expiresAt)
 }
 
-// IsValid returns true if the token is neither revoked nor expired.
+// IsValid returns true if the token is neither used, revoked nor expired.
 func (t *RefreshToken) IsValid() bool {
-	return !t.revoked && !t.IsExpired()
+	return !t.IsUsed() && !t.revoked && !t.IsExpired()
 }
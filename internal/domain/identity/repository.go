@@ -2,37 +2,177 @@ package identity
 
 import (
 	"context"
+	"time"
 
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
 	"github.com/google/uuid"
 )
 
 // UserRepository defines persistence operations for User aggregates.
 type UserRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
-	FindByEmail(ctx context.Context, email string) (*User, error)
+	// FindByEmail returns the user whose address equals email, ignoring case.
+	FindByEmail(ctx context.Context, email Email) (*User, error)
+	// CountByCanonicalEmail counts the users whose address has the same canonical
+	// form as email; see Email.Canonical.
+	CountByCanonicalEmail(ctx context.Context, email Email) (int64, error)
+	// FindByVerifiedPhone returns the user whose verified phone number is phone.
+	FindByVerifiedPhone(ctx context.Context, phone string) (*User, error)
 	Save(ctx context.Context, user *User) error
 	Update(ctx context.Context, user *User) error
 	ListAll(ctx context.Context, page, limit int) ([]*User, int64, error)
 	CountByRole(ctx context.Context) (map[string]int64, error)
 	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	// ReplacePasswordHash swaps oldHash for newHash, returning domain.ErrNotFound if the
+	// user's hash is no longer oldHash because the password changed in the meantime.
+	ReplacePasswordHash(ctx context.Context, userID uuid.UUID, oldHash, newHash string) error
 }
 
 // TokenRepository defines persistence operations for RefreshToken entities.
 type TokenRepository interface {
 	Save(ctx context.Context, token *RefreshToken) error
 	FindByToken(ctx context.Context, token string) (*RefreshToken, error)
+	// Rotate atomically marks the consumed token as used and persists its successor.
+	// Returns ErrRefreshTokenReused if the consumed token was already used, or
+	// ErrRefreshTokenRevoked if it was revoked without being used.
+	Rotate(ctx context.Context, consumedID uuid.UUID, next *RefreshToken) error
 	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
 }
 
+// SessionRepository defines persistence operations for Session entities.
+// Revoking a session also revokes every refresh token in its family.
+type SessionRepository interface {
+	Save(ctx context.Context, session *Session) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	ListActiveForUser(ctx context.Context, userID uuid.UUID) ([]*Session, error)
+	Touch(ctx context.Context, id uuid.UUID, userAgent, ipAddress string) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	RevokeAllForUserExcept(ctx context.Context, userID, keepID uuid.UUID) error
+}
+
 // PasswordResetRepository defines persistence operations for PasswordReset entities.
 type PasswordResetRepository interface {
 	Create(ctx context.Context, reset *PasswordReset) error
+	// ReplaceOutstanding stores reset and removes the user's other unused reset tokens
+	// in one transaction, so only the newest link works.
+	ReplaceOutstanding(ctx context.Context, reset *PasswordReset) error
 	FindByToken(ctx context.Context, token string) (*PasswordReset, error)
 	FindAnyByToken(ctx context.Context, token string) (*PasswordReset, error)
 	MarkUsed(ctx context.Context, id uuid.UUID) error
+	// MarkUsedAndUpdatePassword consumes the token, sets the new password hash and ends
+	// every session of the user in one transaction. Returns domain.ErrNotFound if the
+	// token was already used or has expired.
 	MarkUsedAndUpdatePassword(ctx context.Context, tokenID uuid.UUID, userID uuid.UUID, newHash string) error
 }
 
+// EmailVerificationRepository defines persistence operations for EmailVerification entities.
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, verification *EmailVerification) error
+	FindAnyByToken(ctx context.Context, token string) (*EmailVerification, error)
+	// CountCreatedSince counts the verification tokens issued to a user since the given time.
+	CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	// MarkUsedAndVerifyUser atomically consumes the token, invalidates the user's other
+	// outstanding tokens and marks the user verified. Returns domain.ErrNotFound if the
+	// token was already used.
+	MarkUsedAndVerifyUser(ctx context.Context, tokenID, userID uuid.UUID) error
+}
+
+// EmailChangeRepository defines persistence operations for EmailChange entities.
+// Tokens are stored as keyed hashes; a change loaded by one of its tokens carries only that token.
+type EmailChangeRepository interface {
+	// Create stores a pending change and cancels the user's other pending ones in the
+	// same transaction, so only the newest request can be confirmed.
+	Create(ctx context.Context, change *EmailChange) error
+	FindByConfirmToken(ctx context.Context, token string) (*EmailChange, error)
+	FindByCancelToken(ctx context.Context, token string) (*EmailChange, error)
+	// Confirm marks a pending change confirmed and moves the user to the new address,
+	// which counts as verified, in one transaction. Returns ErrEmailChangeNotPending if
+	// the change stopped being pending or the account's address changed since it was
+	// requested, and an already-exists error if another account holds the new address.
+	Confirm(ctx context.Context, change *EmailChange) error
+	// Cancel marks a pending change cancelled.
+	// Returns ErrEmailChangeNotPending if it was no longer pending.
+	Cancel(ctx context.Context, change *EmailChange) error
+}
+
+// PhoneOTPRepository defines persistence operations for PhoneOTP entities.
+type PhoneOTPRepository interface {
+	Create(ctx context.Context, otp *PhoneOTP) error
+	// FindLatest returns the most recently issued code for the phone and purpose,
+	// whatever its state. Returns domain.ErrNotFound if none was ever issued.
+	FindLatest(ctx context.Context, phone string, purpose OTPPurpose) (*PhoneOTP, error)
+	// CountCreatedSince counts the codes sent to a phone for a purpose since the given time.
+	CountCreatedSince(ctx context.Context, phone string, purpose OTPPurpose, since time.Time) (int64, error)
+	// RecordFailedAttempt increments the wrong-guess counter of a code.
+	RecordFailedAttempt(ctx context.Context, id uuid.UUID) error
+	// Consume marks a code redeemed. Returns domain.ErrNotFound if it was already
+	// redeemed or ran out of attempts concurrently.
+	Consume(ctx context.Context, id uuid.UUID) error
+}
+
+// InvitationRepository defines persistence operations for Invitation entities.
+// Invitations loaded by ID or listed carry no raw token, only FindByToken does.
+type InvitationRepository interface {
+	Save(ctx context.Context, invitation *Invitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Invitation, error)
+	FindByToken(ctx context.Context, token string) (*Invitation, error)
+	// FindPendingByEmail returns the unexpired, unaccepted, unrevoked invitation for an email.
+	FindPendingByEmail(ctx context.Context, email Email) (*Invitation, error)
+	List(ctx context.Context, page, limit int) ([]*Invitation, int64, error)
+	// Accept atomically marks the invitation accepted and creates the invited user.
+	// Returns ErrInvitationNotPending if the invitation was accepted, revoked or expired meanwhile.
+	Accept(ctx context.Context, invitation *Invitation, user *User) error
+	// Revoke marks a pending invitation revoked.
+	// Returns ErrInvitationNotPending if it was no longer pending.
+	Revoke(ctx context.Context, invitation *Invitation) error
+}
+
+// TOTPFactorRepository defines persistence operations for TOTPFactor entities.
+// Secrets are encrypted at rest by the implementation.
+type TOTPFactorRepository interface {
+	// Save stores a pending enrollment, replacing any earlier pending one.
+	// Returns ErrTOTPAlreadyEnabled if the user has a confirmed enrollment.
+	Save(ctx context.Context, factor *TOTPFactor) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*TOTPFactor, error)
+	// Confirm persists a confirmed enrollment. Returns ErrTOTPAlreadyEnabled if it
+	// was confirmed concurrently.
+	Confirm(ctx context.Context, factor *TOTPFactor) error
+	// RecordUse persists the factor's last used step. Returns ErrTOTPCodeReused if
+	// the same or a later step was recorded concurrently.
+	RecordUse(ctx context.Context, factor *TOTPFactor) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// MFAPolicyRepository defines persistence for the roles that must use two-factor authentication.
+type MFAPolicyRepository interface {
+	ListRequiredRoles(ctx context.Context) ([]auth.UserRole, error)
+	// ReplaceRequiredRoles atomically replaces the required roles with the given set.
+	ReplaceRequiredRoles(ctx context.Context, roles []auth.UserRole, actorID uuid.UUID) error
+}
+
+// RecoveryCaseRepository defines persistence operations for RecoveryCase entities and
+// their history. Every state change is stored together with its RecoveryEvent.
+// Recovery tokens are stored as keyed hashes; only FindByToken returns the raw token.
+type RecoveryCaseRepository interface {
+	Create(ctx context.Context, recoveryCase *RecoveryCase, event *RecoveryEvent) error
+	FindByID(ctx context.Context, id uuid.UUID) (*RecoveryCase, error)
+	FindByToken(ctx context.Context, token string) (*RecoveryCase, error)
+	// CountOpenForEmail counts the pending and approved cases claiming an email.
+	CountOpenForEmail(ctx context.Context, claimedEmail Email) (int64, error)
+	// List returns cases newest first, optionally filtered by status and user.
+	List(ctx context.Context, status RecoveryStatus, userID *uuid.UUID, page, limit int) ([]*RecoveryCase, int64, error)
+	ListEvents(ctx context.Context, caseID uuid.UUID) ([]*RecoveryEvent, error)
+	// Review persists an approval or rejection of a pending case. Approval also
+	// ends every session of the user and revokes their refresh tokens in the same
+	// transaction. Returns ErrRecoveryCaseNotPending if the case was reviewed concurrently.
+	Review(ctx context.Context, recoveryCase *RecoveryCase, event *RecoveryEvent) error
+	// Complete atomically marks the case completed, moves the user to the confirmed
+	// contact email as verified, sets the new password hash and ends every session.
+	// Returns ErrRecoveryLinkInvalid if the case was completed concurrently.
+	Complete(ctx context.Context, recoveryCase *RecoveryCase, passwordHash string, event *RecoveryEvent) error
+}
+
 // RunnerApplicationRepository defines persistence operations for RunnerApplication entities.
 type RunnerApplicationRepository interface {
 	// Insert persists a new runner application and returns a formatted display ID
@@ -44,3 +184,106 @@ type RunnerApplicationRepository interface {
 	// if a row with the same ic_number already exists.
 	Insert(ctx context.Context, app *RunnerApplication) (string, error)
 }
+
+// PasskeyRepository defines persistence operations for Passkey entities.
+type PasskeyRepository interface {
+	// Save stores a new passkey. Returns ErrPasskeyAlreadyRegistered if the
+	// credential ID is already registered to any user.
+	Save(ctx context.Context, passkey *Passkey) error
+	FindByCredentialID(ctx context.Context, credentialID []byte) (*Passkey, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]*Passkey, error)
+	// RecordUse persists the passkey's new signature counter and last use. Returns
+	// ErrPasskeySignCountRegressed if a use with the same or a higher counter was
+	// recorded concurrently.
+	RecordUse(ctx context.Context, passkey *Passkey) error
+	// Delete removes one of the user's passkeys. Returns ErrNotFound if the user has
+	// no passkey with that ID.
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// WebAuthnChallengeRepository defines persistence for WebAuthn ceremony challenges.
+type WebAuthnChallengeRepository interface {
+	Create(ctx context.Context, challenge *WebAuthnChallenge) error
+	// Consume atomically deletes and returns a challenge so each is used at most once.
+	// Returns ErrNotFound if it does not exist or was consumed concurrently.
+	Consume(ctx context.Context, id uuid.UUID) (*WebAuthnChallenge, error)
+	// DeleteExpired removes challenges that expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// LoginFailureRepository keeps failed sign-in counters in shared storage so every
+// replica enforces the same limits.
+type LoginFailureRepository interface {
+	// Find returns the counter for the scope and key, or ErrNotFound if there is none.
+	Find(ctx context.Context, scope LoginFailureScope, key string) (*LoginFailure, error)
+	// RecordFailure atomically counts one more failure and returns the updated counter.
+	// A counter whose last failure is older than resetAfter starts again from one.
+	RecordFailure(ctx context.Context, scope LoginFailureScope, key string, resetAfter time.Duration) (*LoginFailure, error)
+	// Lock sets a lockout that ends at until. It reports false if the counter was
+	// already locked, so only one caller acts on a new lockout.
+	Lock(ctx context.Context, scope LoginFailureScope, key string, until time.Time) (bool, error)
+	// Reset removes the counter, lifting any lockout.
+	Reset(ctx context.Context, scope LoginFailureScope, key string) error
+}
+
+// RequestCounterRepository counts requests per scope and key in fixed windows, for
+// throttling endpoints whose callers need not have an account.
+type RequestCounterRepository interface {
+	// Increment counts one request and returns the count in the current window and
+	// when that window ends. A window that has ended is replaced by a new one.
+	Increment(ctx context.Context, scope, key string, window time.Duration) (int, time.Time, error)
+}
+
+// TokenRevocationRepository keeps access token revocations in shared storage so every
+// replica rejects the same tokens.
+type TokenRevocationRepository interface {
+	// Create stores a revocation. Revoking the same scope and key again moves its
+	// revocation and expiry times forward.
+	Create(ctx context.Context, revocation *TokenRevocation) error
+	// ListActive returns the revocations that have not expired at the given time.
+	ListActive(ctx context.Context, now time.Time) ([]*TokenRevocation, error)
+	// DeleteExpired removes revocations that expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// OAuthClientRepository defines persistence for registered OAuth clients. Secrets are
+// stored as keyed hashes by the implementation.
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *OAuthClient) error
+	FindByID(ctx context.Context, id uuid.UUID) (*OAuthClient, error)
+	// FindByClientID returns the client with the given public client ID, whether or not
+	// it is disabled.
+	FindByClientID(ctx context.Context, clientID string) (*OAuthClient, error)
+	// FindByCredentials returns the client with the given client ID and secret, whether
+	// or not it is disabled. Returns ErrNotFound if either does not match.
+	FindByCredentials(ctx context.Context, clientID, secret string) (*OAuthClient, error)
+	List(ctx context.Context) ([]*OAuthClient, error)
+	// Update persists the client's state, and its secret when it was just rotated.
+	Update(ctx context.Context, client *OAuthClient) error
+}
+
+// AuthorizationCodeRepository defines persistence for OpenID Connect authorization
+// codes. Codes are stored as keyed hashes by the implementation.
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *AuthorizationCode) error
+	// FindByCode returns the code whatever its state, so a second redemption can be
+	// recognised. Returns domain.ErrNotFound if no such code was issued.
+	FindByCode(ctx context.Context, code string) (*AuthorizationCode, error)
+	// Consume marks a code redeemed. Returns domain.ErrNotFound if it was already
+	// redeemed concurrently.
+	Consume(ctx context.Context, id uuid.UUID) error
+	// AttachSession records the session started with a redeemed code.
+	AttachSession(ctx context.Context, id, sessionID uuid.UUID) error
+}
+
+// ExternalIdentityRepository defines persistence for the provider accounts users sign
+// in with.
+type ExternalIdentityRepository interface {
+	// Create links a provider account. Returns ErrExternalIdentityAlreadyLinked if the
+	// account is linked to any user or the user already has one of that provider.
+	Create(ctx context.Context, link *ExternalIdentity) error
+	// FindByProviderSubject returns domain.ErrNotFound if the account is not linked.
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*ExternalIdentity, error)
+	// RecordUse persists the link's last use and reported email.
+	RecordUse(ctx context.Context, link *ExternalIdentity) error
+}
@@ -0,0 +1,108 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session represents a single signed-in device. Each session owns one refresh
+// token family; the session ID doubles as the family ID of its refresh tokens.
+type Session struct {
+	id         uuid.UUID
+	userID     uuid.UUID
+	deviceName string
+	platform   string
+	userAgent  string
+	ipAddress  string
+	// authMethods are the RFC 8176 "amr" values the user signed in with; every
+	// access token issued for the session carries them.
+	authMethods []string
+	createdAt   time.Time
+	lastSeenAt  time.Time
+	revokedAt   *time.Time
+}
+
+// NewSession creates a new active Session for the given user and device, signed in
+// with the given authentication methods.
+func NewSession(userID uuid.UUID, deviceName, platform, userAgent, ipAddress string, authMethods []string) *Session {
+	now := time.Now().UTC()
+	return &Session{
+		id:          uuid.New(),
+		userID:      userID,
+		deviceName:  deviceName,
+		platform:    platform,
+		userAgent:   userAgent,
+		ipAddress:   ipAddress,
+		authMethods: authMethods,
+		createdAt:   now,
+		lastSeenAt:  now,
+		revokedAt:   nil,
+	}
+}
+
+// ReconstructSession rebuilds a Session from persistence data.
+func ReconstructSession(
+	id, userID uuid.UUID,
+	deviceName, platform, userAgent, ipAddress string,
+	authMethods []string,
+	createdAt, lastSeenAt time.Time,
+	revokedAt *time.Time,
+) *Session {
+	return &Session{
+		id:          id,
+		userID:      userID,
+		deviceName:  deviceName,
+		platform:    platform,
+		userAgent:   userAgent,
+		ipAddress:   ipAddress,
+		authMethods: authMethods,
+		createdAt:   createdAt,
+		lastSeenAt:  lastSeenAt,
+		revokedAt:   revokedAt,
+	}
+}
+
+// --- Getters ---
+
+// ID returns the session's unique identifier.
+func (s *Session) ID() uuid.UUID { return s.id }
+
+// UserID returns the owning user's ID.
+func (s *Session) UserID() uuid.UUID { return s.userID }
+
+// DeviceName returns the client-supplied device name.
+func (s *Session) DeviceName() string { return s.deviceName }
+
+// Platform returns the client platform (e.g. ios, android, web).
+func (s *Session) Platform() string { return s.platform }
+
+// UserAgent returns the user agent the session was last seen with.
+func (s *Session) UserAgent() string { return s.userAgent }
+
+// IPAddress returns the IP address the session was last seen from.
+func (s *Session) IPAddress() string { return s.ipAddress }
+
+// AuthMethods returns the authentication methods the session was started with.
+func (s *Session) AuthMethods() []string { return s.authMethods }
+
+// CreatedAt returns when the session was started.
+func (s *Session) CreatedAt() time.Time { return s.createdAt }
+
+// LastSeenAt returns when the session last refreshed its tokens.
+func (s *Session) LastSeenAt() time.Time { return s.lastSeenAt }
+
+// RevokedAt returns when the session was revoked, or nil if still active.
+func (s *Session) RevokedAt() *time.Time { return s.revokedAt }
+
+// --- Behavior ---
+
+// IsActive returns true if the session has not been revoked.
+func (s *Session) IsActive() bool {
+	return s.revokedAt == nil
+}
+
+// BelongsTo returns true if the session is owned by the given user.
+func (s *Session) BelongsTo(userID uuid.UUID) bool {
+	return s.userID == userID
+}
@@ -0,0 +1,80 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenRevocationScope is what a token revocation applies to.
+type TokenRevocationScope string
+
+const (
+	// TokenRevocationScopeToken revokes one access token, keyed by its jti.
+	TokenRevocationScopeToken TokenRevocationScope = "token"
+	// TokenRevocationScopeSession revokes every access token of one session, keyed by its sid.
+	TokenRevocationScopeSession TokenRevocationScope = "session"
+	// TokenRevocationScopeUser revokes every access token of one user, keyed by the user ID.
+	TokenRevocationScopeUser TokenRevocationScope = "user"
+)
+
+// TokenRevocation withdraws access tokens before they expire. It covers tokens of its
+// scope and key issued no later than it was made, and can be forgotten once expiresAt
+// passes, since every token it covers has expired by then.
+type TokenRevocation struct {
+	scope     TokenRevocationScope
+	key       string
+	userID    uuid.UUID
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+// NewTokenRevocation creates a revocation of the tokens of scope and key issued until
+// now, kept until the longest-lived of them has expired.
+func NewTokenRevocation(scope TokenRevocationScope, key string, userID uuid.UUID, tokenLifetime time.Duration) *TokenRevocation {
+	now := time.Now().UTC()
+	return &TokenRevocation{
+		scope:     scope,
+		key:       key,
+		userID:    userID,
+		revokedAt: now,
+		expiresAt: now.Add(tokenLifetime),
+	}
+}
+
+// ReconstructTokenRevocation rebuilds a TokenRevocation from persistence data.
+func ReconstructTokenRevocation(scope TokenRevocationScope, key string, userID uuid.UUID, revokedAt, expiresAt time.Time) *TokenRevocation {
+	return &TokenRevocation{
+		scope:     scope,
+		key:       key,
+		userID:    userID,
+		revokedAt: revokedAt,
+		expiresAt: expiresAt,
+	}
+}
+
+// Scope returns what the revocation applies to.
+func (r *TokenRevocation) Scope() TokenRevocationScope { return r.scope }
+
+// Key returns the jti, session ID or user ID the revocation applies to.
+func (r *TokenRevocation) Key() string { return r.key }
+
+// UserID returns the user whose tokens are revoked.
+func (r *TokenRevocation) UserID() uuid.UUID { return r.userID }
+
+// RevokedAt returns when the revocation was made.
+func (r *TokenRevocation) RevokedAt() time.Time { return r.revokedAt }
+
+// ExpiresAt returns when every token the revocation covers has expired.
+func (r *TokenRevocation) ExpiresAt() time.Time { return r.expiresAt }
+
+// Covers reports whether a token issued at issuedAt is revoked. Token timestamps have
+// whole-second precision, so a token issued in the second of the revocation is covered.
+func (r *TokenRevocation) Covers(issuedAt time.Time) bool {
+	return issuedAt.Unix() <= r.revokedAt.Unix()
+}
+
+// IsExpired reports whether every token the revocation covers has expired at the given time.
+func (r *TokenRevocation) IsExpired(now time.Time) bool {
+	return !now.Before(r.expiresAt)
+}
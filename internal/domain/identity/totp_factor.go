@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTOTPAlreadyEnabled is returned when enrolling a user whose authenticator is already confirmed.
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTOTPNotEnabled is returned when a user without a confirmed authenticator must present a code.
+	ErrTOTPNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTOTPCodeReused is returned when a code's time step was already used, so a
+	// code observed once cannot be replayed within its validity window.
+	ErrTOTPCodeReused = errors.New("authentication code was already used")
+)
+
+// TOTPFactor is a user's authenticator app enrollment. It is pending until the user
+// proves possession by entering a code, and only then counts as a second factor.
+type TOTPFactor struct {
+	userID       uuid.UUID
+	secret       string
+	confirmedAt  *time.Time
+	lastUsedStep int64
+	createdAt    time.Time
+}
+
+// NewTOTPFactor creates a pending enrollment for the user with the given base32 secret.
+func NewTOTPFactor(userID uuid.UUID, secret string) *TOTPFactor {
+	return &TOTPFactor{
+		userID:    userID,
+		secret:    secret,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// ReconstructTOTPFactor rebuilds a TOTPFactor from persistence data.
+func ReconstructTOTPFactor(userID uuid.UUID, secret string, confirmedAt *time.Time, lastUsedStep int64, createdAt time.Time) *TOTPFactor {
+	return &TOTPFactor{
+		userID:       userID,
+		secret:       secret,
+		confirmedAt:  confirmedAt,
+		lastUsedStep: lastUsedStep,
+		createdAt:    createdAt,
+	}
+}
+
+// --- Getters ---
+
+// UserID returns the enrolled user's ID.
+func (f *TOTPFactor) UserID() uuid.UUID { return f.userID }
+
+// Secret returns the base32 shared secret.
+func (f *TOTPFactor) Secret() string { return f.secret }
+
+// ConfirmedAt returns when the enrollment was confirmed, or nil while pending.
+func (f *TOTPFactor) ConfirmedAt() *time.Time { return f.confirmedAt }
+
+// LastUsedStep returns the time step of the last accepted code.
+func (f *TOTPFactor) LastUsedStep() int64 { return f.lastUsedStep }
+
+// CreatedAt returns when the enrollment was started.
+func (f *TOTPFactor) CreatedAt() time.Time { return f.createdAt }
+
+// --- Behavior ---
+
+// IsConfirmed returns true once the user has proven possession of the secret.
+func (f *TOTPFactor) IsConfirmed() bool {
+	return f.confirmedAt != nil
+}
+
+// UseStep records that a code for the given time step was accepted. Steps at or
+// before the last accepted one are refused.
+func (f *TOTPFactor) UseStep(step int64) error {
+	if step <= f.lastUsedStep {
+		return ErrTOTPCodeReused
+	}
+	f.lastUsedStep = step
+	return nil
+}
+
+// Confirm activates a pending enrollment with the step of the code that proved it.
+func (f *TOTPFactor) Confirm(step int64) error {
+	if f.IsConfirmed() {
+		return ErrTOTPAlreadyEnabled
+	}
+	if err := f.UseStep(step); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	f.confirmedAt = &now
+	return nil
+}
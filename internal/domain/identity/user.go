@@ -1,6 +1,7 @@
 package identity
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,17 +11,19 @@ import (
 
 // User is the aggregate root representing a system user.
 type User struct {
-	id           uuid.UUID
-	email        Email
-	phone        Phone
-	passwordHash string
-	fullName     string
-	role         auth.UserRole
-	isVerified   bool
-	avatarURL    string
-	version      int64
-	createdAt    time.Time
-	updatedAt    time.Time
+	id            uuid.UUID
+	email         Email
+	phone         Phone
+	passwordHash  string
+	fullName      string
+	role          auth.UserRole
+	isVerified    bool
+	phoneVerified bool
+	avatarURL     string
+	standing      AccountStanding
+	version       int64
+	createdAt     time.Time
+	updatedAt     time.Time
 }
 
 // NewUser creates a new User with validated fields.
@@ -42,17 +45,19 @@ func NewUser(email, phone, fullName, passwordHash string, role auth.UserRole) (*
 
 	now := time.Now().UTC()
 	return &User{
-		id:           uuid.New(),
-		email:        emailVO,
-		phone:        phoneVO,
-		passwordHash: passwordHash,
-		fullName:     fullName,
-		role:         role,
-		isVerified:   false,
-		avatarURL:    "",
-		version:      1,
-		createdAt:    now,
-		updatedAt:    now,
+		id:            uuid.New(),
+		email:         emailVO,
+		phone:         phoneVO,
+		passwordHash:  passwordHash,
+		fullName:      fullName,
+		role:          role,
+		isVerified:    false,
+		phoneVerified: false,
+		avatarURL:     "",
+		standing:      NewActiveStanding(now),
+		version:       1,
+		createdAt:     now,
+		updatedAt:     now,
 	}, nil
 }
 
@@ -61,23 +66,26 @@ func ReconstructUser(
 	id uuid.UUID,
 	email, phone, passwordHash, fullName string,
 	role auth.UserRole,
-	isVerified bool,
+	isVerified, phoneVerified bool,
 	avatarURL string,
+	standing AccountStanding,
 	version int64,
 	createdAt, updatedAt time.Time,
 ) *User {
 	return &User{
-		id:           id,
-		email:        Email{value: email},
-		phone:        Phone{value: phone},
-		passwordHash: passwordHash,
-		fullName:     fullName,
-		role:         role,
-		isVerified:   isVerified,
-		avatarURL:    avatarURL,
-		version:      version,
-		createdAt:    createdAt,
-		updatedAt:    updatedAt,
+		id:            id,
+		email:         Email{value: email},
+		phone:         Phone{value: phone},
+		passwordHash:  passwordHash,
+		fullName:      fullName,
+		role:          role,
+		isVerified:    isVerified,
+		phoneVerified: phoneVerified,
+		avatarURL:     avatarURL,
+		standing:      standing,
+		version:       version,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
 	}
 }
 
@@ -107,12 +115,27 @@ func (u *User) FullName() string { return u.fullName }
 // Role returns the user's role.
 func (u *User) Role() auth.UserRole { return u.role }
 
-// IsVerified returns whether the user has been verified.
+// IsVerified returns whether the user has verified their email address.
 func (u *User) IsVerified() bool { return u.isVerified }
 
+// PhoneVerified returns whether the user has proven control of their current phone number.
+func (u *User) PhoneVerified() bool { return u.phoneVerified }
+
 // AvatarURL returns the user's avatar URL.
 func (u *User) AvatarURL() string { return u.avatarURL }
 
+// Standing returns the account's status record.
+func (u *User) Standing() AccountStanding { return u.standing }
+
+// Status returns the account's effective status, treating an expired suspension as active.
+func (u *User) Status() AccountStatus { return u.standing.Status(time.Now().UTC()) }
+
+// CanAuthenticate returns nil if the account may sign in and obtain tokens, or
+// one of ErrAccountSuspended, ErrAccountBanned or ErrAccountDeleted.
+func (u *User) CanAuthenticate() error {
+	return u.standing.CheckCanAuthenticate(time.Now().UTC())
+}
+
 // Version returns the entity version for optimistic locking.
 func (u *User) Version() int64 { return u.version }
 
@@ -124,24 +147,41 @@ func (u *User) UpdatedAt() time.Time { return u.updatedAt }
 
 // --- Behavior ---
 
-// Verify marks the user as verified.
+// Verify marks the user's email address as verified.
 func (u *User) Verify() {
 	u.isVerified = true
 	u.updatedAt = time.Now().UTC()
 }
 
-// UpdateProfile updates the user's profile information.
-func (u *User) UpdateProfile(fullName, phone, avatarURL string) {
+// MarkPhoneVerified records that the user proved control of their current phone number.
+func (u *User) MarkPhoneVerified() {
+	u.phoneVerified = true
+	u.updatedAt = time.Now().UTC()
+}
+
+// UpdateProfile updates the user's profile information. Changing the phone
+// number clears its verified flag.
+func (u *User) UpdateProfile(fullName, phone, avatarURL string) error {
+	var phoneVO Phone
+	if phone != "" {
+		var err error
+		if phoneVO, err = NewPhone(phone); err != nil {
+			return err
+		}
+	}
+
 	if fullName != "" {
 		u.fullName = fullName
 	}
-	if phone != "" {
-		u.phone = Phone{value: phone}
+	if !phoneVO.IsEmpty() && !phoneVO.Equals(u.phone) {
+		u.phone = phoneVO
+		u.phoneVerified = false
 	}
 	if avatarURL != "" {
 		u.avatarURL = avatarURL
 	}
 	u.updatedAt = time.Now().UTC()
+	return nil
 }
 
 // ChangePassword replaces the user's password hash.
@@ -150,10 +190,55 @@ func (u *User) ChangePassword(newHash string) {
 	u.updatedAt = time.Now().UTC()
 }
 
-// Deactivate deactivates the user by revoking verification.
-func (u *User) Deactivate() {
-	u.isVerified = false
-	u.updatedAt = time.Now().UTC()
+// Suspend blocks the account. A non-nil until lifts the suspension automatically
+// at that time; nil suspends it until an admin reinstates it.
+func (u *User) Suspend(actorID uuid.UUID, reason string, until *time.Time) error {
+	if u.standing.StoredStatus() == AccountDeleted {
+		return ErrAccountDeleted
+	}
+	now := time.Now().UTC()
+	if until != nil && !until.After(now) {
+		return errors.New("suspension end must be in the future")
+	}
+	u.setStanding(AccountSuspended, reason, &actorID, until, now)
+	return nil
+}
+
+// Ban blocks the account until an admin lifts the ban.
+func (u *User) Ban(actorID uuid.UUID, reason string) error {
+	if u.standing.StoredStatus() == AccountDeleted {
+		return ErrAccountDeleted
+	}
+	u.setStanding(AccountBanned, reason, &actorID, nil, time.Now().UTC())
+	return nil
+}
+
+// Reinstate lifts a ban or suspension and makes the account active again.
+func (u *User) Reinstate(actorID uuid.UUID) error {
+	switch u.standing.StoredStatus() {
+	case AccountDeleted:
+		return ErrAccountDeleted
+	case AccountActive:
+		return errors.New("account is not banned or suspended")
+	}
+	u.setStanding(AccountActive, "", &actorID, nil, time.Now().UTC())
+	return nil
+}
+
+// MarkDeleted closes the account permanently.
+func (u *User) MarkDeleted(actorID *uuid.UUID, reason string) {
+	u.setStanding(AccountDeleted, reason, actorID, nil, time.Now().UTC())
+}
+
+func (u *User) setStanding(status AccountStatus, reason string, actorID *uuid.UUID, until *time.Time, now time.Time) {
+	u.standing = AccountStanding{
+		status:    status,
+		reason:    reason,
+		changedBy: actorID,
+		changedAt: now,
+		expiresAt: until,
+	}
+	u.updatedAt = now
 }
 
 // IncrementVersion bumps the version for optimistic locking.
@@ -0,0 +1,203 @@
+package federation_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/federation"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testAudience = "kilat-owner-app"
+
+// fakeProvider is a local identity provider that publishes a test key and signs ID
+// tokens with it.
+type fakeProvider struct {
+	server     *httptest.Server
+	key        *rsa.PrivateKey
+	kid        string
+	keyFetches atomic.Int32
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	p := &fakeProvider{key: key, kid: "test-key"}
+
+	signingKey, err := tokens.NewSigningKey(p.kid, key)
+	if err != nil {
+		t.Fatalf("NewSigningKey failed: %v", err)
+	}
+	jwks := tokens.NewKeySet(signingKey).JWKS()
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		p.keyFetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *fakeProvider) verifier() *federation.Provider {
+	return federation.NewProvider(federation.Config{
+		Name:       "fake",
+		Issuers:    []string{p.server.URL},
+		JWKSURL:    p.server.URL + "/keys",
+		Audiences:  []string{testAudience},
+		HTTPClient: p.server.Client(),
+	})
+}
+
+// claims returns the claims of a valid ID token; tests change them before signing.
+func (p *fakeProvider) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            p.server.URL,
+		"sub":            "1234567890",
+		"aud":            testAudience,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          "n-0S6_WzA2Mj",
+		"email":          "owner@gmail.com",
+		"email_verified": true,
+		"name":           "Aida Owner",
+	}
+}
+
+func (p *fakeProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.kid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatalf("failed to sign id token: %v", err)
+	}
+	return signed
+}
+
+func TestVerify_ValidToken_MapsClaims(t *testing.T) {
+	p := newFakeProvider(t)
+
+	claims, err := p.verifier().Verify(context.Background(), p.sign(t, p.claims()), "n-0S6_WzA2Mj")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if claims.Subject != "1234567890" || claims.Email != "owner@gmail.com" || !claims.EmailVerified || claims.Name != "Aida Owner" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestVerify_EmailVerifiedAsString(t *testing.T) {
+	p := newFakeProvider(t)
+	c := p.claims()
+	c["email_verified"] = "true"
+
+	claims, err := p.verifier().Verify(context.Background(), p.sign(t, c), "")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !claims.EmailVerified {
+		t.Error("expected email_verified \"true\" to be read as verified")
+	}
+}
+
+func TestVerify_RejectsInvalidTokens(t *testing.T) {
+	p := newFakeProvider(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	cases := map[string]func() (string, string){
+		"other audience": func() (string, string) {
+			c := p.claims()
+			c["aud"] = "someone-elses-app"
+			return p.sign(t, c), ""
+		},
+		"other issuer": func() (string, string) {
+			c := p.claims()
+			c["iss"] = "https://evil.example"
+			return p.sign(t, c), ""
+		},
+		"expired": func() (string, string) {
+			c := p.claims()
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+			return p.sign(t, c), ""
+		},
+		"no expiry": func() (string, string) {
+			c := p.claims()
+			delete(c, "exp")
+			return p.sign(t, c), ""
+		},
+		"no subject": func() (string, string) {
+			c := p.claims()
+			delete(c, "sub")
+			return p.sign(t, c), ""
+		},
+		"nonce mismatch": func() (string, string) {
+			return p.sign(t, p.claims()), "another-nonce"
+		},
+		"signed by another key": func() (string, string) {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims())
+			token.Header["kid"] = p.kid
+			signed, err := token.SignedString(otherKey)
+			if err != nil {
+				t.Fatalf("failed to sign: %v", err)
+			}
+			return signed, ""
+		},
+		"unknown key": func() (string, string) {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims())
+			token.Header["kid"] = "unknown"
+			signed, err := token.SignedString(p.key)
+			if err != nil {
+				t.Fatalf("failed to sign: %v", err)
+			}
+			return signed, ""
+		},
+	}
+	for name, build := range cases {
+		t.Run(name, func(t *testing.T) {
+			idToken, nonce := build()
+			if _, err := p.verifier().Verify(context.Background(), idToken, nonce); !errors.Is(err, federation.ErrInvalidIDToken) {
+				t.Errorf("expected ErrInvalidIDToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerify_CachesKeys(t *testing.T) {
+	p := newFakeProvider(t)
+	verifier := p.verifier()
+
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Verify(context.Background(), p.sign(t, p.claims()), ""); err != nil {
+			t.Fatalf("Verify %d failed: %v", i, err)
+		}
+	}
+	if fetches := p.keyFetches.Load(); fetches != 1 {
+		t.Errorf("expected the keys to be fetched once, got %d fetches", fetches)
+	}
+}
+
+func TestVerify_ProviderUnavailable(t *testing.T) {
+	p := newFakeProvider(t)
+	idToken := p.sign(t, p.claims())
+	p.server.Close()
+
+	if _, err := p.verifier().Verify(context.Background(), idToken, ""); !errors.Is(err, federation.ErrInvalidIDToken) {
+		t.Errorf("expected ErrInvalidIDToken, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package federation
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+)
+
+const (
+	// keysMaxAge is how long fetched keys are used before they are fetched again.
+	keysMaxAge = time.Hour
+	// keysMinRefreshInterval limits how often the keys are fetched, so tokens with
+	// forged key IDs or an unreachable provider do not turn into a flood of requests.
+	keysMinRefreshInterval = time.Minute
+	// maxJWKSSize bounds the size of a provider's key set response.
+	maxJWKSSize = 1 << 20
+)
+
+// keyCache holds a provider's RSA signing keys by key ID. Keys are fetched on first
+// use, again once they are keysMaxAge old, and again when a token names a key that is
+// not known yet, which is how providers roll their keys.
+type keyCache struct {
+	url    string
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func newKeyCache(url string, client *http.Client) *keyCache {
+	return &keyCache{url: url, client: client}
+}
+
+// lookup returns the key with the given ID.
+func (c *keyCache) lookup(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	key, ok := c.keys[kid]
+	if ok && now.Sub(c.fetchedAt) <= keysMaxAge {
+		return key, nil
+	}
+	if now.Sub(c.attemptedAt) >= keysMinRefreshInterval {
+		c.attemptedAt = now
+		if err := c.refresh(ctx, now); err != nil {
+			if ok {
+				// Keep verifying with a known key while the provider is unreachable.
+				return key, nil
+			}
+			return nil, err
+		}
+		key, ok = c.keys[kid]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// refresh fetches the provider's key set. Keys other than RSA signing keys are skipped.
+func (c *keyCache) refresh(ctx context.Context, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build key set request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch key set: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch key set: status %d", resp.StatusCode)
+	}
+
+	var set tokens.JWKS
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode key set: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := rsaPublicKey(jwk)
+		if err != nil {
+			return fmt.Errorf("invalid key %q: %w", jwk.KeyID, err)
+		}
+		keys[jwk.KeyID] = key
+	}
+	c.keys = keys
+	c.fetchedAt = now
+	return nil
+}
+
+// rsaPublicKey decodes the modulus and exponent of an RSA JWK.
+func rsaPublicKey(jwk tokens.JWK) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid RSA parameters")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
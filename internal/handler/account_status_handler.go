@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AccountStatusService defines the application-layer contract the account status handler depends on.
+type AccountStatusService interface {
+	BanUser(ctx context.Context, actorID, userID uuid.UUID, req application.BanUserRequest) error
+	SuspendUser(ctx context.Context, actorID, userID uuid.UUID, req application.SuspendUserRequest) error
+	UnbanUser(ctx context.Context, actorID, userID uuid.UUID) error
+	UnlockUser(ctx context.Context, actorID, userID uuid.UUID) error
+}
+
+// AccountStatusHandler handles the admin endpoints that ban, suspend, reinstate and unlock accounts.
+type AccountStatusHandler struct {
+	service AccountStatusService
+	logger  *zap.Logger
+}
+
+// NewAccountStatusHandler creates a new AccountStatusHandler.
+func NewAccountStatusHandler(service AccountStatusService, logger *zap.Logger) *AccountStatusHandler {
+	return &AccountStatusHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers account status routes on the given router group.
+func (h *AccountStatusHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator, mfaPolicy MFAPolicy) {
+	users := r.Group("/api/v1/admin/users")
+	users.Use(authMiddleware(validator), requireRole(auth.RoleAdmin), requireMFA(mfaPolicy))
+	{
+		users.POST("/:id/ban", h.BanUser)
+		users.POST("/:id/suspend", h.SuspendUser)
+		users.POST("/:id/unban", h.UnbanUser)
+		users.POST("/:id/unlock", h.UnlockUser)
+	}
+}
+
+// BanUser handles POST /api/v1/admin/users/:id/ban.
+func (h *AccountStatusHandler) BanUser(c *gin.Context) {
+	actorID, userID, ok := h.parseActorAndTarget(c)
+	if !ok {
+		return
+	}
+
+	var req application.BanUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	if err := h.service.BanUser(c.Request.Context(), actorID, userID, req); err != nil {
+		h.logger.Warn("ban user failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "user banned successfully"})
+}
+
+// SuspendUser handles POST /api/v1/admin/users/:id/suspend.
+func (h *AccountStatusHandler) SuspendUser(c *gin.Context) {
+	actorID, userID, ok := h.parseActorAndTarget(c)
+	if !ok {
+		return
+	}
+
+	var req application.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.SuspendUser(c.Request.Context(), actorID, userID, req); err != nil {
+		h.logger.Warn("suspend user failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "user suspended successfully"})
+}
+
+// UnbanUser handles POST /api/v1/admin/users/:id/unban.
+func (h *AccountStatusHandler) UnbanUser(c *gin.Context) {
+	actorID, userID, ok := h.parseActorAndTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnbanUser(c.Request.Context(), actorID, userID); err != nil {
+		h.logger.Warn("unban user failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "user reinstated successfully"})
+}
+
+// UnlockUser handles POST /api/v1/admin/users/:id/unlock.
+func (h *AccountStatusHandler) UnlockUser(c *gin.Context) {
+	actorID, userID, ok := h.parseActorAndTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnlockUser(c.Request.Context(), actorID, userID); err != nil {
+		h.logger.Warn("unlock user failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "user unlocked successfully"})
+}
+
+// parseActorAndTarget reads the acting admin from the token and the target user from
+// the path, writing a 400 response and returning false if either is missing.
+func (h *AccountStatusHandler) parseActorAndTarget(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return actorID, userID, true
+}
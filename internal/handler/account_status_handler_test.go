@@ -0,0 +1,161 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeAccountStatusService struct {
+	actorID    uuid.UUID
+	userID     uuid.UUID
+	banReq     application.BanUserRequest
+	suspendReq application.SuspendUserRequest
+	unbanned   bool
+	unlocked   bool
+}
+
+func (f *fakeAccountStatusService) BanUser(_ context.Context, actorID, userID uuid.UUID, req application.BanUserRequest) error {
+	f.actorID, f.userID, f.banReq = actorID, userID, req
+	return nil
+}
+
+func (f *fakeAccountStatusService) SuspendUser(_ context.Context, actorID, userID uuid.UUID, req application.SuspendUserRequest) error {
+	f.actorID, f.userID, f.suspendReq = actorID, userID, req
+	return nil
+}
+
+func (f *fakeAccountStatusService) UnbanUser(_ context.Context, actorID, userID uuid.UUID) error {
+	f.actorID, f.userID, f.unbanned = actorID, userID, true
+	return nil
+}
+
+func (f *fakeAccountStatusService) UnlockUser(_ context.Context, actorID, userID uuid.UUID) error {
+	f.actorID, f.userID, f.unlocked = actorID, userID, true
+	return nil
+}
+
+func setupAccountStatusRouter(t *testing.T, svc handler.AccountStatusService, role auth.UserRole) (*gin.Engine, string, uuid.UUID) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	h := handler.NewAccountStatusHandler(svc, zap.NewNop())
+	h.RegisterRoutes(&r.RouterGroup, tokenManager, staticMFAPolicy{})
+
+	actorID := uuid.New()
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    actorID,
+		Email:     "admin@kilat.my",
+		Role:      role,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token, actorID
+}
+
+func TestBanUser_WithoutBody_Returns200(t *testing.T) {
+	svc := &fakeAccountStatusService{}
+	r, token, actorID := setupAccountStatusRouter(t, svc, auth.RoleAdmin)
+	userID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+userID.String()+"/ban", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.actorID != actorID || svc.userID != userID {
+		t.Errorf("expected actor %s and user %s, got %s and %s", actorID, userID, svc.actorID, svc.userID)
+	}
+}
+
+func TestSuspendUser_WithUntil_PassesExpiry(t *testing.T) {
+	svc := &fakeAccountStatusService{}
+	r, token, _ := setupAccountStatusRouter(t, svc, auth.RoleAdmin)
+
+	body := bytes.NewBufferString(`{"reason":"chargeback investigation","until":"2030-01-02T15:04:05Z"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+uuid.New().String()+"/suspend", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	want := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	if svc.suspendReq.Until == nil || !svc.suspendReq.Until.Equal(want) {
+		t.Errorf("expected suspension until %s, got %v", want, svc.suspendReq.Until)
+	}
+}
+
+func TestSuspendUser_MissingReason_Returns400(t *testing.T) {
+	r, token, _ := setupAccountStatusRouter(t, &fakeAccountStatusService{}, auth.RoleAdmin)
+
+	body := bytes.NewBufferString(`{}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+uuid.New().String()+"/suspend", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUnbanUser_NonAdmin_Returns403(t *testing.T) {
+	svc := &fakeAccountStatusService{}
+	r, token, _ := setupAccountStatusRouter(t, svc, auth.RoleOwner)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+uuid.New().String()+"/unban", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+	if svc.unbanned {
+		t.Error("service must not be called for non-admin callers")
+	}
+}
+
+func TestUnlockUser_Admin_Returns200(t *testing.T) {
+	svc := &fakeAccountStatusService{}
+	r, token, actorID := setupAccountStatusRouter(t, svc, auth.RoleAdmin)
+	userID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/"+userID.String()+"/unlock", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if !svc.unlocked || svc.actorID != actorID || svc.userID != userID {
+		t.Errorf("expected user %s to be unlocked by %s, got unlocked=%v user=%s actor=%s", userID, actorID, svc.unlocked, svc.userID, svc.actorID)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Kilat-Pet-Delivery/lib-common/auth"
-	"github.com/Kilat-Pet-Delivery/lib-common/middleware"
 	"github.com/Kilat-Pet-Delivery/lib-common/response"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 )
@@ -23,16 +22,15 @@ func NewAdminHandler(service *application.AuthService) *AdminHandler {
 }
 
 // RegisterRoutes registers admin routes.
-func (h *AdminHandler) RegisterRoutes(r *gin.RouterGroup, jwtManager *auth.JWTManager) {
-	authMW := middleware.AuthMiddleware(jwtManager)
-	adminRole := middleware.RequireRole(auth.RoleAdmin)
+func (h *AdminHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator, mfaPolicy MFAPolicy) {
+	authMW := authMiddleware(validator)
+	adminRole := requireRole(auth.RoleAdmin)
 
 	admin := r.Group("/api/v1/admin")
-	admin.Use(authMW, adminRole)
+	admin.Use(authMW, adminRole, requireMFA(mfaPolicy))
 	{
 		admin.GET("/users", h.ListUsers)
 		admin.GET("/users/:id", h.GetUser)
-		admin.POST("/users/:id/ban", h.BanUser)
 		admin.GET("/stats/users", h.UserStats)
 	}
 }
@@ -74,22 +72,6 @@ func (h *AdminHandler) GetUser(c *gin.Context) {
 	response.Success(c, user)
 }
 
-// BanUser handles POST /api/v1/admin/users/:id/ban.
-func (h *AdminHandler) BanUser(c *gin.Context) {
-	userID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.BadRequest(c, "invalid user ID")
-		return
-	}
-
-	if err := h.service.BanUser(c.Request.Context(), userID); err != nil {
-		response.Error(c, err)
-		return
-	}
-
-	response.Success(c, gin.H{"message": "user banned successfully"})
-}
-
 // UserStats handles GET /api/v1/admin/stats/users.
 func (h *AdminHandler) UserStats(c *gin.Context) {
 	stats, err := h.service.GetUserStats(c.Request.Context())
@@ -1,22 +1,36 @@
 package handler
 
 import (
-	"github.com/Kilat-Pet-Delivery/lib-common/auth"
-	"github.com/Kilat-Pet-Delivery/lib-common/middleware"
+	"context"
+	"net/http"
+
 	"github.com/Kilat-Pet-Delivery/lib-common/response"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// AuthService defines the application-layer contract the authentication handler depends on.
+type AuthService interface {
+	Register(ctx context.Context, req application.RegisterRequest, client application.ClientInfo) (*application.AuthResponse, error)
+	Login(ctx context.Context, req application.LoginRequest, client application.ClientInfo) (*application.AuthResponse, error)
+	VerifyMFA(ctx context.Context, req application.MFAVerifyRequest, client application.ClientInfo) (*application.AuthResponse, error)
+	RefreshToken(ctx context.Context, token string, client application.ClientInfo) (*application.AuthResponse, error)
+	Logout(ctx context.Context, userID, sessionID uuid.UUID, tokenID string, req application.LogoutRequest) error
+	ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, req application.ChangePasswordRequest) error
+	GetProfile(ctx context.Context, userID uuid.UUID) (*application.UserDTO, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req application.UpdateProfileRequest) (*application.UserDTO, error)
+}
+
 // AuthHandler handles HTTP requests for authentication endpoints.
 type AuthHandler struct {
-	service *application.AuthService
+	service AuthService
 	logger  *zap.Logger
 }
 
 // NewAuthHandler creates a new AuthHandler.
-func NewAuthHandler(service *application.AuthService, logger *zap.Logger) *AuthHandler {
+func NewAuthHandler(service AuthService, logger *zap.Logger) *AuthHandler {
 	return &AuthHandler{
 		service: service,
 		logger:  logger,
@@ -24,19 +38,21 @@ func NewAuthHandler(service *application.AuthService, logger *zap.Logger) *AuthH
 }
 
 // RegisterRoutes registers all authentication routes on the given router group.
-func (h *AuthHandler) RegisterRoutes(r *gin.RouterGroup, jwtManager *auth.JWTManager) {
+func (h *AuthHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
 	authGroup := r.Group("/auth")
 	{
 		// Public routes (no authentication required)
 		authGroup.POST("/register", h.Register)
 		authGroup.POST("/login", h.Login)
+		authGroup.POST("/mfa/verify", h.VerifyMFA)
 		authGroup.POST("/refresh", h.RefreshToken)
 
 		// Protected routes (authentication required)
 		protected := authGroup.Group("")
-		protected.Use(middleware.AuthMiddleware(jwtManager))
+		protected.Use(authMiddleware(validator))
 		{
 			protected.POST("/logout", h.Logout)
+			protected.POST("/password", h.ChangePassword)
 			protected.GET("/profile", h.GetProfile)
 			protected.PUT("/profile", h.UpdateProfile)
 		}
@@ -51,12 +67,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.Register(c.Request.Context(), req)
+	result, err := h.service.Register(c.Request.Context(), req, clientInfo(c))
 	if err != nil {
 		h.logger.Error("registration failed", zap.Error(err))
 		response.Error(c, err)
 		return
 	}
+	if result.VerificationRequired {
+		// Verify-first mode: the same answer whether or not the email was taken.
+		c.JSON(http.StatusAccepted, gin.H{"message": "check your email to finish creating your account"})
+		return
+	}
 
 	response.Created(c, result)
 }
@@ -69,10 +90,28 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.Login(c.Request.Context(), req)
+	result, err := h.service.Login(c.Request.Context(), req, clientInfo(c))
 	if err != nil {
-		h.logger.Error("login failed", zap.Error(err))
-		response.Error(c, err)
+		h.logger.Warn("login failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// VerifyMFA completes a two-factor sign-in with the challenge token returned by Login.
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req application.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.VerifyMFA(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		h.logger.Warn("two-factor verification failed", zap.Error(err))
+		respondError(c, err)
 		return
 	}
 
@@ -89,7 +128,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
+	result, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken, clientInfo(c))
 	if err != nil {
 		h.logger.Error("token refresh failed", zap.Error(err))
 		response.Error(c, err)
@@ -99,15 +138,29 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	response.Success(c, result)
 }
 
-// Logout handles user logout by revoking all refresh tokens.
+// Logout handles user logout by ending the current session, or all sessions when requested.
 func (h *AuthHandler) Logout(c *gin.Context) {
-	userID, ok := middleware.GetUserID(c)
+	userID, ok := currentUserID(c)
 	if !ok {
 		response.BadRequest(c, "user ID not found in context")
 		return
 	}
+	sessionID, ok := currentSessionID(c)
+	if !ok {
+		response.BadRequest(c, "session ID not found in token")
+		return
+	}
 
-	if err := h.service.Logout(c.Request.Context(), userID); err != nil {
+	// The body is optional; an empty body logs out the current session only.
+	var req application.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	if err := h.service.Logout(c.Request.Context(), userID, sessionID, currentTokenID(c), req); err != nil {
 		h.logger.Error("logout failed", zap.Error(err))
 		response.Error(c, err)
 		return
@@ -116,9 +169,37 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	response.Success(c, gin.H{"message": "logged out successfully"})
 }
 
+// ChangePassword changes the authenticated user's password and signs out their other devices.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+	sessionID, ok := currentSessionID(c)
+	if !ok {
+		response.BadRequest(c, "session ID not found in token")
+		return
+	}
+
+	var req application.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), userID, sessionID, req); err != nil {
+		h.logger.Warn("change password failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "password changed successfully"})
+}
+
 // GetProfile retrieves the authenticated user's profile.
 func (h *AuthHandler) GetProfile(c *gin.Context) {
-	userID, ok := middleware.GetUserID(c)
+	userID, ok := currentUserID(c)
 	if !ok {
 		response.BadRequest(c, "user ID not found in context")
 		return
@@ -136,7 +217,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 // UpdateProfile updates the authenticated user's profile.
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
-	userID, ok := middleware.GetUserID(c)
+	userID, ok := currentUserID(c)
 	if !ok {
 		response.BadRequest(c, "user ID not found in context")
 		return
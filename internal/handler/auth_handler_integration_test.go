@@ -0,0 +1,264 @@
+//go:build integration
+
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/passwords"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// spyHasher is a bcrypt hasher that counts password checks.
+type spyHasher struct {
+	*passwords.BcryptHasher
+	mu       sync.Mutex
+	verified int
+}
+
+func (h *spyHasher) Verify(encoded, password string) (bool, error) {
+	h.mu.Lock()
+	h.verified++
+	h.mu.Unlock()
+	return h.BcryptHasher.Verify(encoded, password)
+}
+
+func (h *spyHasher) verifyCalls() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.verified
+}
+
+func newSpyHasher(t *testing.T) *spyHasher {
+	t.Helper()
+	hasher, err := passwords.NewBcryptHasher(bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher failed: %v", err)
+	}
+	return &spyHasher{BcryptHasher: hasher}
+}
+
+// spyVerificationNotifier records the emails it is asked to send.
+type spyVerificationNotifier struct {
+	mu                   sync.Mutex
+	verifications        []string
+	registrationAttempts []string
+}
+
+func (n *spyVerificationNotifier) SendVerificationEmail(_ context.Context, email, _ string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.verifications = append(n.verifications, email)
+	return nil
+}
+
+func (n *spyVerificationNotifier) SendRegistrationAttemptEmail(_ context.Context, email string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.registrationAttempts = append(n.registrationAttempts, email)
+	return nil
+}
+
+func setupAuthIntegrationRouter(t *testing.T, authService *application.AuthService) (*gin.Engine, *tokens.Manager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	handler.NewAuthHandler(authService, zap.NewNop()).RegisterRoutes(r.Group("/api/v1"), tokenManager)
+	return r, tokenManager
+}
+
+// seedVerifiedIntegrationUser seeds a user with a verified email and returns its ID and email.
+func seedVerifiedIntegrationUser(t *testing.T, db *gorm.DB) (uuid.UUID, string) {
+	t.Helper()
+	userID, _ := seedIntegrationUser(t, db)
+	t.Cleanup(func() {
+		db.Where("id = ?", userID).Delete(&repository.UserModel{})
+	})
+	if err := db.Model(&repository.UserModel{}).Where("id = ?", userID).Update("is_verified", true).Error; err != nil {
+		t.Fatalf("failed to verify seeded user: %v", err)
+	}
+	var model repository.UserModel
+	if err := db.Where("id = ?", userID).First(&model).Error; err != nil {
+		t.Fatalf("failed to read seeded user: %v", err)
+	}
+	return userID, model.Email
+}
+
+func postJSON(r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogin_UnknownEmail_ChecksDummyHash_SameAnswerAsWrongPassword(t *testing.T) {
+	db := setupIntegrationDB(t)
+	_, email := seedVerifiedIntegrationUser(t, db)
+
+	hasher := newSpyHasher(t)
+	r, _ := setupAuthIntegrationRouter(t, newIntegrationAuthService(t, db, integrationAuthOptions{hasher: hasher}))
+
+	wrongPassword := postJSON(r, "/api/v1/auth/login", `{"email":"`+email+`","password":"wrongpass123"}`)
+	afterWrongPassword := hasher.verifyCalls()
+	unknownEmail := postJSON(r, "/api/v1/auth/login", `{"email":"`+uuid.NewString()+`@reset-integration-test.local","password":"wrongpass123"}`)
+
+	if wrongPassword.Code != http.StatusUnauthorized || unknownEmail.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for both, got %d and %d", wrongPassword.Code, unknownEmail.Code)
+	}
+	if unknownEmail.Body.String() != wrongPassword.Body.String() {
+		t.Errorf("expected the same answer for an unknown email, got %s and %s", unknownEmail.Body.String(), wrongPassword.Body.String())
+	}
+	if afterWrongPassword != 1 {
+		t.Errorf("expected the wrong password to be checked once, got %d checks", afterWrongPassword)
+	}
+	if checks := hasher.verifyCalls() - afterWrongPassword; checks != 1 {
+		t.Errorf("expected an unknown email to be checked against the dummy hash once, got %d checks", checks)
+	}
+}
+
+func TestRegister_VerifyFirst_TakenAndNewEmail_SameAnswer(t *testing.T) {
+	db := setupIntegrationDB(t)
+	_, takenEmail := seedVerifiedIntegrationUser(t, db)
+	newEmail := uuid.NewString() + "@reset-integration-test.local"
+	t.Cleanup(func() {
+		db.Where("email = ?", newEmail).Delete(&repository.UserModel{})
+	})
+
+	notifier := &spyVerificationNotifier{}
+	r, _ := setupAuthIntegrationRouter(t, newIntegrationAuthService(t, db, integrationAuthOptions{
+		verificationNotifier: notifier,
+		registrationMode:     application.RegistrationModeVerifyFirst,
+	}))
+
+	register := func(email string) *httptest.ResponseRecorder {
+		return postJSON(r, "/api/v1/auth/register", `{"email":"`+email+`","full_name":"Aida Owner","password":"correct-horse-battery-9","role":"owner"}`)
+	}
+	taken := register(takenEmail)
+	fresh := register(newEmail)
+
+	if taken.Code != http.StatusAccepted || fresh.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for both, got %d and %d — bodies: %s, %s", taken.Code, fresh.Code, taken.Body.String(), fresh.Body.String())
+	}
+	if taken.Body.String() != fresh.Body.String() {
+		t.Errorf("expected the same answer for a taken email, got %s and %s", taken.Body.String(), fresh.Body.String())
+	}
+	if bytes.Contains(fresh.Body.Bytes(), []byte("access_token")) {
+		t.Errorf("expected no tokens before the email is verified, got %s", fresh.Body.String())
+	}
+
+	var count int64
+	db.Model(&repository.UserModel{}).Where("email = ?", takenEmail).Count(&count)
+	if count != 1 {
+		t.Errorf("expected the taken email to keep one account, got %d", count)
+	}
+}
+
+func TestRegister_VerifyFirst_TakenEmail_NotifiesOwner(t *testing.T) {
+	db := setupIntegrationDB(t)
+	_, takenEmail := seedVerifiedIntegrationUser(t, db)
+
+	notifier := &spyVerificationNotifier{}
+	r, _ := setupAuthIntegrationRouter(t, newIntegrationAuthService(t, db, integrationAuthOptions{
+		verificationNotifier: notifier,
+		registrationMode:     application.RegistrationModeVerifyFirst,
+	}))
+
+	w := postJSON(r, "/api/v1/auth/register", `{"email":"`+takenEmail+`","full_name":"Someone Else","password":"correct-horse-battery-9","role":"owner"}`)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if len(notifier.registrationAttempts) != 1 || notifier.registrationAttempts[0] != takenEmail {
+		t.Errorf("expected the owner of %s to be told about the attempt, got %v", takenEmail, notifier.registrationAttempts)
+	}
+	if len(notifier.verifications) != 0 {
+		t.Errorf("expected no verification email for a verified address, got %v", notifier.verifications)
+	}
+}
+
+func TestChangePassword_RevokesOtherSessions_KeepsCurrent_BumpsVersion(t *testing.T) {
+	db := setupIntegrationDB(t)
+	userID, email := seedVerifiedIntegrationUser(t, db)
+	t.Cleanup(func() {
+		db.Where("user_id = ?", userID).Delete(&repository.SessionModel{})
+	})
+
+	sessionRepo := repository.NewGormSessionRepository(db)
+	current := identity.NewSession(userID, "Pixel 8", "android", "okhttp/4.12", "203.0.113.7", []string{tokens.AMRPassword})
+	other := identity.NewSession(userID, "iPad", "ios", "Kilat/2.3", "198.51.100.4", []string{tokens.AMRPassword})
+	for _, session := range []*identity.Session{current, other} {
+		if err := sessionRepo.Save(context.Background(), session); err != nil {
+			t.Fatalf("failed to seed session: %v", err)
+		}
+	}
+	var before repository.UserModel
+	if err := db.Where("id = ?", userID).First(&before).Error; err != nil {
+		t.Fatalf("failed to read user: %v", err)
+	}
+
+	r, tokenManager := setupAuthIntegrationRouter(t, newIntegrationAuthService(t, db, integrationAuthOptions{}))
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    userID,
+		Email:     email,
+		Role:      auth.RoleRunner,
+		SessionID: current.ID(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"current_password":"originalpass99","new_password":"correct-horse-battery-9"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/password", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+
+	active, err := sessionRepo.ListActiveForUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 1 || active[0].ID() != current.ID() {
+		t.Errorf("expected only the current session to stay active, got %d active sessions", len(active))
+	}
+	revoked, err := sessionRepo.FindByID(context.Background(), other.ID())
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if revoked.IsActive() {
+		t.Error("expected the other session to be revoked")
+	}
+
+	var after repository.UserModel
+	if err := db.Where("id = ?", userID).First(&after).Error; err != nil {
+		t.Fatalf("failed to read user: %v", err)
+	}
+	if after.Version != before.Version+1 {
+		t.Errorf("expected the version to go from %d to %d, got %d", before.Version, before.Version+1, after.Version)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(after.PasswordHash), []byte("correct-horse-battery-9")); err != nil {
+		t.Errorf("expected the new password to be stored: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeAuthService struct {
+	changeErr     error
+	changeCalled  bool
+	userID        uuid.UUID
+	keptSessionID uuid.UUID
+	changeReq     application.ChangePasswordRequest
+}
+
+func (f *fakeAuthService) Register(_ context.Context, _ application.RegisterRequest, _ application.ClientInfo) (*application.AuthResponse, error) {
+	return &application.AuthResponse{}, nil
+}
+
+func (f *fakeAuthService) Login(_ context.Context, _ application.LoginRequest, _ application.ClientInfo) (*application.AuthResponse, error) {
+	return &application.AuthResponse{}, nil
+}
+
+func (f *fakeAuthService) VerifyMFA(_ context.Context, _ application.MFAVerifyRequest, _ application.ClientInfo) (*application.AuthResponse, error) {
+	return &application.AuthResponse{}, nil
+}
+
+func (f *fakeAuthService) RefreshToken(_ context.Context, _ string, _ application.ClientInfo) (*application.AuthResponse, error) {
+	return &application.AuthResponse{}, nil
+}
+
+func (f *fakeAuthService) Logout(_ context.Context, _, _ uuid.UUID, _ string, _ application.LogoutRequest) error {
+	return nil
+}
+
+func (f *fakeAuthService) ChangePassword(_ context.Context, userID, sessionID uuid.UUID, req application.ChangePasswordRequest) error {
+	f.changeCalled = true
+	f.userID = userID
+	f.keptSessionID = sessionID
+	f.changeReq = req
+	return f.changeErr
+}
+
+func (f *fakeAuthService) GetProfile(_ context.Context, _ uuid.UUID) (*application.UserDTO, error) {
+	return &application.UserDTO{}, nil
+}
+
+func (f *fakeAuthService) UpdateProfile(_ context.Context, _ uuid.UUID, _ application.UpdateProfileRequest) (*application.UserDTO, error) {
+	return &application.UserDTO{}, nil
+}
+
+func setupAuthRouter(t *testing.T, svc handler.AuthService) (*gin.Engine, string, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewAuthHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1, tokenManager)
+
+	userID := uuid.New()
+	sessionID := uuid.New()
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    userID,
+		Email:     "owner@kilat.my",
+		Role:      auth.RoleOwner,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token, userID, sessionID
+}
+
+func TestChangePassword_Unauthenticated_Returns401(t *testing.T) {
+	svc := &fakeAuthService{}
+	r, _, _, _ := setupAuthRouter(t, svc)
+
+	body := bytes.NewBufferString(`{"current_password":"originalpass99","new_password":"correct-horse-battery-9"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/password", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", w.Code)
+	}
+	if svc.changeCalled {
+		t.Error("expected the service not to be called without a token")
+	}
+}
+
+func TestChangePassword_Rejected_Returns400(t *testing.T) {
+	cases := map[string]error{
+		"wrong current password": domain.NewValidationError("current password is incorrect"),
+		"policy violation":       domain.NewValidationError("password must be at least 10 characters"),
+	}
+	for name, changeErr := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, token, _, _ := setupAuthRouter(t, &fakeAuthService{changeErr: changeErr})
+
+			body := bytes.NewBufferString(`{"current_password":"originalpass99","new_password":"short"}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/password", body)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d — body: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestChangePassword_MissingFields_Returns400(t *testing.T) {
+	svc := &fakeAuthService{}
+	r, token, _, _ := setupAuthRouter(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/password", bytes.NewBufferString(`{"new_password":"correct-horse-battery-9"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+	if svc.changeCalled {
+		t.Error("expected the service not to be called without the current password")
+	}
+}
+
+func TestChangePassword_Valid_Returns200_KeepsCurrentSession(t *testing.T) {
+	svc := &fakeAuthService{}
+	r, token, userID, sessionID := setupAuthRouter(t, svc)
+
+	body := bytes.NewBufferString(`{"current_password":"originalpass99","new_password":"correct-horse-battery-9"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/password", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.userID != userID || svc.keptSessionID != sessionID {
+		t.Errorf("expected user %s and session %s from the token, got %s and %s", userID, sessionID, svc.userID, svc.keptSessionID)
+	}
+	if svc.changeReq.CurrentPassword != "originalpass99" || svc.changeReq.NewPassword != "correct-horse-battery-9" {
+		t.Errorf("expected the passwords to reach the service, got %+v", svc.changeReq)
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers mobile and web clients use to describe the device a session belongs to.
+const (
+	headerDeviceName = "X-Device-Name"
+	headerPlatform   = "X-Platform"
+)
+
+// Lengths of the sessions.device_name and sessions.platform columns, in characters.
+const (
+	maxDeviceNameLength = 100
+	maxPlatformLength   = 30
+)
+
+// clientInfo extracts the device and network details of the request for session tracking.
+// Client-supplied values are cut to fit their columns rather than failing the sign-in.
+func clientInfo(c *gin.Context) application.ClientInfo {
+	return application.ClientInfo{
+		DeviceName: truncateRunes(c.GetHeader(headerDeviceName), maxDeviceNameLength),
+		Platform:   truncateRunes(c.GetHeader(headerPlatform), maxPlatformLength),
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	}
+}
+
+// truncateRunes returns s cut to at most limit characters, never splitting one.
+func truncateRunes(s string, limit int) string {
+	count := 0
+	for i := range s {
+		if count == limit {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
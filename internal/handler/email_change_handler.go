@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EmailChangeService defines the application-layer contract the email change handler depends on.
+type EmailChangeService interface {
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, req application.RequestEmailChangeRequest) error
+	ConfirmEmailChange(ctx context.Context, req application.ConfirmEmailChangeRequest) error
+	CancelEmailChange(ctx context.Context, req application.CancelEmailChangeRequest) error
+}
+
+// EmailChangeHandler handles the email change endpoints.
+type EmailChangeHandler struct {
+	service EmailChangeService
+	logger  *zap.Logger
+}
+
+// NewEmailChangeHandler creates a new EmailChangeHandler.
+func NewEmailChangeHandler(service EmailChangeService, logger *zap.Logger) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers email change routes on the given router group.
+// Confirming and cancelling are public because the links are opened from a mailbox,
+// possibly on a device that is not signed in.
+func (h *EmailChangeHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	change := r.Group("/auth/email/change")
+	change.POST("", authMiddleware(validator), h.RequestEmailChange)
+	change.POST("/confirm", h.ConfirmEmailChange)
+	change.POST("/cancel", h.CancelEmailChange)
+}
+
+// RequestEmailChange handles POST /auth/email/change.
+func (h *EmailChangeHandler) RequestEmailChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.RequestEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.RequestEmailChange(c.Request.Context(), userID, req); err != nil {
+		h.logger.Warn("request email change failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "check the new address to confirm the change"})
+}
+
+// ConfirmEmailChange handles POST /auth/email/change/confirm.
+func (h *EmailChangeHandler) ConfirmEmailChange(c *gin.Context) {
+	var req application.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(c.Request.Context(), req); err != nil {
+		h.logger.Warn("confirm email change failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "email changed; refresh your session to update your access token"})
+}
+
+// CancelEmailChange handles POST /auth/email/change/cancel.
+func (h *EmailChangeHandler) CancelEmailChange(c *gin.Context) {
+	var req application.CancelEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.CancelEmailChange(c.Request.Context(), req); err != nil {
+		h.logger.Warn("cancel email change failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "email change cancelled"})
+}
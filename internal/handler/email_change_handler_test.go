@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeEmailChangeService struct {
+	requestErr error
+	confirmErr error
+	cancelErr  error
+	requested  *application.RequestEmailChangeRequest
+}
+
+func (f *fakeEmailChangeService) RequestEmailChange(_ context.Context, _ uuid.UUID, req application.RequestEmailChangeRequest) error {
+	f.requested = &req
+	return f.requestErr
+}
+
+func (f *fakeEmailChangeService) ConfirmEmailChange(_ context.Context, _ application.ConfirmEmailChangeRequest) error {
+	return f.confirmErr
+}
+
+func (f *fakeEmailChangeService) CancelEmailChange(_ context.Context, _ application.CancelEmailChangeRequest) error {
+	return f.cancelErr
+}
+
+func setupEmailChangeRouter(t *testing.T, svc handler.EmailChangeService) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewEmailChangeHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1, tokenManager)
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "owner@kilat.my",
+		Role:      auth.RoleOwner,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token
+}
+
+func TestRequestEmailChange_Valid_Returns202(t *testing.T) {
+	svc := &fakeEmailChangeService{}
+	r, token := setupEmailChangeRouter(t, svc)
+
+	body := bytes.NewBufferString(`{"new_email":"new.owner@kilat.my","current_password":"s3cret-pass"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/email/change", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.requested == nil || svc.requested.NewEmail != "new.owner@kilat.my" {
+		t.Errorf("expected the new email to reach the service, got %+v", svc.requested)
+	}
+}
+
+func TestRequestEmailChange_Unauthenticated_Returns401(t *testing.T) {
+	r, _ := setupEmailChangeRouter(t, &fakeEmailChangeService{})
+
+	body := bytes.NewBufferString(`{"new_email":"new.owner@kilat.my","current_password":"s3cret-pass"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/email/change", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestRequestEmailChange_Throttled_Returns429WithRetryAfter(t *testing.T) {
+	svc := &fakeEmailChangeService{requestErr: application.NewRateLimitedError("too many email change requests", time.Hour)}
+	r, token := setupEmailChangeRouter(t, svc)
+
+	body := bytes.NewBufferString(`{"new_email":"new.owner@kilat.my","current_password":"s3cret-pass"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/email/change", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "3600" {
+		t.Errorf("expected Retry-After 3600, got %q", got)
+	}
+}
+
+func TestConfirmEmailChange_ValidToken_Returns200(t *testing.T) {
+	r, _ := setupEmailChangeRouter(t, &fakeEmailChangeService{})
+
+	body := bytes.NewBufferString(`{"token":"abc123"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/email/change/confirm", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCancelEmailChange_NoLongerPending_Returns400(t *testing.T) {
+	r, _ := setupEmailChangeRouter(t, &fakeEmailChangeService{cancelErr: domain.NewValidationError("email change is no longer pending")})
+
+	body := bytes.NewBufferString(`{"token":"abc123"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/email/change/cancel", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EmailVerificationService defines the application-layer contract the email verification handler depends on.
+type EmailVerificationService interface {
+	VerifyEmail(ctx context.Context, req application.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+}
+
+// EmailVerificationHandler handles the email verification endpoints.
+type EmailVerificationHandler struct {
+	service EmailVerificationService
+	logger  *zap.Logger
+}
+
+// NewEmailVerificationHandler creates a new EmailVerificationHandler.
+func NewEmailVerificationHandler(service EmailVerificationService, logger *zap.Logger) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers email verification routes on the given router group.
+// Verifying is public because the link may be opened on a device that is not signed in.
+func (h *EmailVerificationHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	verify := r.Group("/auth/verify-email")
+	verify.POST("", h.VerifyEmail)
+	verify.POST("/resend", authMiddleware(validator), h.ResendVerification)
+}
+
+// VerifyEmail handles POST /auth/verify-email.
+func (h *EmailVerificationHandler) VerifyEmail(c *gin.Context) {
+	var req application.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.VerifyEmail(c.Request.Context(), req); err != nil {
+		h.logger.Warn("verify email failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "email verified; refresh your session to update your access token"})
+}
+
+// ResendVerification handles POST /auth/verify-email/resend.
+func (h *EmailVerificationHandler) ResendVerification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	if err := h.service.ResendVerification(c.Request.Context(), userID); err != nil {
+		h.logger.Warn("resend verification failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "verification email sent"})
+}
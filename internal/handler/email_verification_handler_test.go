@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeEmailVerificationService struct {
+	verifyErr error
+	resendErr error
+}
+
+func (f *fakeEmailVerificationService) VerifyEmail(_ context.Context, _ application.VerifyEmailRequest) error {
+	return f.verifyErr
+}
+
+func (f *fakeEmailVerificationService) ResendVerification(_ context.Context, _ uuid.UUID) error {
+	return f.resendErr
+}
+
+func setupEmailVerificationRouter(t *testing.T, svc handler.EmailVerificationService) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewEmailVerificationHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1, tokenManager)
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "owner@kilat.my",
+		Role:      auth.RoleOwner,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token
+}
+
+func TestVerifyEmail_ValidToken_Returns200(t *testing.T) {
+	r, _ := setupEmailVerificationRouter(t, &fakeEmailVerificationService{})
+
+	body := bytes.NewBufferString(`{"token":"abc123"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/verify-email", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestVerifyEmail_ExpiredToken_Returns400(t *testing.T) {
+	r, _ := setupEmailVerificationRouter(t, &fakeEmailVerificationService{verifyErr: domain.NewValidationError("token expired")})
+
+	body := bytes.NewBufferString(`{"token":"abc123"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/verify-email", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestResendVerification_Unauthenticated_Returns401(t *testing.T) {
+	r, _ := setupEmailVerificationRouter(t, &fakeEmailVerificationService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/verify-email/resend", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestResendVerification_Throttled_Returns429WithRetryAfter(t *testing.T) {
+	svc := &fakeEmailVerificationService{resendErr: application.NewRateLimitedError("verification email was sent recently", time.Minute)}
+	r, token := setupEmailVerificationRouter(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/verify-email/resend", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("expected Retry-After 60, got %q", got)
+	}
+}
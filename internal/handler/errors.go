@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+)
+
+// respondError writes err as an HTTP error response. Rate limiting errors become
+// 429 with a Retry-After header; everything else goes through response.Error.
+func respondError(c *gin.Context, err error) {
+	var rateLimited *application.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		seconds := int(math.Ceil(rateLimited.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": rateLimited.Message})
+		return
+	}
+	response.Error(c, err)
+}
@@ -6,13 +6,14 @@ import (
 
 	"github.com/Kilat-Pet-Delivery/lib-common/response"
 	"github.com/Kilat-Pet-Delivery/lib-proto/dto"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 // ForgotPasswordService defines the application-layer contract the handler depends on.
 type ForgotPasswordService interface {
-	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
+	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest, client application.ClientInfo) error
 }
 
 // ForgotPasswordHandler handles POST /auth/forgot-password.
@@ -35,7 +36,8 @@ func (h *ForgotPasswordHandler) RegisterRoutes(r *gin.RouterGroup) {
 }
 
 // ForgotPassword handles POST /auth/forgot-password.
-// Always responds 202 Accepted regardless of whether the email matches a user.
+// Always responds 202 Accepted regardless of whether the email matches a user, unless
+// the client address or the email is over its request limit (429).
 func (h *ForgotPasswordHandler) ForgotPassword(c *gin.Context) {
 	var req dto.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -43,9 +45,9 @@ func (h *ForgotPasswordHandler) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.ForgotPassword(c.Request.Context(), req); err != nil {
-		h.logger.Error("forgot password failed", zap.Error(err))
-		response.Error(c, err)
+	if err := h.service.ForgotPassword(c.Request.Context(), req, clientInfo(c)); err != nil {
+		h.logger.Warn("forgot password failed", zap.Error(err))
+		respondError(c, err)
 		return
 	}
 
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Kilat-Pet-Delivery/lib-proto/dto"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -17,7 +19,7 @@ type fakeForgotPasswordService struct {
 	err error
 }
 
-func (f *fakeForgotPasswordService) ForgotPassword(_ context.Context, _ dto.ForgotPasswordRequest) error {
+func (f *fakeForgotPasswordService) ForgotPassword(_ context.Context, _ dto.ForgotPasswordRequest, _ application.ClientInfo) error {
 	return f.err
 }
 
@@ -60,6 +62,24 @@ func TestForgotPassword_UnknownEmail_AlsoReturns202(t *testing.T) {
 	}
 }
 
+func TestForgotPassword_Throttled_Returns429WithRetryAfter(t *testing.T) {
+	r := setupForgotPasswordRouter(&fakeForgotPasswordService{err: application.NewRateLimitedError("too many password reset requests", 30*time.Minute)})
+
+	body := bytes.NewBufferString(`{"email":"runner.test@kilat.my"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/forgot-password", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1800" {
+		t.Errorf("expected Retry-After 1800, got %q", got)
+	}
+}
+
 func TestForgotPassword_MalformedRequest_Returns400(t *testing.T) {
 	r := setupForgotPasswordRouter(&fakeForgotPasswordService{err: nil})
 
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// contextKeyIntrospectionClient is the gin context key introspectionClientMiddleware stores the caller's client ID under.
+const contextKeyIntrospectionClient = "identity.introspection_client"
+
+// IntrospectionClientAuthenticator checks the OAuth client credentials of introspection callers.
+type IntrospectionClientAuthenticator interface {
+	AuthenticateIntrospectionClient(ctx context.Context, clientID, secret string) error
+}
+
+// introspectionClientMiddleware rejects requests without HTTP Basic credentials of an
+// active OAuth client with the introspect scope, as RFC 7662 requires of callers.
+func introspectionClientMiddleware(clients IntrospectionClientAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, secret, ok := c.Request.BasicAuth()
+		if !ok {
+			c.Header("WWW-Authenticate", `Basic realm="service-identity"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": application.OAuthErrorInvalidClient, "error_description": "client authentication failed"})
+			return
+		}
+		if err := clients.AuthenticateIntrospectionClient(c.Request.Context(), id, secret); err != nil {
+			var oauthErr *application.OAuthError
+			if !errors.As(err, &oauthErr) {
+				response.Error(c, err)
+				c.Abort()
+				return
+			}
+			status := http.StatusForbidden
+			if oauthErr.Code == application.OAuthErrorInvalidClient {
+				status = http.StatusUnauthorized
+				c.Header("WWW-Authenticate", `Basic realm="service-identity"`)
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": oauthErr.Code, "error_description": oauthErr.Description})
+			return
+		}
+		c.Set(contextKeyIntrospectionClient, id)
+		c.Next()
+	}
+}
+
+// IntrospectionService defines the application-layer contract the introspection handler depends on.
+type IntrospectionService interface {
+	Introspect(ctx context.Context, req application.IntrospectRequest) (*application.IntrospectionResponse, error)
+}
+
+// IntrospectionHandler lets other services ask whether an access token is still active.
+type IntrospectionHandler struct {
+	service IntrospectionService
+	logger  *zap.Logger
+}
+
+// NewIntrospectionHandler creates a new IntrospectionHandler.
+func NewIntrospectionHandler(service IntrospectionService, logger *zap.Logger) *IntrospectionHandler {
+	return &IntrospectionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the introspection route on the given router group.
+// Only OAuth clients with the introspect scope may call it.
+func (h *IntrospectionHandler) RegisterRoutes(r *gin.RouterGroup, clients IntrospectionClientAuthenticator) {
+	r.POST("/auth/introspect", introspectionClientMiddleware(clients), h.Introspect)
+}
+
+// Introspect handles POST /auth/introspect.
+// The token is sent form-encoded as in RFC 7662; JSON is accepted too. The answer is
+// returned bare (not in the response envelope), and an unusable token is a 200 with
+// "active": false rather than an error.
+func (h *IntrospectionHandler) Introspect(c *gin.Context) {
+	var req application.IntrospectRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.Introspect(c.Request.Context(), req)
+	if err != nil {
+		h.logger.Error("token introspection failed", zap.Error(err), zap.String("client_id", c.GetString(contextKeyIntrospectionClient)))
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,204 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type fakeIntrospectionService struct {
+	result    *application.IntrospectionResponse
+	err       error
+	lastToken string
+}
+
+func (f *fakeIntrospectionService) Introspect(_ context.Context, req application.IntrospectRequest) (*application.IntrospectionResponse, error) {
+	f.lastToken = req.Token
+	return f.result, f.err
+}
+
+// fakeIntrospectionClients knows one OAuth client with the introspect scope and one without.
+type fakeIntrospectionClients struct {
+	err error
+}
+
+func (f *fakeIntrospectionClients) AuthenticateIntrospectionClient(_ context.Context, clientID, secret string) error {
+	if f.err != nil {
+		return f.err
+	}
+	switch {
+	case clientID == "svc_booking" && secret == "booking-secret":
+		return nil
+	case clientID == "svc_reports" && secret == "reports-secret":
+		return &application.OAuthError{Code: application.OAuthErrorUnauthorizedClient, Description: "client is not allowed to introspect tokens"}
+	}
+	return &application.OAuthError{Code: application.OAuthErrorInvalidClient, Description: "client authentication failed"}
+}
+
+func setupIntrospectionRouter(t *testing.T, svc handler.IntrospectionService) *gin.Engine {
+	t.Helper()
+	return setupIntrospectionRouterWithClients(t, svc, &fakeIntrospectionClients{})
+}
+
+func setupIntrospectionRouterWithClients(t *testing.T, svc handler.IntrospectionService, clients handler.IntrospectionClientAuthenticator) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewIntrospectionHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1, clients)
+	return r
+}
+
+func newIntrospectRequest(token string) *http.Request {
+	form := url.Values{"token": {token}}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/introspect", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestIntrospect_ActiveToken_Returns200(t *testing.T) {
+	svc := &fakeIntrospectionService{result: &application.IntrospectionResponse{Active: true, Subject: "user-1", Role: "owner"}}
+	r := setupIntrospectionRouter(t, svc)
+
+	req := newIntrospectRequest("access-token")
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.lastToken != "access-token" {
+		t.Errorf("expected the form token to reach the service, got %q", svc.lastToken)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body["active"] != true || body["sub"] != "user-1" {
+		t.Errorf("expected a bare active response, got %s", w.Body.String())
+	}
+}
+
+func TestIntrospect_InactiveToken_Returns200WithActiveFalse(t *testing.T) {
+	svc := &fakeIntrospectionService{result: &application.IntrospectionResponse{Active: false}}
+	r := setupIntrospectionRouter(t, svc)
+
+	req := newIntrospectRequest("revoked-token")
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if strings.TrimSpace(w.Body.String()) != `{"active":false}` {
+		t.Errorf("expected only the active flag, got %s", w.Body.String())
+	}
+}
+
+func TestIntrospect_WrongClientSecret_Returns401(t *testing.T) {
+	svc := &fakeIntrospectionService{result: &application.IntrospectionResponse{Active: true}}
+	r := setupIntrospectionRouter(t, svc)
+
+	req := newIntrospectRequest("access-token")
+	req.SetBasicAuth("svc_booking", "wrong-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.lastToken != "" {
+		t.Error("expected the service not to be called")
+	}
+}
+
+func TestIntrospect_ClientWithoutIntrospectScope_Returns403(t *testing.T) {
+	svc := &fakeIntrospectionService{result: &application.IntrospectionResponse{Active: true}}
+	r := setupIntrospectionRouter(t, svc)
+
+	req := newIntrospectRequest("access-token")
+	req.SetBasicAuth("svc_reports", "reports-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.lastToken != "" {
+		t.Error("expected the service not to be called")
+	}
+}
+
+func TestIntrospect_ClientLookupFailure_Returns500(t *testing.T) {
+	svc := &fakeIntrospectionService{result: &application.IntrospectionResponse{Active: true}}
+	r := setupIntrospectionRouterWithClients(t, svc, &fakeIntrospectionClients{err: errors.New("database unavailable")})
+
+	req := newIntrospectRequest("access-token")
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.lastToken != "" {
+		t.Error("expected the service not to be called")
+	}
+}
+
+func TestIntrospect_NoClientCredentials_Returns401(t *testing.T) {
+	r := setupIntrospectionRouter(t, &fakeIntrospectionService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIntrospectRequest("access-token"))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestIntrospect_MissingToken_Returns400(t *testing.T) {
+	r := setupIntrospectionRouter(t, &fakeIntrospectionService{})
+
+	req := newIntrospectRequest("")
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestIntrospect_StorageFailure_Returns500(t *testing.T) {
+	r := setupIntrospectionRouter(t, &fakeIntrospectionService{err: errors.New("database unavailable")})
+
+	req := newIntrospectRequest("access-token")
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
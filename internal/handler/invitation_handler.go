@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// InvitationService defines the application-layer contract the invitation handler depends on.
+type InvitationService interface {
+	Invite(ctx context.Context, actorID uuid.UUID, req application.CreateInvitationRequest) (*application.InvitationDTO, error)
+	ListInvitations(ctx context.Context, page, limit int) ([]application.InvitationDTO, int64, error)
+	RevokeInvitation(ctx context.Context, actorID, invitationID uuid.UUID) error
+	AcceptInvitation(ctx context.Context, req application.AcceptInvitationRequest) (*application.UserDTO, error)
+}
+
+// InvitationHandler handles the admin invitation endpoints and the public accept endpoint.
+type InvitationHandler struct {
+	service InvitationService
+	logger  *zap.Logger
+}
+
+// NewInvitationHandler creates a new InvitationHandler.
+func NewInvitationHandler(service InvitationService, logger *zap.Logger) *InvitationHandler {
+	return &InvitationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers invitation routes on the given router group.
+func (h *InvitationHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator, mfaPolicy MFAPolicy) {
+	// Public: the invitee has no account yet and authenticates with the invitation token.
+	r.Group("/api/v1/auth/invitations").POST("/accept", h.AcceptInvitation)
+
+	admin := r.Group("/api/v1/admin/invitations")
+	admin.Use(authMiddleware(validator), requireRole(auth.RoleAdmin), requireMFA(mfaPolicy))
+	{
+		admin.POST("", h.CreateInvitation)
+		admin.GET("", h.ListInvitations)
+		admin.DELETE("/:id", h.RevokeInvitation)
+	}
+}
+
+// CreateInvitation handles POST /api/v1/admin/invitations.
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.Invite(c.Request.Context(), actorID, req)
+	if err != nil {
+		h.logger.Warn("create invitation failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// ListInvitations handles GET /api/v1/admin/invitations.
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	invitations, total, err := h.service.ListInvitations(c.Request.Context(), page, limit)
+	if err != nil {
+		h.logger.Error("list invitations failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, invitations, total, page, limit)
+}
+
+// RevokeInvitation handles DELETE /api/v1/admin/invitations/:id.
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid invitation ID")
+		return
+	}
+
+	if err := h.service.RevokeInvitation(c.Request.Context(), actorID, invitationID); err != nil {
+		h.logger.Warn("revoke invitation failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "invitation revoked"})
+}
+
+// AcceptInvitation handles POST /api/v1/auth/invitations/accept.
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req application.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.AcceptInvitation(c.Request.Context(), req)
+	if err != nil {
+		h.logger.Warn("accept invitation failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
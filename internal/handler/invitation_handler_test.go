@@ -0,0 +1,150 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeInvitationService struct {
+	invited   *application.CreateInvitationRequest
+	acceptErr error
+}
+
+func (f *fakeInvitationService) Invite(_ context.Context, actorID uuid.UUID, req application.CreateInvitationRequest) (*application.InvitationDTO, error) {
+	f.invited = &req
+	return &application.InvitationDTO{ID: uuid.New(), Email: req.Email, Role: req.Role, Status: "pending", InvitedBy: actorID}, nil
+}
+
+func (f *fakeInvitationService) ListInvitations(_ context.Context, _, _ int) ([]application.InvitationDTO, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeInvitationService) RevokeInvitation(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeInvitationService) AcceptInvitation(_ context.Context, _ application.AcceptInvitationRequest) (*application.UserDTO, error) {
+	if f.acceptErr != nil {
+		return nil, f.acceptErr
+	}
+	return &application.UserDTO{Status: "active"}, nil
+}
+
+func setupInvitationRouter(t *testing.T, svc handler.InvitationService, role auth.UserRole) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	h := handler.NewInvitationHandler(svc, zap.NewNop())
+	h.RegisterRoutes(&r.RouterGroup, tokenManager, staticMFAPolicy{})
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "someone@kilat.my",
+		Role:      role,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token
+}
+
+func TestCreateInvitation_Admin_Returns201(t *testing.T) {
+	svc := &fakeInvitationService{}
+	r, token := setupInvitationRouter(t, svc, auth.RoleAdmin)
+
+	body := bytes.NewBufferString(`{"email":"new.admin@kilat.my","role":"admin"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/invitations", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.invited == nil || svc.invited.Role != "admin" {
+		t.Errorf("expected admin invitation to reach the service, got %+v", svc.invited)
+	}
+}
+
+func TestCreateInvitation_NonAdmin_Returns403(t *testing.T) {
+	svc := &fakeInvitationService{}
+	r, token := setupInvitationRouter(t, svc, auth.RoleOwner)
+
+	body := bytes.NewBufferString(`{"email":"new.admin@kilat.my","role":"admin"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/invitations", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+	if svc.invited != nil {
+		t.Error("service must not be called for non-admin callers")
+	}
+}
+
+func TestCreateInvitation_UnknownRole_Returns400(t *testing.T) {
+	r, token := setupInvitationRouter(t, &fakeInvitationService{}, auth.RoleAdmin)
+
+	body := bytes.NewBufferString(`{"email":"x@kilat.my","role":"superuser"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/invitations", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestAcceptInvitation_Public_Returns201(t *testing.T) {
+	r, _ := setupInvitationRouter(t, &fakeInvitationService{}, auth.RoleOwner)
+
+	body := bytes.NewBufferString(`{"token":"abc","full_name":"New Admin","password":"s3cure-pass"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/invitations/accept", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected 201, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAcceptInvitation_UsedToken_Returns400(t *testing.T) {
+	svc := &fakeInvitationService{acceptErr: domain.NewValidationError("invitation is accepted")}
+	r, _ := setupInvitationRouter(t, svc, auth.RoleOwner)
+
+	body := bytes.NewBufferString(`{"token":"abc","full_name":"New Admin","password":"s3cure-pass"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/invitations/accept", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSProvider exposes the public keys access tokens are signed with.
+type JWKSProvider interface {
+	JWKS() tokens.JWKS
+}
+
+// JWKSHandler serves the JSON Web Key Set other services verify access tokens with.
+type JWKSHandler struct {
+	provider JWKSProvider
+}
+
+// NewJWKSHandler creates a new JWKSHandler.
+func NewJWKSHandler(provider JWKSProvider) *JWKSHandler {
+	return &JWKSHandler{provider: provider}
+}
+
+// RegisterRoutes registers the JWKS route at the server root.
+// No auth middleware — public keys are meant to be fetched by anyone.
+func (h *JWKSHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/.well-known/jwks.json", h.GetJWKS)
+}
+
+// GetJWKS handles GET /.well-known/jwks.json.
+// The key set is returned bare (not in the response envelope) as required by RFC 7517.
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.provider.JWKS())
+}
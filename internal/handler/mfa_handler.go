@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MFAService defines the application-layer contract the two-factor authentication handler depends on.
+type MFAService interface {
+	MFAPolicy
+	Status(ctx context.Context, userID uuid.UUID) (*application.MFAStatusDTO, error)
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (*application.TOTPEnrollmentDTO, error)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, req application.TOTPCodeRequest) error
+	DisableTOTP(ctx context.Context, userID uuid.UUID, req application.TOTPCodeRequest) error
+	GetPolicy(ctx context.Context) (*application.MFAPolicyDTO, error)
+	UpdatePolicy(ctx context.Context, actorID uuid.UUID, req application.UpdateMFAPolicyRequest) (*application.MFAPolicyDTO, error)
+}
+
+// MFAHandler handles authenticator app enrollment and the admin two-factor policy.
+type MFAHandler struct {
+	service MFAService
+	logger  *zap.Logger
+}
+
+// NewMFAHandler creates a new MFAHandler.
+func NewMFAHandler(service MFAService, logger *zap.Logger) *MFAHandler {
+	return &MFAHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers two-factor authentication routes on the given router group.
+func (h *MFAHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	// Enrollment stays reachable without a second factor so users the policy
+	// requires it from can set one up.
+	mfa := r.Group("/api/v1/auth/mfa")
+	mfa.Use(authMiddleware(validator))
+	{
+		mfa.GET("", h.Status)
+		mfa.POST("/totp", h.EnrollTOTP)
+		mfa.POST("/totp/confirm", h.ConfirmTOTP)
+		mfa.POST("/totp/disable", h.DisableTOTP)
+	}
+
+	policy := r.Group("/api/v1/admin/mfa/policy")
+	policy.Use(authMiddleware(validator), requireRole(auth.RoleAdmin), requireMFA(h.service))
+	{
+		policy.GET("", h.GetPolicy)
+		policy.PUT("", h.UpdatePolicy)
+	}
+}
+
+// Status handles GET /api/v1/auth/mfa.
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	result, err := h.service.Status(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// EnrollTOTP handles POST /api/v1/auth/mfa/totp.
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	result, err := h.service.EnrollTOTP(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Warn("totp enrollment failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// ConfirmTOTP handles POST /api/v1/auth/mfa/totp/confirm.
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.ConfirmTOTP(c.Request.Context(), userID, req); err != nil {
+		h.logger.Warn("totp confirmation failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "two-factor authentication enabled"})
+}
+
+// DisableTOTP handles POST /api/v1/auth/mfa/totp/disable.
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.DisableTOTP(c.Request.Context(), userID, req); err != nil {
+		h.logger.Warn("totp disable failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// GetPolicy handles GET /api/v1/admin/mfa/policy.
+func (h *MFAHandler) GetPolicy(c *gin.Context) {
+	result, err := h.service.GetPolicy(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// UpdatePolicy handles PUT /api/v1/admin/mfa/policy.
+func (h *MFAHandler) UpdatePolicy(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.UpdateMFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.UpdatePolicy(c.Request.Context(), actorID, req)
+	if err != nil {
+		h.logger.Warn("mfa policy update failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
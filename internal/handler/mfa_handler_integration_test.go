@@ -0,0 +1,154 @@
+//go:build integration
+
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/totp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// newIntegrationMFAService builds an MFAService wired to the integration database
+// with the same login throttle main.go shares with AuthService.
+func newIntegrationMFAService(t *testing.T, db *gorm.DB) *application.MFAService {
+	t.Helper()
+	logger := zap.NewNop()
+	securityEvents := application.NewLogOnlySecurityEventPublisher(logger)
+	secretBox, err := repository.NewSecretBox("test-mfa-key")
+	if err != nil {
+		t.Fatalf("NewSecretBox failed: %v", err)
+	}
+	loginThrottle := application.NewLoginThrottle(
+		repository.NewGormLoginFailureRepository(db),
+		application.NewLogOnlyAccountLockedNotifier(logger),
+		securityEvents,
+		logger,
+	)
+	return application.NewMFAService(
+		repository.NewGormTOTPFactorRepository(db, secretBox),
+		repository.NewGormMFAPolicyRepository(db),
+		repository.NewGormUserRepository(db),
+		loginThrottle,
+		securityEvents,
+		"Kilat Pet Test",
+		logger,
+	)
+}
+
+// setupMFAIntegrationUser seeds a verified user with a pending authenticator
+// enrollment and returns a router, the user's access token and the TOTP secret.
+func setupMFAIntegrationUser(t *testing.T, db *gorm.DB, svc *application.MFAService) (*gin.Engine, string, string) {
+	t.Helper()
+	userID, email := seedVerifiedIntegrationUser(t, db)
+	t.Cleanup(func() {
+		db.Where("user_id = ?", userID).Delete(&repository.TOTPFactorModel{})
+		db.Where("key = ?", email).Delete(&repository.LoginFailureModel{})
+	})
+
+	enrollment, err := svc.EnrollTOTP(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("EnrollTOTP failed: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	handler.NewMFAHandler(svc, zap.NewNop()).RegisterRoutes(&r.RouterGroup, tokenManager)
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:      userID,
+		Email:       email,
+		Role:        auth.UserRole("runner"),
+		SessionID:   uuid.New(),
+		AuthMethods: []string{tokens.AMRPassword},
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token, enrollment.Secret
+}
+
+// wrongTOTPCode returns a six-digit code the secret does not accept right now.
+func wrongTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	for i := 0; i < 1000000; i++ {
+		code := fmt.Sprintf("%06d", i)
+		if _, ok := totp.Validate(secret, code, time.Now().UTC(), 1); !ok {
+			return code
+		}
+	}
+	t.Fatal("no rejected code found")
+	return ""
+}
+
+func postTOTPCode(r *gin.Engine, path, token, code string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"code":"`+code+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestConfirmTOTP_RepeatedWrongCodes_Returns429(t *testing.T) {
+	db := setupIntegrationDB(t)
+	r, token, secret := setupMFAIntegrationUser(t, db, newIntegrationMFAService(t, db))
+	wrong := wrongTOTPCode(t, secret)
+
+	for i := 1; i <= 3; i++ {
+		if w := postTOTPCode(r, "/api/v1/auth/mfa/totp/confirm", token, wrong); w.Code != http.StatusBadRequest {
+			t.Fatalf("attempt %d: expected 400, got %d — body: %s", i, w.Code, w.Body.String())
+		}
+	}
+
+	w := postTOTPCode(r, "/api/v1/auth/mfa/totp/confirm", token, wrong)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after repeated wrong codes, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+}
+
+func TestDisableTOTP_RepeatedWrongCodes_Returns429(t *testing.T) {
+	db := setupIntegrationDB(t)
+	r, token, secret := setupMFAIntegrationUser(t, db, newIntegrationMFAService(t, db))
+
+	code, err := totp.Code(secret, totp.Step(time.Now().UTC()))
+	if err != nil {
+		t.Fatalf("Code failed: %v", err)
+	}
+	if w := postTOTPCode(r, "/api/v1/auth/mfa/totp/confirm", token, code); w.Code != http.StatusOK {
+		t.Fatalf("confirm: expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+
+	wrong := wrongTOTPCode(t, secret)
+	for i := 1; i <= 3; i++ {
+		if w := postTOTPCode(r, "/api/v1/auth/mfa/totp/disable", token, wrong); w.Code != http.StatusBadRequest {
+			t.Fatalf("attempt %d: expected 400, got %d — body: %s", i, w.Code, w.Body.String())
+		}
+	}
+
+	w := postTOTPCode(r, "/api/v1/auth/mfa/totp/disable", token, wrong)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after repeated wrong codes, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+}
@@ -0,0 +1,198 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeMFAService struct {
+	staticMFAPolicy
+	confirmedCode string
+	policyReq     application.UpdateMFAPolicyRequest
+	policyUpdated bool
+}
+
+func (f *fakeMFAService) Status(_ context.Context, _ uuid.UUID) (*application.MFAStatusDTO, error) {
+	return &application.MFAStatusDTO{}, nil
+}
+
+func (f *fakeMFAService) EnrollTOTP(_ context.Context, _ uuid.UUID) (*application.TOTPEnrollmentDTO, error) {
+	return &application.TOTPEnrollmentDTO{Secret: "SECRET", OTPAuthURI: "otpauth://totp/x"}, nil
+}
+
+func (f *fakeMFAService) ConfirmTOTP(_ context.Context, _ uuid.UUID, req application.TOTPCodeRequest) error {
+	f.confirmedCode = req.Code
+	return nil
+}
+
+func (f *fakeMFAService) DisableTOTP(_ context.Context, _ uuid.UUID, _ application.TOTPCodeRequest) error {
+	return nil
+}
+
+func (f *fakeMFAService) GetPolicy(_ context.Context) (*application.MFAPolicyDTO, error) {
+	return &application.MFAPolicyDTO{RequiredRoles: []string{}}, nil
+}
+
+func (f *fakeMFAService) UpdatePolicy(_ context.Context, _ uuid.UUID, req application.UpdateMFAPolicyRequest) (*application.MFAPolicyDTO, error) {
+	f.policyReq, f.policyUpdated = req, true
+	return &application.MFAPolicyDTO{RequiredRoles: req.RequiredRoles}, nil
+}
+
+func setupMFARouter(t *testing.T, svc handler.MFAService) (*gin.Engine, *tokens.Manager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	h := handler.NewMFAHandler(svc, zap.NewNop())
+	h.RegisterRoutes(&r.RouterGroup, tokenManager)
+	return r, tokenManager
+}
+
+func mfaTestToken(t *testing.T, tokenManager *tokens.Manager, role auth.UserRole, authMethods ...string) string {
+	t.Helper()
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:      uuid.New(),
+		Email:       "admin@kilat.my",
+		Role:        role,
+		SessionID:   uuid.New(),
+		AuthMethods: authMethods,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return token
+}
+
+func TestConfirmTOTP_ValidCode_Returns200(t *testing.T) {
+	svc := &fakeMFAService{}
+	r, tokenManager := setupMFARouter(t, svc)
+	token := mfaTestToken(t, tokenManager, auth.UserRole("shop"), tokens.AMRPassword)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/mfa/totp/confirm", bytes.NewBufferString(`{"code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.confirmedCode != "123456" {
+		t.Errorf("expected code to reach the service, got %q", svc.confirmedCode)
+	}
+}
+
+func TestConfirmTOTP_MalformedCode_Returns400(t *testing.T) {
+	r, tokenManager := setupMFARouter(t, &fakeMFAService{})
+	token := mfaTestToken(t, tokenManager, auth.UserRole("shop"), tokens.AMRPassword)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/mfa/totp/confirm", bytes.NewBufferString(`{"code":"12ab56"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestEnrollTOTP_AllowedWithoutSecondFactor(t *testing.T) {
+	svc := &fakeMFAService{staticMFAPolicy: staticMFAPolicy{auth.RoleAdmin: true}}
+	r, tokenManager := setupMFARouter(t, svc)
+	token := mfaTestToken(t, tokenManager, auth.RoleAdmin, tokens.AMRPassword)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/mfa/totp", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected 201, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateMFAPolicy_RequiredButSingleFactor_Returns403(t *testing.T) {
+	svc := &fakeMFAService{staticMFAPolicy: staticMFAPolicy{auth.RoleAdmin: true}}
+	r, tokenManager := setupMFARouter(t, svc)
+	token := mfaTestToken(t, tokenManager, auth.RoleAdmin, tokens.AMRPassword)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/mfa/policy", bytes.NewBufferString(`{"required_roles":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.policyUpdated {
+		t.Error("expected policy not to be updated without a second factor")
+	}
+}
+
+func TestUpdateMFAPolicy_RequiredAndMultiFactor_Returns200(t *testing.T) {
+	svc := &fakeMFAService{staticMFAPolicy: staticMFAPolicy{auth.RoleAdmin: true}}
+	r, tokenManager := setupMFARouter(t, svc)
+	token := mfaTestToken(t, tokenManager, auth.RoleAdmin, tokens.AMRPassword, tokens.AMROTP, tokens.AMRMFA)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/mfa/policy", bytes.NewBufferString(`{"required_roles":["admin","shop"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if len(svc.policyReq.RequiredRoles) != 2 {
+		t.Errorf("expected 2 required roles, got %v", svc.policyReq.RequiredRoles)
+	}
+}
+
+func TestUpdateMFAPolicy_UnknownRole_Returns400(t *testing.T) {
+	r, tokenManager := setupMFARouter(t, &fakeMFAService{})
+	token := mfaTestToken(t, tokenManager, auth.RoleAdmin, tokens.AMRPassword)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/mfa/policy", bytes.NewBufferString(`{"required_roles":["superuser"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestGetMFAPolicy_NonAdmin_Returns403(t *testing.T) {
+	r, tokenManager := setupMFARouter(t, &fakeMFAService{})
+	token := mfaTestToken(t, tokenManager, auth.RoleOwner, tokens.AMRPassword)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/mfa/policy", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Gin context keys set by the authentication middleware.
+const (
+	contextKeyClaims = "identity.claims"
+)
+
+// AccessTokenValidator validates access tokens issued by this service.
+type AccessTokenValidator interface {
+	ValidateAccessToken(token string) (*tokens.Claims, error)
+}
+
+// RevocationChecker reports whether a validated access token has been revoked.
+type RevocationChecker interface {
+	IsRevoked(claims *tokens.Claims) bool
+}
+
+type revocationCheckingValidator struct {
+	validator   AccessTokenValidator
+	revocations RevocationChecker
+}
+
+// WithRevocationCheck wraps validator so that revoked tokens fail validation with
+// application.ErrTokenRevoked. Handlers given the wrapped validator reject a token as
+// soon as it is revoked instead of when it expires.
+func WithRevocationCheck(validator AccessTokenValidator, revocations RevocationChecker) AccessTokenValidator {
+	return &revocationCheckingValidator{validator: validator, revocations: revocations}
+}
+
+// ValidateAccessToken validates the token, then checks it has not been revoked.
+func (v *revocationCheckingValidator) ValidateAccessToken(token string) (*tokens.Claims, error) {
+	claims, err := v.validator.ValidateAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if v.revocations.IsRevoked(claims) {
+		return nil, application.ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// authMiddleware rejects requests without a valid bearer access token and stores its
+// claims in the gin context. It replaces the shared-secret lib-common middleware now
+// that access tokens are signed with this service's asymmetric keys.
+func authMiddleware(validator AccessTokenValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		raw, found := strings.CutPrefix(header, "Bearer ")
+		if !found || raw == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		claims, err := validator.ValidateAccessToken(raw)
+		if errors.Is(err, application.ErrTokenRevoked) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+
+		c.Set(contextKeyClaims, claims)
+		c.Next()
+	}
+}
+
+// requireRole rejects authenticated requests whose role is not one of roles, and any
+// request made with a token issued to an OAuth client rather than a user.
+// Must run after authMiddleware.
+func requireRole(roles ...auth.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := currentClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if claims.IsClient() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "client tokens cannot act as users"})
+			return
+		}
+		for _, role := range roles {
+			if claims.Role == role {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+	}
+}
+
+// EmailVerificationPolicy decides which roles must verify their email before protected actions.
+type EmailVerificationPolicy interface {
+	RequiresVerifiedEmail(role auth.UserRole) bool
+}
+
+// requireVerifiedEmail rejects requests from roles the policy requires to have a
+// verified email address when the access token says it is not verified yet.
+// Must run after authMiddleware.
+func requireVerifiedEmail(policy EmailVerificationPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := currentClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if policy.RequiresVerifiedEmail(claims.Role) && !claims.EmailVerified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "email address must be verified"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// MFAPolicy decides which roles must sign in with a second factor.
+type MFAPolicy interface {
+	RequiresMFA(ctx context.Context, role auth.UserRole) bool
+}
+
+// requireMFA rejects requests from roles the policy requires to use two-factor
+// authentication when the access token was issued for a single-factor sign-in.
+// Must run after authMiddleware.
+func requireMFA(policy MFAPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := currentClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if !claims.HasAuthMethod(tokens.AMRMFA) && policy.RequiresMFA(c.Request.Context(), claims.Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "two-factor authentication required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// currentClaims returns the access token claims stored by authMiddleware.
+func currentClaims(c *gin.Context) (*tokens.Claims, bool) {
+	v, ok := c.Get(contextKeyClaims)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*tokens.Claims)
+	return claims, ok
+}
+
+// currentUserID returns the authenticated user's ID.
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	claims, ok := currentClaims(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return claims.UserID, true
+}
+
+// currentTokenID returns the jti of the access token, empty for tokens issued without one.
+func currentTokenID(c *gin.Context) string {
+	claims, ok := currentClaims(c)
+	if !ok {
+		return ""
+	}
+	return claims.ID
+}
+
+// currentSessionID returns the session the access token was issued for.
+func currentSessionID(c *gin.Context) (uuid.UUID, bool) {
+	claims, ok := currentClaims(c)
+	if !ok || claims.SessionID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return claims.SessionID, true
+}
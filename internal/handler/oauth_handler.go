@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OAuthClientService defines the application-layer contract the OAuth handler depends on.
+type OAuthClientService interface {
+	CreateClient(ctx context.Context, actorID uuid.UUID, req application.CreateOAuthClientRequest) (*application.OAuthClientSecretDTO, error)
+	ListClients(ctx context.Context) ([]application.OAuthClientDTO, error)
+	RotateSecret(ctx context.Context, actorID, id uuid.UUID) (*application.OAuthClientSecretDTO, error)
+	DisableClient(ctx context.Context, actorID, id uuid.UUID) error
+	IssueToken(ctx context.Context, req application.ClientTokenRequest) (*application.ClientTokenResponse, error)
+}
+
+// AuthorizationCodeExchanger redeems OpenID Connect authorization codes at the token endpoint.
+type AuthorizationCodeExchanger interface {
+	ExchangeCode(ctx context.Context, req application.ClientTokenRequest, client application.ClientInfo) (*application.ClientTokenResponse, error)
+}
+
+// OAuthHandler handles the OAuth2 token endpoint and the admin endpoints that manage
+// registered clients.
+type OAuthHandler struct {
+	service OAuthClientService
+	codes   AuthorizationCodeExchanger
+	logger  *zap.Logger
+}
+
+// NewOAuthHandler creates a new OAuthHandler. codes is nil when OpenID Connect is not
+// configured, and the token endpoint then only supports the client credentials grant.
+func NewOAuthHandler(service OAuthClientService, codes AuthorizationCodeExchanger, logger *zap.Logger) *OAuthHandler {
+	return &OAuthHandler{
+		service: service,
+		codes:   codes,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the token endpoint at the server root and the client
+// management routes under the admin API.
+func (h *OAuthHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator, mfaPolicy MFAPolicy) {
+	// Public: clients authenticate with their own credentials.
+	r.POST("/oauth/token", h.Token)
+
+	admin := r.Group("/api/v1/admin/oauth-clients")
+	admin.Use(authMiddleware(validator), requireRole(auth.RoleAdmin), requireMFA(mfaPolicy))
+	{
+		admin.POST("", h.CreateClient)
+		admin.GET("", h.ListClients)
+		admin.POST("/:id/rotate-secret", h.RotateSecret)
+		admin.POST("/:id/disable", h.DisableClient)
+	}
+}
+
+// Token handles POST /oauth/token.
+// The request is form-encoded; the client authenticates with HTTP Basic or with
+// client_id and client_secret fields. Responses are bare (not in the response
+// envelope) and errors use the RFC 6749 error format.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req application.ClientTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.respondOAuthError(c, &application.OAuthError{Code: application.OAuthErrorInvalidRequest, Description: err.Error()})
+		return
+	}
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		if req.ClientSecret != "" {
+			h.respondOAuthError(c, &application.OAuthError{Code: application.OAuthErrorInvalidRequest, Description: "use only one client authentication method"})
+			return
+		}
+		req.ClientID, req.ClientSecret = id, secret
+	}
+
+	var result *application.ClientTokenResponse
+	var err error
+	if req.GrantType == application.GrantTypeAuthorizationCode && h.codes != nil {
+		result, err = h.codes.ExchangeCode(c.Request.Context(), req, clientInfo(c))
+	} else {
+		result, err = h.service.IssueToken(c.Request.Context(), req)
+	}
+	if err != nil {
+		var oauthErr *application.OAuthError
+		if errors.As(err, &oauthErr) {
+			h.respondOAuthError(c, oauthErr)
+			return
+		}
+		h.logger.Error("issue token failed", zap.Error(err), zap.String("grant_type", req.GrantType))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondOAuthError writes an RFC 6749 error response. Failed client authentication
+// is a 401 with a Basic challenge; every other error is a 400.
+func (h *OAuthHandler) respondOAuthError(c *gin.Context, err *application.OAuthError) {
+	status := http.StatusBadRequest
+	if err.Code == application.OAuthErrorInvalidClient {
+		status = http.StatusUnauthorized
+		c.Header("WWW-Authenticate", `Basic realm="service-identity"`)
+	}
+	c.AbortWithStatusJSON(status, gin.H{"error": err.Code, "error_description": err.Description})
+}
+
+// CreateClient handles POST /api/v1/admin/oauth-clients.
+func (h *OAuthHandler) CreateClient(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.CreateOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.CreateClient(c.Request.Context(), actorID, req)
+	if err != nil {
+		h.logger.Warn("create oauth client failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// ListClients handles GET /api/v1/admin/oauth-clients.
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	result, err := h.service.ListClients(c.Request.Context())
+	if err != nil {
+		h.logger.Error("list oauth clients failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RotateSecret handles POST /api/v1/admin/oauth-clients/:id/rotate-secret.
+func (h *OAuthHandler) RotateSecret(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid client ID")
+		return
+	}
+
+	result, err := h.service.RotateSecret(c.Request.Context(), actorID, id)
+	if err != nil {
+		h.logger.Warn("rotate oauth client secret failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// DisableClient handles POST /api/v1/admin/oauth-clients/:id/disable.
+func (h *OAuthHandler) DisableClient(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid client ID")
+		return
+	}
+
+	if err := h.service.DisableClient(c.Request.Context(), actorID, id); err != nil {
+		h.logger.Warn("disable oauth client failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "client disabled"})
+}
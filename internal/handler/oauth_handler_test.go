@@ -0,0 +1,262 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeOAuthClientService struct {
+	created  *application.CreateOAuthClientRequest
+	tokenReq *application.ClientTokenRequest
+	issueErr error
+	disabled uuid.UUID
+}
+
+func (f *fakeOAuthClientService) CreateClient(_ context.Context, actorID uuid.UUID, req application.CreateOAuthClientRequest) (*application.OAuthClientSecretDTO, error) {
+	f.created = &req
+	return &application.OAuthClientSecretDTO{
+		OAuthClientDTO: application.OAuthClientDTO{ID: uuid.New(), ClientID: "svc_test", Name: req.Name, Scopes: req.Scopes, Status: "active", CreatedBy: actorID},
+		ClientSecret:   "secret",
+	}, nil
+}
+
+func (f *fakeOAuthClientService) ListClients(_ context.Context) ([]application.OAuthClientDTO, error) {
+	return nil, nil
+}
+
+func (f *fakeOAuthClientService) RotateSecret(_ context.Context, _, id uuid.UUID) (*application.OAuthClientSecretDTO, error) {
+	return &application.OAuthClientSecretDTO{OAuthClientDTO: application.OAuthClientDTO{ID: id}, ClientSecret: "new-secret"}, nil
+}
+
+func (f *fakeOAuthClientService) DisableClient(_ context.Context, _, id uuid.UUID) error {
+	f.disabled = id
+	return nil
+}
+
+func (f *fakeOAuthClientService) IssueToken(_ context.Context, req application.ClientTokenRequest) (*application.ClientTokenResponse, error) {
+	f.tokenReq = &req
+	if f.issueErr != nil {
+		return nil, f.issueErr
+	}
+	return &application.ClientTokenResponse{AccessToken: "jwt", TokenType: "Bearer", ExpiresIn: 900, Scope: req.Scope}, nil
+}
+
+type fakeCodeExchanger struct {
+	req *application.ClientTokenRequest
+}
+
+func (f *fakeCodeExchanger) ExchangeCode(_ context.Context, req application.ClientTokenRequest, _ application.ClientInfo) (*application.ClientTokenResponse, error) {
+	f.req = &req
+	return &application.ClientTokenResponse{AccessToken: "user-jwt", TokenType: "Bearer", ExpiresIn: 900, Scope: "openid", RefreshToken: "refresh", IDToken: "id-jwt"}, nil
+}
+
+func setupOAuthRouter(t *testing.T, svc handler.OAuthClientService) (*gin.Engine, *tokens.Manager) {
+	return setupOAuthRouterWithCodes(t, svc, nil)
+}
+
+func setupOAuthRouterWithCodes(t *testing.T, svc handler.OAuthClientService, codes handler.AuthorizationCodeExchanger) (*gin.Engine, *tokens.Manager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	h := handler.NewOAuthHandler(svc, codes, zap.NewNop())
+	h.RegisterRoutes(&r.RouterGroup, tokenManager, staticMFAPolicy{})
+	return r, tokenManager
+}
+
+func newTokenRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestToken_BasicAuth_Returns200(t *testing.T) {
+	svc := &fakeOAuthClientService{}
+	r, _ := setupOAuthRouter(t, svc)
+
+	req := newTokenRequest(url.Values{"grant_type": {"client_credentials"}, "scope": {"bookings:read"}})
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.tokenReq.ClientID != "svc_booking" || svc.tokenReq.ClientSecret != "booking-secret" {
+		t.Errorf("expected Basic credentials to reach the service, got %+v", svc.tokenReq)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body["access_token"] != "jwt" || body["token_type"] != "Bearer" {
+		t.Errorf("expected a bare token response, got %s", w.Body.String())
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected Cache-Control: no-store, got %q", w.Header().Get("Cache-Control"))
+	}
+}
+
+func TestToken_AuthorizationCode_UsesCodeExchanger(t *testing.T) {
+	svc := &fakeOAuthClientService{}
+	codes := &fakeCodeExchanger{}
+	r, _ := setupOAuthRouterWithCodes(t, svc, codes)
+
+	req := newTokenRequest(url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"the-code"},
+		"redirect_uri":  {"https://admin.kilat.my/callback"},
+		"code_verifier": {"dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"},
+	})
+	req.SetBasicAuth("svc_dashboard", "dashboard-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.tokenReq != nil {
+		t.Error("expected the client credentials service not to be called")
+	}
+	if codes.req == nil || codes.req.Code != "the-code" || codes.req.ClientID != "svc_dashboard" || codes.req.RedirectURI != "https://admin.kilat.my/callback" {
+		t.Errorf("expected the code request to reach the exchanger, got %+v", codes.req)
+	}
+	if !strings.Contains(w.Body.String(), `"id_token":"id-jwt"`) {
+		t.Errorf("expected an ID token in the response, got %s", w.Body.String())
+	}
+}
+
+func TestToken_InvalidClient_Returns401(t *testing.T) {
+	svc := &fakeOAuthClientService{issueErr: &application.OAuthError{Code: application.OAuthErrorInvalidClient, Description: "client authentication failed"}}
+	r, _ := setupOAuthRouter(t, svc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newTokenRequest(url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"svc_booking"},
+		"client_secret": {"wrong"},
+	}))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"error":"invalid_client"`) {
+		t.Errorf("expected an RFC 6749 error body, got %s", w.Body.String())
+	}
+}
+
+func TestToken_InvalidScope_Returns400(t *testing.T) {
+	svc := &fakeOAuthClientService{issueErr: &application.OAuthError{Code: application.OAuthErrorInvalidScope, Description: "scope not allowed for this client"}}
+	r, _ := setupOAuthRouter(t, svc)
+
+	req := newTokenRequest(url.Values{"grant_type": {"client_credentials"}, "scope": {"admin"}})
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestToken_TwoAuthMethods_Returns400(t *testing.T) {
+	svc := &fakeOAuthClientService{}
+	r, _ := setupOAuthRouter(t, svc)
+
+	req := newTokenRequest(url.Values{"grant_type": {"client_credentials"}, "client_secret": {"booking-secret"}})
+	req.SetBasicAuth("svc_booking", "booking-secret")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.tokenReq != nil {
+		t.Error("expected the service not to be called")
+	}
+}
+
+func TestCreateOAuthClient_Admin_Returns201(t *testing.T) {
+	svc := &fakeOAuthClientService{}
+	r, tokenManager := setupOAuthRouter(t, svc)
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{UserID: uuid.New(), Role: auth.RoleAdmin, SessionID: uuid.New()})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"name":"service-booking","scopes":["users:read"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/oauth-clients", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.created == nil || svc.created.Name != "service-booking" {
+		t.Errorf("expected the request to reach the service, got %+v", svc.created)
+	}
+}
+
+func TestCreateOAuthClient_NonAdmin_Returns403(t *testing.T) {
+	svc := &fakeOAuthClientService{}
+	r, tokenManager := setupOAuthRouter(t, svc)
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{UserID: uuid.New(), Role: auth.RoleOwner, SessionID: uuid.New()})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"name":"service-booking","scopes":["users:read"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/oauth-clients", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestDisableOAuthClient_ClientToken_Returns401(t *testing.T) {
+	svc := &fakeOAuthClientService{}
+	r, tokenManager := setupOAuthRouter(t, svc)
+	token, _, err := tokenManager.GenerateClientToken("svc_booking", []string{"users:read"})
+	if err != nil {
+		t.Fatalf("failed to generate client token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/oauth-clients/"+uuid.New().String()+"/disable", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a client token on an admin route, got %d", w.Code)
+	}
+	if svc.disabled != uuid.Nil {
+		t.Error("expected the service not to be called")
+	}
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OIDCService defines the application-layer contract the OpenID Connect handler depends on.
+type OIDCService interface {
+	Discovery() *application.OIDCDiscovery
+	ValidateAuthorization(ctx context.Context, req application.AuthorizeRequest) (*application.AuthorizationDTO, error)
+	Authorize(ctx context.Context, req application.AuthorizeRequest, client application.ClientInfo) (*application.AuthorizeResult, error)
+	UserInfo(ctx context.Context, userID uuid.UUID) (*application.UserInfo, error)
+}
+
+// signInPageTemplate is the minimal sign-in page of the authorization endpoint. The
+// authorization request travels in hidden fields from one step to the next.
+var signInPageTemplate = template.Must(template.New("sign-in").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Sign in</title>
+</head>
+<body>
+<main>
+{{if .ClientName}}
+<h1>Sign in to {{.ClientName}}</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<form method="post" action="authorize">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}{{if .MFAToken}}<input type="hidden" name="mfa_token" value="{{.MFAToken}}">
+<label>Authenticator code <input name="otp" inputmode="numeric" autocomplete="one-time-code" required autofocus></label>
+{{else}}<label>Email <input type="email" name="email" value="{{.Email}}" autocomplete="username" required autofocus></label>
+<label>Password <input type="password" name="password" autocomplete="current-password" required></label>
+{{end}}<button type="submit">Sign in</button>
+</form>
+{{else}}
+<h1>Sign-in unavailable</h1>
+<p>{{.Error}}</p>
+{{end}}
+</main>
+</body>
+</html>
+`))
+
+// signInPage is the data of the sign-in page. Without a ClientName it only shows Error.
+type signInPage struct {
+	ClientName string
+	Params     map[string]string
+	Email      string
+	MFAToken   string
+	Error      string
+}
+
+// OIDCHandler serves the OpenID Connect provider endpoints: discovery, the
+// authorization endpoint with its sign-in page, and userinfo. The token endpoint is
+// served by OAuthHandler.
+type OIDCHandler struct {
+	service OIDCService
+	logger  *zap.Logger
+}
+
+// NewOIDCHandler creates a new OIDCHandler.
+func NewOIDCHandler(service OIDCService, logger *zap.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the OpenID Connect routes at the server root.
+func (h *OIDCHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	r.GET("/.well-known/openid-configuration", h.Discovery)
+	r.GET("/oauth/authorize", h.Authorize)
+	r.POST("/oauth/authorize", h.SignIn)
+
+	userinfo := r.Group("/oauth/userinfo")
+	userinfo.Use(authMiddleware(validator))
+	{
+		userinfo.GET("", h.UserInfo)
+		userinfo.POST("", h.UserInfo)
+	}
+}
+
+// Discovery handles GET /.well-known/openid-configuration.
+// The metadata is returned bare (not in the response envelope).
+func (h *OIDCHandler) Discovery(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.service.Discovery())
+}
+
+// Authorize handles GET /oauth/authorize, where clients send users to sign in. A
+// valid request gets the sign-in page; errors go back to the client's redirect URI
+// when it can be trusted, and are shown on the page otherwise.
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	var req application.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.renderPage(c, http.StatusBadRequest, signInPage{Error: "This sign-in link is not valid."})
+		return
+	}
+
+	authz, err := h.service.ValidateAuthorization(c.Request.Context(), req)
+	if err != nil {
+		h.authorizationFailed(c, err, http.StatusFound)
+		return
+	}
+
+	h.renderPage(c, http.StatusOK, signInPage{ClientName: authz.ClientName, Params: authorizationParams(req)})
+}
+
+// SignIn handles POST /oauth/authorize, the sign-in page's form. Once the user is
+// signed in they are sent back to the client with an authorization code.
+func (h *OIDCHandler) SignIn(c *gin.Context) {
+	var req application.AuthorizeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.renderPage(c, http.StatusBadRequest, signInPage{Error: "This sign-in link is not valid."})
+		return
+	}
+
+	authz, err := h.service.ValidateAuthorization(c.Request.Context(), req)
+	if err != nil {
+		h.authorizationFailed(c, err, http.StatusSeeOther)
+		return
+	}
+	page := signInPage{ClientName: authz.ClientName, Params: authorizationParams(req), Email: req.Email, MFAToken: req.MFAToken}
+
+	result, err := h.service.Authorize(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		var authzErr *application.AuthorizationError
+		var rateLimited *application.RateLimitedError
+		switch {
+		case errors.As(err, &authzErr), errors.Is(err, application.ErrInvalidAuthorizationClient):
+			h.authorizationFailed(c, err, http.StatusSeeOther)
+		case errors.As(err, &rateLimited):
+			page.Error = rateLimited.Message
+			h.renderPage(c, http.StatusTooManyRequests, page)
+		default:
+			h.logger.Warn("authorization sign-in failed", zap.Error(err), zap.String("client_id", req.ClientID))
+			page.Error = "Incorrect email or password."
+			if req.MFAToken != "" {
+				page.Error = "That code did not work. Try again."
+			}
+			h.renderPage(c, http.StatusUnauthorized, page)
+		}
+		return
+	}
+	if result.MFARequired {
+		page.MFAToken = result.MFAToken
+		h.renderPage(c, http.StatusOK, page)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusSeeOther, result.RedirectURL)
+}
+
+// authorizationFailed answers an invalid authorization request: at the client's
+// redirect URI with status, or on the page when the client or redirect URI is unknown.
+func (h *OIDCHandler) authorizationFailed(c *gin.Context, err error, status int) {
+	var authzErr *application.AuthorizationError
+	switch {
+	case errors.As(err, &authzErr):
+		c.Redirect(status, authzErr.RedirectURL())
+	case errors.Is(err, application.ErrInvalidAuthorizationClient):
+		h.renderPage(c, http.StatusBadRequest, signInPage{Error: "This sign-in link is not valid for the application that sent you here."})
+	default:
+		h.logger.Error("authorization request failed", zap.Error(err))
+		h.renderPage(c, http.StatusInternalServerError, signInPage{Error: "Something went wrong. Please try again later."})
+	}
+}
+
+// renderPage writes the sign-in page. It must not be cached or framed by other sites.
+func (h *OIDCHandler) renderPage(c *gin.Context, status int, page signInPage) {
+	var buf bytes.Buffer
+	if err := signInPageTemplate.Execute(&buf, page); err != nil {
+		h.logger.Error("failed to render sign-in page", zap.Error(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// authorizationParams returns the authorization request parameters the sign-in form
+// carries in hidden fields.
+func authorizationParams(req application.AuthorizeRequest) map[string]string {
+	return map[string]string{
+		"response_type":         req.ResponseType,
+		"client_id":             req.ClientID,
+		"redirect_uri":          req.RedirectURI,
+		"scope":                 req.Scope,
+		"state":                 req.State,
+		"nonce":                 req.Nonce,
+		"code_challenge":        req.CodeChallenge,
+		"code_challenge_method": req.CodeChallengeMethod,
+	}
+}
+
+// UserInfo handles GET and POST /oauth/userinfo.
+// The claims are returned bare (not in the response envelope).
+func (h *OIDCHandler) UserInfo(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	result, err := h.service.UserInfo(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Warn("userinfo failed", zap.Error(err), zap.String("user_id", userID.String()))
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, result)
+}
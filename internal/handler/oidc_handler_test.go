@@ -0,0 +1,256 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const testRedirectURI = "https://admin.kilat.my/callback"
+
+type fakeOIDCService struct {
+	validateErr  error
+	authorizeErr error
+	mfaRequired  bool
+	authorized   *application.AuthorizeRequest
+}
+
+func (f *fakeOIDCService) Discovery() *application.OIDCDiscovery {
+	return &application.OIDCDiscovery{Issuer: "https://id.kilat.my", AuthorizationEndpoint: "https://id.kilat.my/oauth/authorize"}
+}
+
+func (f *fakeOIDCService) ValidateAuthorization(_ context.Context, _ application.AuthorizeRequest) (*application.AuthorizationDTO, error) {
+	if f.validateErr != nil {
+		return nil, f.validateErr
+	}
+	return &application.AuthorizationDTO{ClientName: "Admin Dashboard", Scopes: []string{"openid"}}, nil
+}
+
+func (f *fakeOIDCService) Authorize(_ context.Context, req application.AuthorizeRequest, _ application.ClientInfo) (*application.AuthorizeResult, error) {
+	f.authorized = &req
+	if f.authorizeErr != nil {
+		return nil, f.authorizeErr
+	}
+	if f.mfaRequired && req.MFAToken == "" {
+		return &application.AuthorizeResult{MFARequired: true, MFAToken: "mfa-challenge"}, nil
+	}
+	return &application.AuthorizeResult{RedirectURL: req.RedirectURI + "?code=abc&state=" + req.State}, nil
+}
+
+func (f *fakeOIDCService) UserInfo(_ context.Context, userID uuid.UUID) (*application.UserInfo, error) {
+	return &application.UserInfo{Subject: userID.String(), Email: "admin@kilat.my", EmailVerified: true, Role: "admin"}, nil
+}
+
+func setupOIDCRouter(t *testing.T, svc handler.OIDCService) (*gin.Engine, *tokens.Manager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	h := handler.NewOIDCHandler(svc, zap.NewNop())
+	h.RegisterRoutes(&r.RouterGroup, tokenManager)
+	return r, tokenManager
+}
+
+func authorizeQuery() url.Values {
+	return url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"svc_dashboard"},
+		"redirect_uri":          {testRedirectURI},
+		"scope":                 {"openid email"},
+		"state":                 {"xyz"},
+		"code_challenge":        {"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+func newSignInRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestDiscovery_ReturnsBareMetadata(t *testing.T) {
+	r, _ := setupOIDCRouter(t, &fakeOIDCService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body["issuer"] != "https://id.kilat.my" {
+		t.Errorf("expected a bare metadata document, got %s", w.Body.String())
+	}
+}
+
+func TestAuthorize_ValidRequest_ShowsSignInPage(t *testing.T) {
+	r, _ := setupOIDCRouter(t, &fakeOIDCService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+authorizeQuery().Encode(), nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("expected an HTML page, got %q", w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "Admin Dashboard") || !strings.Contains(body, `name="password"`) {
+		t.Errorf("expected a sign-in form for the client, got %s", body)
+	}
+	if !strings.Contains(body, `name="code_challenge" value="E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`) {
+		t.Error("expected the authorization request to be carried in hidden fields")
+	}
+	if w.Header().Get("X-Frame-Options") != "DENY" {
+		t.Error("expected the sign-in page to refuse framing")
+	}
+}
+
+func TestAuthorize_UnregisteredRedirectURI_DoesNotRedirect(t *testing.T) {
+	r, _ := setupOIDCRouter(t, &fakeOIDCService{validateErr: application.ErrInvalidAuthorizationClient})
+
+	query := authorizeQuery()
+	query.Set("redirect_uri", "https://evil.example/callback")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query.Encode(), nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "" {
+		t.Errorf("expected no redirect, got Location %q", location)
+	}
+}
+
+func TestAuthorize_InvalidScope_RedirectsWithError(t *testing.T) {
+	svc := &fakeOIDCService{validateErr: &application.AuthorizationError{
+		Code:        application.OAuthErrorInvalidScope,
+		Description: "the openid scope is required",
+		RedirectURI: testRedirectURI,
+		State:       "xyz",
+	}}
+	r, _ := setupOIDCRouter(t, svc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+authorizeQuery().Encode(), nil))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected 302, got %d", w.Code)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid Location: %v", err)
+	}
+	if location.Host != "admin.kilat.my" || location.Query().Get("error") != "invalid_scope" || location.Query().Get("state") != "xyz" {
+		t.Errorf("unexpected redirect %s", location)
+	}
+}
+
+func TestSignIn_Success_RedirectsWithCode(t *testing.T) {
+	svc := &fakeOIDCService{}
+	r, _ := setupOIDCRouter(t, svc)
+
+	form := authorizeQuery()
+	form.Set("email", "admin@kilat.my")
+	form.Set("password", "Correct-Horse-42")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newSignInRequest(form))
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if location := w.Header().Get("Location"); !strings.HasPrefix(location, testRedirectURI+"?code=abc") {
+		t.Errorf("expected a redirect to the client with the code, got %q", location)
+	}
+	if svc.authorized == nil || svc.authorized.Email != "admin@kilat.my" || svc.authorized.ClientID != "svc_dashboard" {
+		t.Errorf("expected the sign-in to reach the service, got %+v", svc.authorized)
+	}
+}
+
+func TestSignIn_MFARequired_ShowsCodeForm(t *testing.T) {
+	r, _ := setupOIDCRouter(t, &fakeOIDCService{mfaRequired: true})
+
+	form := authorizeQuery()
+	form.Set("email", "admin@kilat.my")
+	form.Set("password", "Correct-Horse-42")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newSignInRequest(form))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `name="otp"`) || !strings.Contains(body, `name="mfa_token" value="mfa-challenge"`) {
+		t.Errorf("expected the authenticator code step, got %s", body)
+	}
+}
+
+func TestSignIn_WrongPassword_Returns401(t *testing.T) {
+	r, _ := setupOIDCRouter(t, &fakeOIDCService{authorizeErr: domain.NewUnauthorizedError("invalid email or password")})
+
+	form := authorizeQuery()
+	form.Set("email", "admin@kilat.my")
+	form.Set("password", "wrong")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newSignInRequest(form))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+	if w.Header().Get("Location") != "" {
+		t.Error("expected the page to be shown again rather than a redirect")
+	}
+	if !strings.Contains(w.Body.String(), `value="admin@kilat.my"`) {
+		t.Error("expected the email to be kept in the form")
+	}
+}
+
+func TestUserInfo_WithAccessToken_Returns200(t *testing.T) {
+	r, tokenManager := setupOIDCRouter(t, &fakeOIDCService{})
+	userID := uuid.New()
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{UserID: userID, Email: "admin@kilat.my", Role: auth.RoleAdmin, SessionID: uuid.New()})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/oauth/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"sub":"`+userID.String()+`"`) {
+		t.Errorf("expected the user's claims, got %s", w.Body.String())
+	}
+}
+
+func TestUserInfo_WithoutToken_Returns401(t *testing.T) {
+	r, _ := setupOIDCRouter(t, &fakeOIDCService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/userinfo", nil))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OTPLoginService defines the application-layer contract the passwordless login handler depends on.
+type OTPLoginService interface {
+	RequestLoginOTP(ctx context.Context, req application.OTPLoginRequest, client application.ClientInfo) error
+	LoginWithOTP(ctx context.Context, req application.OTPVerifyRequest, client application.ClientInfo) (*application.AuthResponse, error)
+}
+
+// OTPLoginHandler handles passwordless sign-in with a phone number and SMS code.
+type OTPLoginHandler struct {
+	service OTPLoginService
+	logger  *zap.Logger
+}
+
+// NewOTPLoginHandler creates a new OTPLoginHandler.
+func NewOTPLoginHandler(service OTPLoginService, logger *zap.Logger) *OTPLoginHandler {
+	return &OTPLoginHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers passwordless login routes on the given router group.
+func (h *OTPLoginHandler) RegisterRoutes(r *gin.RouterGroup) {
+	otp := r.Group("/auth/otp")
+	otp.POST("/request", h.RequestOTP)
+	otp.POST("/verify", h.VerifyOTP)
+}
+
+// RequestOTP handles POST /auth/otp/request.
+// Responds 202 Accepted whether or not the number belongs to an account.
+func (h *OTPLoginHandler) RequestOTP(c *gin.Context) {
+	var req application.OTPLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.RequestLoginOTP(c.Request.Context(), req, clientInfo(c)); err != nil {
+		h.logger.Warn("request login code failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the number is registered, a code has been sent"})
+}
+
+// VerifyOTP handles POST /auth/otp/verify.
+func (h *OTPLoginHandler) VerifyOTP(c *gin.Context) {
+	var req application.OTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.LoginWithOTP(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		h.logger.Warn("login with code failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,129 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type fakeOTPLoginService struct {
+	requestErr error
+	loginErr   error
+	client     application.ClientInfo
+}
+
+func (f *fakeOTPLoginService) RequestLoginOTP(_ context.Context, _ application.OTPLoginRequest, client application.ClientInfo) error {
+	f.client = client
+	return f.requestErr
+}
+
+func (f *fakeOTPLoginService) LoginWithOTP(_ context.Context, _ application.OTPVerifyRequest, client application.ClientInfo) (*application.AuthResponse, error) {
+	f.client = client
+	if f.loginErr != nil {
+		return nil, f.loginErr
+	}
+	return &application.AuthResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func setupOTPLoginRouter(svc handler.OTPLoginService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewOTPLoginHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1)
+	return r
+}
+
+func TestRequestOTP_Returns202(t *testing.T) {
+	r := setupOTPLoginRouter(&fakeOTPLoginService{})
+
+	body := bytes.NewBufferString(`{"phone":"012-345 6789"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/otp/request", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRequestOTP_PassesClientAddress(t *testing.T) {
+	svc := &fakeOTPLoginService{}
+	r := setupOTPLoginRouter(svc)
+
+	body := bytes.NewBufferString(`{"phone":"+60123456789"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/otp/request", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "203.0.113.7:51234"
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.client.IPAddress != "203.0.113.7" {
+		t.Errorf("expected the client address to reach the service for throttling, got %q", svc.client.IPAddress)
+	}
+}
+
+func TestRequestOTP_Throttled_Returns429(t *testing.T) {
+	r := setupOTPLoginRouter(&fakeOTPLoginService{requestErr: application.NewRateLimitedError("a code was sent recently", time.Minute)})
+
+	body := bytes.NewBufferString(`{"phone":"+60123456789"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/otp/request", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", w.Code)
+	}
+}
+
+func TestVerifyOTP_ValidCode_Returns200WithClientInfo(t *testing.T) {
+	svc := &fakeOTPLoginService{}
+	r := setupOTPLoginRouter(svc)
+
+	body := bytes.NewBufferString(`{"phone":"+60123456789","code":"123456"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/otp/verify", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Device-Name", "Galaxy S24")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.client.DeviceName != "Galaxy S24" {
+		t.Errorf("expected device name to reach the service, got %q", svc.client.DeviceName)
+	}
+}
+
+func TestVerifyOTP_WrongCode_Returns401(t *testing.T) {
+	r := setupOTPLoginRouter(&fakeOTPLoginService{loginErr: domain.NewUnauthorizedError("invalid or expired code")})
+
+	body := bytes.NewBufferString(`{"phone":"+60123456789","code":"000000"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/otp/verify", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
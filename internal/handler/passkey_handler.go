@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PasskeyService defines the application-layer contract the passkey management handler depends on.
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, userID uuid.UUID) (*application.PasskeyRegistrationOptionsDTO, error)
+	FinishRegistration(ctx context.Context, userID uuid.UUID, req application.FinishPasskeyRegistrationRequest) (*application.PasskeyDTO, error)
+	ListPasskeys(ctx context.Context, userID uuid.UUID) ([]application.PasskeyDTO, error)
+	DeletePasskey(ctx context.Context, userID, passkeyID uuid.UUID) error
+}
+
+// PasskeyHandler handles registering, listing and removing a user's passkeys.
+type PasskeyHandler struct {
+	service PasskeyService
+	logger  *zap.Logger
+}
+
+// NewPasskeyHandler creates a new PasskeyHandler.
+func NewPasskeyHandler(service PasskeyService, logger *zap.Logger) *PasskeyHandler {
+	return &PasskeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers passkey management routes on the given router group.
+func (h *PasskeyHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	passkeys := r.Group("/auth/passkeys")
+	passkeys.Use(authMiddleware(validator))
+	{
+		passkeys.GET("", h.ListPasskeys)
+		passkeys.POST("/register/begin", h.BeginRegistration)
+		passkeys.POST("/register/finish", h.FinishRegistration)
+		passkeys.DELETE("/:id", h.DeletePasskey)
+	}
+}
+
+// BeginRegistration handles POST /auth/passkeys/register/begin.
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	result, err := h.service.BeginRegistration(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Warn("begin passkey registration failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// FinishRegistration handles POST /auth/passkeys/register/finish.
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.FinishRegistration(c.Request.Context(), userID, req)
+	if err != nil {
+		h.logger.Warn("finish passkey registration failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// ListPasskeys handles GET /auth/passkeys.
+func (h *PasskeyHandler) ListPasskeys(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	result, err := h.service.ListPasskeys(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// DeletePasskey handles DELETE /auth/passkeys/:id.
+func (h *PasskeyHandler) DeletePasskey(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	passkeyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid passkey ID")
+		return
+	}
+
+	if err := h.service.DeletePasskey(c.Request.Context(), userID, passkeyID); err != nil {
+		h.logger.Warn("delete passkey failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "passkey removed"})
+}
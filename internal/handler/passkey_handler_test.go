@@ -0,0 +1,219 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakePasskeyService struct {
+	finished  *application.FinishPasskeyRegistrationRequest
+	deletedID uuid.UUID
+}
+
+func (f *fakePasskeyService) BeginRegistration(_ context.Context, _ uuid.UUID) (*application.PasskeyRegistrationOptionsDTO, error) {
+	return &application.PasskeyRegistrationOptionsDTO{ChallengeID: uuid.New()}, nil
+}
+
+func (f *fakePasskeyService) FinishRegistration(_ context.Context, _ uuid.UUID, req application.FinishPasskeyRegistrationRequest) (*application.PasskeyDTO, error) {
+	f.finished = &req
+	return &application.PasskeyDTO{ID: uuid.New(), Name: req.Name}, nil
+}
+
+func (f *fakePasskeyService) ListPasskeys(_ context.Context, _ uuid.UUID) ([]application.PasskeyDTO, error) {
+	return []application.PasskeyDTO{}, nil
+}
+
+func (f *fakePasskeyService) DeletePasskey(_ context.Context, _, passkeyID uuid.UUID) error {
+	f.deletedID = passkeyID
+	return nil
+}
+
+type fakePasskeyLoginService struct {
+	loginErr error
+	client   application.ClientInfo
+}
+
+func (f *fakePasskeyLoginService) BeginPasskeyLogin(_ context.Context) (*application.PasskeyLoginOptionsDTO, error) {
+	return &application.PasskeyLoginOptionsDTO{ChallengeID: uuid.New()}, nil
+}
+
+func (f *fakePasskeyLoginService) LoginWithPasskey(_ context.Context, _ application.PasskeyLoginRequest, client application.ClientInfo) (*application.AuthResponse, error) {
+	f.client = client
+	if f.loginErr != nil {
+		return nil, f.loginErr
+	}
+	return &application.AuthResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func setupPasskeyRouter(t *testing.T, svc handler.PasskeyService, loginSvc handler.PasskeyLoginService) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	handler.NewPasskeyHandler(svc, zap.NewNop()).RegisterRoutes(apiV1, tokenManager)
+	handler.NewPasskeyLoginHandler(loginSvc, zap.NewNop()).RegisterRoutes(apiV1)
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "owner@kilat.my",
+		Role:      auth.RoleOwner,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token
+}
+
+func TestBeginPasskeyRegistration_NoToken_Returns401(t *testing.T) {
+	r, _ := setupPasskeyRouter(t, &fakePasskeyService{}, &fakePasskeyLoginService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/register/begin", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestFinishPasskeyRegistration_Valid_Returns201(t *testing.T) {
+	svc := &fakePasskeyService{}
+	r, token := setupPasskeyRouter(t, svc, &fakePasskeyLoginService{})
+
+	body := bytes.NewBufferString(`{"challenge_id":"` + uuid.New().String() + `","name":"MacBook","credential":{"id":"Y3JlZA","rawId":"Y3JlZA","type":"public-key","response":{"clientDataJSON":"e30","attestationObject":"oA"}}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/register/finish", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.finished == nil || string(svc.finished.Credential.RawID) != "cred" {
+		t.Errorf("expected the decoded credential to reach the service, got %+v", svc.finished)
+	}
+}
+
+func TestFinishPasskeyRegistration_MissingChallenge_Returns400(t *testing.T) {
+	r, token := setupPasskeyRouter(t, &fakePasskeyService{}, &fakePasskeyLoginService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/register/finish", bytes.NewBufferString(`{"name":"MacBook"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestDeletePasskey_Returns200(t *testing.T) {
+	svc := &fakePasskeyService{}
+	r, token := setupPasskeyRouter(t, svc, &fakePasskeyLoginService{})
+	passkeyID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/auth/passkeys/"+passkeyID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.deletedID != passkeyID {
+		t.Errorf("expected passkey %s to be deleted, got %s", passkeyID, svc.deletedID)
+	}
+}
+
+func TestBeginPasskeyLogin_Public_Returns200(t *testing.T) {
+	r, _ := setupPasskeyRouter(t, &fakePasskeyService{}, &fakePasskeyLoginService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/login/begin", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestFinishPasskeyLogin_PassesClientInfo(t *testing.T) {
+	loginSvc := &fakePasskeyLoginService{}
+	r, _ := setupPasskeyRouter(t, &fakePasskeyService{}, loginSvc)
+
+	body := bytes.NewBufferString(`{"challenge_id":"` + uuid.New().String() + `","credential":{"id":"Y3JlZA","rawId":"Y3JlZA","type":"public-key","response":{}}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/login/finish", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Device-Name", "Aida's MacBook")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if loginSvc.client.DeviceName != "Aida's MacBook" {
+		t.Errorf("expected device name to reach the service, got %q", loginSvc.client.DeviceName)
+	}
+}
+
+func TestFinishPasskeyLogin_OverlongDeviceHeaders_AreTruncated(t *testing.T) {
+	loginSvc := &fakePasskeyLoginService{}
+	r, _ := setupPasskeyRouter(t, &fakePasskeyService{}, loginSvc)
+
+	body := bytes.NewBufferString(`{"challenge_id":"` + uuid.New().String() + `","credential":{"id":"Y3JlZA","rawId":"Y3JlZA","type":"public-key","response":{}}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/login/finish", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Device-Name", strings.Repeat("é", 150))
+	req.Header.Set("X-Platform", strings.Repeat("a", 64))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if loginSvc.client.DeviceName != strings.Repeat("é", 100) {
+		t.Errorf("expected the device name cut to 100 characters, got %d bytes", len(loginSvc.client.DeviceName))
+	}
+	if loginSvc.client.Platform != strings.Repeat("a", 30) {
+		t.Errorf("expected the platform cut to 30 characters, got %q", loginSvc.client.Platform)
+	}
+}
+
+func TestFinishPasskeyLogin_Rejected_Returns401(t *testing.T) {
+	r, _ := setupPasskeyRouter(t, &fakePasskeyService{}, &fakePasskeyLoginService{loginErr: domain.NewUnauthorizedError("passkey sign-in failed")})
+
+	body := bytes.NewBufferString(`{"challenge_id":"` + uuid.New().String() + `","credential":{"id":"Y3JlZA","rawId":"Y3JlZA","type":"public-key","response":{}}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/login/finish", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PasskeyLoginService defines the application-layer contract the passkey sign-in handler depends on.
+type PasskeyLoginService interface {
+	BeginPasskeyLogin(ctx context.Context) (*application.PasskeyLoginOptionsDTO, error)
+	LoginWithPasskey(ctx context.Context, req application.PasskeyLoginRequest, client application.ClientInfo) (*application.AuthResponse, error)
+}
+
+// PasskeyLoginHandler handles passwordless sign-in with a passkey.
+type PasskeyLoginHandler struct {
+	service PasskeyLoginService
+	logger  *zap.Logger
+}
+
+// NewPasskeyLoginHandler creates a new PasskeyLoginHandler.
+func NewPasskeyLoginHandler(service PasskeyLoginService, logger *zap.Logger) *PasskeyLoginHandler {
+	return &PasskeyLoginHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers passkey sign-in routes on the given router group.
+func (h *PasskeyLoginHandler) RegisterRoutes(r *gin.RouterGroup) {
+	login := r.Group("/auth/passkeys/login")
+	login.POST("/begin", h.BeginLogin)
+	login.POST("/finish", h.FinishLogin)
+}
+
+// BeginLogin handles POST /auth/passkeys/login/begin.
+func (h *PasskeyLoginHandler) BeginLogin(c *gin.Context) {
+	result, err := h.service.BeginPasskeyLogin(c.Request.Context())
+	if err != nil {
+		h.logger.Error("begin passkey login failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// FinishLogin handles POST /auth/passkeys/login/finish.
+func (h *PasskeyLoginHandler) FinishLogin(c *gin.Context) {
+	var req application.PasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.LoginWithPasskey(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		h.logger.Warn("passkey login failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
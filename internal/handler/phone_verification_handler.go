@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PhoneVerificationService defines the application-layer contract the phone verification handler depends on.
+type PhoneVerificationService interface {
+	RequestPhoneVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyPhone(ctx context.Context, userID uuid.UUID, req application.VerifyPhoneRequest) (*application.UserDTO, error)
+}
+
+// PhoneVerificationHandler handles the phone number verification endpoints.
+type PhoneVerificationHandler struct {
+	service PhoneVerificationService
+	logger  *zap.Logger
+}
+
+// NewPhoneVerificationHandler creates a new PhoneVerificationHandler.
+func NewPhoneVerificationHandler(service PhoneVerificationService, logger *zap.Logger) *PhoneVerificationHandler {
+	return &PhoneVerificationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers phone verification routes on the given router group.
+func (h *PhoneVerificationHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	phone := r.Group("/auth/phone/verify")
+	phone.Use(authMiddleware(validator))
+	{
+		phone.POST("/request", h.RequestPhoneVerification)
+		phone.POST("", h.VerifyPhone)
+	}
+}
+
+// RequestPhoneVerification handles POST /auth/phone/verify/request.
+func (h *PhoneVerificationHandler) RequestPhoneVerification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	if err := h.service.RequestPhoneVerification(c.Request.Context(), userID); err != nil {
+		h.logger.Warn("request phone verification failed", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "verification code sent"})
+}
+
+// VerifyPhone handles POST /auth/phone/verify.
+func (h *PhoneVerificationHandler) VerifyPhone(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	var req application.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.VerifyPhone(c.Request.Context(), userID, req)
+	if err != nil {
+		h.logger.Warn("verify phone failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakePhoneVerificationService struct {
+	requestErr error
+	verified   bool
+}
+
+func (f *fakePhoneVerificationService) RequestPhoneVerification(_ context.Context, _ uuid.UUID) error {
+	return f.requestErr
+}
+
+func (f *fakePhoneVerificationService) VerifyPhone(_ context.Context, _ uuid.UUID, _ application.VerifyPhoneRequest) (*application.UserDTO, error) {
+	f.verified = true
+	return &application.UserDTO{PhoneVerified: true, Status: "active"}, nil
+}
+
+func setupPhoneVerificationRouter(t *testing.T, svc handler.PhoneVerificationService) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewPhoneVerificationHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1, tokenManager)
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "runner@kilat.my",
+		Role:      auth.RoleRunner,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token
+}
+
+func TestRequestPhoneVerification_Returns202(t *testing.T) {
+	r, token := setupPhoneVerificationRouter(t, &fakePhoneVerificationService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/phone/verify/request", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRequestPhoneVerification_Cooldown_Returns429(t *testing.T) {
+	svc := &fakePhoneVerificationService{requestErr: application.NewRateLimitedError("a code was sent recently", time.Minute)}
+	r, token := setupPhoneVerificationRouter(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/phone/verify/request", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", w.Code)
+	}
+}
+
+func TestVerifyPhone_MalformedCode_Returns400(t *testing.T) {
+	svc := &fakePhoneVerificationService{}
+	r, token := setupPhoneVerificationRouter(t, svc)
+
+	body := bytes.NewBufferString(`{"code":"12ab"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/phone/verify", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+	if svc.verified {
+		t.Error("service must not be called with a malformed code")
+	}
+}
+
+func TestVerifyPhone_ValidCode_Returns200(t *testing.T) {
+	svc := &fakePhoneVerificationService{}
+	r, token := setupPhoneVerificationRouter(t, svc)
+
+	body := bytes.NewBufferString(`{"code":"042917"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/phone/verify", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RecoveryService defines the application-layer contract the recovery handler depends on.
+type RecoveryService interface {
+	FileRecovery(ctx context.Context, req application.FileRecoveryRequest) error
+	ListCases(ctx context.Context, status string, userID *uuid.UUID, page, limit int) ([]application.RecoveryCaseDTO, int64, error)
+	GetCase(ctx context.Context, caseID uuid.UUID) (*application.RecoveryCaseDTO, error)
+	ApproveCase(ctx context.Context, actorID, caseID uuid.UUID, req application.ReviewRecoveryRequest) (*application.RecoveryCaseDTO, error)
+	RejectCase(ctx context.Context, actorID, caseID uuid.UUID, req application.ReviewRecoveryRequest) (*application.RecoveryCaseDTO, error)
+	CompleteRecovery(ctx context.Context, req application.CompleteRecoveryRequest) error
+}
+
+// RecoveryHandler handles the public account recovery endpoints and the admin review queue.
+type RecoveryHandler struct {
+	service RecoveryService
+	logger  *zap.Logger
+}
+
+// NewRecoveryHandler creates a new RecoveryHandler.
+func NewRecoveryHandler(service RecoveryService, logger *zap.Logger) *RecoveryHandler {
+	return &RecoveryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers recovery routes on the given router group.
+func (h *RecoveryHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator, mfaPolicy MFAPolicy) {
+	// Public: the requester cannot sign in, which is why they are here.
+	public := r.Group("/api/v1/auth/recovery")
+	{
+		public.POST("", h.FileRecovery)
+		public.POST("/complete", h.CompleteRecovery)
+	}
+
+	admin := r.Group("/api/v1/admin/recovery")
+	admin.Use(authMiddleware(validator), requireRole(auth.RoleAdmin), requireMFA(mfaPolicy))
+	{
+		admin.GET("", h.ListCases)
+		admin.GET("/:id", h.GetCase)
+		admin.POST("/:id/approve", h.ApproveCase)
+		admin.POST("/:id/reject", h.RejectCase)
+	}
+}
+
+// FileRecovery handles POST /api/v1/auth/recovery.
+func (h *RecoveryHandler) FileRecovery(c *gin.Context) {
+	var req application.FileRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.FileRecovery(c.Request.Context(), req); err != nil {
+		h.logger.Error("file recovery failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	// Same response whether or not the email matched an account.
+	c.JSON(http.StatusAccepted, gin.H{"message": "recovery request received; our support team will contact you"})
+}
+
+// CompleteRecovery handles POST /api/v1/auth/recovery/complete.
+func (h *RecoveryHandler) CompleteRecovery(c *gin.Context) {
+	var req application.CompleteRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.CompleteRecovery(c.Request.Context(), req); err != nil {
+		h.logger.Warn("complete recovery failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "account recovered; sign in with your new email and password"})
+}
+
+// ListCases handles GET /api/v1/admin/recovery.
+func (h *RecoveryHandler) ListCases(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", "pending", "approved", "rejected", "completed":
+	default:
+		response.BadRequest(c, "invalid status filter")
+		return
+	}
+
+	var userID *uuid.UUID
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.BadRequest(c, "invalid user ID")
+			return
+		}
+		userID = &id
+	}
+
+	cases, total, err := h.service.ListCases(c.Request.Context(), status, userID, page, limit)
+	if err != nil {
+		h.logger.Error("list recovery cases failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Paginated(c, cases, total, page, limit)
+}
+
+// GetCase handles GET /api/v1/admin/recovery/:id.
+func (h *RecoveryHandler) GetCase(c *gin.Context) {
+	caseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid recovery case ID")
+		return
+	}
+
+	result, err := h.service.GetCase(c.Request.Context(), caseID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ApproveCase handles POST /api/v1/admin/recovery/:id/approve.
+func (h *RecoveryHandler) ApproveCase(c *gin.Context) {
+	actorID, caseID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ApproveCase(c.Request.Context(), actorID, caseID, req)
+	if err != nil {
+		h.logger.Warn("approve recovery case failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RejectCase handles POST /api/v1/admin/recovery/:id/reject.
+func (h *RecoveryHandler) RejectCase(c *gin.Context) {
+	actorID, caseID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.RejectCase(c.Request.Context(), actorID, caseID, req)
+	if err != nil {
+		h.logger.Warn("reject recovery case failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// bindReview extracts the acting admin, the case ID and the optional review note.
+// It writes the error response itself and reports whether the handler should continue.
+func (h *RecoveryHandler) bindReview(c *gin.Context) (uuid.UUID, uuid.UUID, application.ReviewRecoveryRequest, bool) {
+	var req application.ReviewRecoveryRequest
+
+	actorID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	caseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid recovery case ID")
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, err.Error())
+			return uuid.Nil, uuid.Nil, req, false
+		}
+	}
+	return actorID, caseID, req, true
+}
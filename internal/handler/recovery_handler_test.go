@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeRecoveryService struct {
+	filed      *application.FileRecoveryRequest
+	listStatus string
+	listUserID *uuid.UUID
+	approved   *application.ReviewRecoveryRequest
+}
+
+func (f *fakeRecoveryService) FileRecovery(_ context.Context, req application.FileRecoveryRequest) error {
+	f.filed = &req
+	return nil
+}
+
+func (f *fakeRecoveryService) ListCases(_ context.Context, status string, userID *uuid.UUID, _, _ int) ([]application.RecoveryCaseDTO, int64, error) {
+	f.listStatus, f.listUserID = status, userID
+	return nil, 0, nil
+}
+
+func (f *fakeRecoveryService) GetCase(_ context.Context, caseID uuid.UUID) (*application.RecoveryCaseDTO, error) {
+	return &application.RecoveryCaseDTO{ID: caseID, Status: "pending"}, nil
+}
+
+func (f *fakeRecoveryService) ApproveCase(_ context.Context, _, caseID uuid.UUID, req application.ReviewRecoveryRequest) (*application.RecoveryCaseDTO, error) {
+	f.approved = &req
+	return &application.RecoveryCaseDTO{ID: caseID, Status: "approved"}, nil
+}
+
+func (f *fakeRecoveryService) RejectCase(_ context.Context, _, caseID uuid.UUID, _ application.ReviewRecoveryRequest) (*application.RecoveryCaseDTO, error) {
+	return &application.RecoveryCaseDTO{ID: caseID, Status: "rejected"}, nil
+}
+
+func (f *fakeRecoveryService) CompleteRecovery(_ context.Context, _ application.CompleteRecoveryRequest) error {
+	return nil
+}
+
+func setupRecoveryRouter(t *testing.T, svc handler.RecoveryService, role auth.UserRole) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	h := handler.NewRecoveryHandler(svc, zap.NewNop())
+	h.RegisterRoutes(&r.RouterGroup, tokenManager, staticMFAPolicy{})
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "someone@kilat.my",
+		Role:      role,
+		SessionID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token
+}
+
+func TestFileRecovery_Valid_Returns202(t *testing.T) {
+	svc := &fakeRecoveryService{}
+	r, _ := setupRecoveryRouter(t, svc, auth.RoleOwner)
+
+	body := bytes.NewBufferString(`{"email":"lost@kilat.my","full_name":"Aida Rahman","new_email":"aida@example.com","details":"last order was a cat carrier"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/recovery", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.filed == nil || svc.filed.NewEmail != "aida@example.com" {
+		t.Errorf("expected request to reach the service, got %+v", svc.filed)
+	}
+}
+
+func TestFileRecovery_MissingNewEmail_Returns400(t *testing.T) {
+	r, _ := setupRecoveryRouter(t, &fakeRecoveryService{}, auth.RoleOwner)
+
+	body := bytes.NewBufferString(`{"email":"lost@kilat.my","full_name":"Aida Rahman"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/recovery", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestListRecoveryCases_NonAdmin_Returns403(t *testing.T) {
+	r, token := setupRecoveryRouter(t, &fakeRecoveryService{}, auth.RoleOwner)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/recovery", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestListRecoveryCases_FiltersReachService(t *testing.T) {
+	svc := &fakeRecoveryService{}
+	r, token := setupRecoveryRouter(t, svc, auth.RoleAdmin)
+	userID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/recovery?status=pending&user_id="+userID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.listStatus != "pending" || svc.listUserID == nil || *svc.listUserID != userID {
+		t.Errorf("expected filters to reach the service, got status=%q user=%v", svc.listStatus, svc.listUserID)
+	}
+}
+
+func TestListRecoveryCases_InvalidStatus_Returns400(t *testing.T) {
+	r, token := setupRecoveryRouter(t, &fakeRecoveryService{}, auth.RoleAdmin)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/recovery?status=open", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestApproveRecoveryCase_WithoutBody_Returns200(t *testing.T) {
+	svc := &fakeRecoveryService{}
+	r, token := setupRecoveryRouter(t, svc, auth.RoleAdmin)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/recovery/"+uuid.New().String()+"/approve", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.approved == nil {
+		t.Error("expected approval to reach the service")
+	}
+}
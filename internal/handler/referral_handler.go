@@ -5,8 +5,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 
-	"github.com/Kilat-Pet-Delivery/lib-common/auth"
-	"github.com/Kilat-Pet-Delivery/lib-common/middleware"
 	"github.com/Kilat-Pet-Delivery/lib-common/response"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 )
@@ -21,21 +19,22 @@ func NewReferralHandler(service *application.ReferralService) *ReferralHandler {
 	return &ReferralHandler{service: service}
 }
 
-// RegisterRoutes registers all referral routes.
-func (h *ReferralHandler) RegisterRoutes(r *gin.RouterGroup, jwtManager *auth.JWTManager) {
-	authMW := middleware.AuthMiddleware(jwtManager)
+// RegisterRoutes registers all referral routes. Handing out a referral code is
+// gated on the email verification policy to keep unverified accounts from farming credit.
+func (h *ReferralHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator, policy EmailVerificationPolicy) {
+	authMW := authMiddleware(validator)
 
 	referrals := r.Group("/api/v1/referrals")
 	referrals.Use(authMW)
 	{
 		referrals.GET("/me", h.GetMyReferrals)
-		referrals.GET("/code", h.GetMyReferralCode)
+		referrals.GET("/code", requireVerifiedEmail(policy), h.GetMyReferralCode)
 	}
 }
 
 // GetMyReferrals handles GET /api/v1/referrals/me.
 func (h *ReferralHandler) GetMyReferrals(c *gin.Context) {
-	userID, ok := middleware.GetUserID(c)
+	userID, ok := currentUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
@@ -52,7 +51,7 @@ func (h *ReferralHandler) GetMyReferrals(c *gin.Context) {
 
 // GetMyReferralCode handles GET /api/v1/referrals/code.
 func (h *ReferralHandler) GetMyReferralCode(c *gin.Context) {
-	userID, ok := middleware.GetUserID(c)
+	userID, ok := currentUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
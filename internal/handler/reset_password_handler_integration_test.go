@@ -10,11 +10,12 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Kilat-Pet-Delivery/lib-common/auth"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/passwords"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/webauthn"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -23,8 +24,8 @@ import (
 	"gorm.io/gorm"
 )
 
-func newTestJWTManager() *auth.JWTManager {
-	return auth.NewJWTManager("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+func newTestTokenHasher() *repository.TokenHasher {
+	return repository.NewTokenHasher("test-pepper")
 }
 
 type fakeResetNotifier struct{}
@@ -33,6 +34,10 @@ func (f *fakeResetNotifier) SendPasswordResetEmail(_ context.Context, _, _ strin
 	return nil
 }
 
+func (f *fakeResetNotifier) SendPasswordChangedEmail(_ context.Context, _ string) error {
+	return nil
+}
+
 func setupIntegrationDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	dsn := "host=localhost port=5435 user=kilat password=kilat_secret dbname=kilat_identity sslmode=disable"
@@ -70,13 +75,97 @@ func seedIntegrationToken(t *testing.T, db *gorm.DB, userID uuid.UUID) string {
 	t.Helper()
 	token := "integration-reset-token-" + uuid.NewString()
 	reset := identity.NewPasswordReset(userID, token, time.Now().UTC().Add(time.Hour))
-	repo := repository.NewGormPasswordResetRepository(db)
+	repo := repository.NewGormPasswordResetRepository(db, newTestTokenHasher())
 	if err := repo.Create(context.Background(), reset); err != nil {
 		t.Fatalf("seed password reset token failed: %v", err)
 	}
 	return token
 }
 
+// integrationAuthOptions replaces parts of the AuthService built by
+// newIntegrationAuthService; zero fields keep the defaults.
+type integrationAuthOptions struct {
+	hasher               application.PasswordHasher
+	verificationNotifier application.EmailVerificationNotifier
+	registrationMode     application.RegistrationMode
+}
+
+// newIntegrationAuthService builds an AuthService wired to the integration database
+// the way main.go wires it, with log-only notifiers.
+func newIntegrationAuthService(t *testing.T, db *gorm.DB, opts integrationAuthOptions) *application.AuthService {
+	t.Helper()
+	userRepo := repository.NewGormUserRepository(db)
+	tokenRepo := repository.NewGormTokenRepository(db, newTestTokenHasher())
+	sessionRepo := repository.NewGormSessionRepository(db)
+	passwordResetRepo := repository.NewGormPasswordResetRepository(db, newTestTokenHasher())
+	logger := zap.NewNop()
+	securityEvents := application.NewLogOnlySecurityEventPublisher(logger)
+
+	tokenManager := newTestTokenManager(t)
+	verificationNotifier := opts.verificationNotifier
+	if verificationNotifier == nil {
+		verificationNotifier = application.NewLogOnlyEmailVerificationNotifier(logger)
+	}
+	emailVerifications := application.NewEmailVerificationService(
+		repository.NewGormEmailVerificationRepository(db, newTestTokenHasher()),
+		userRepo,
+		verificationNotifier,
+		logger,
+	)
+	otpService := application.NewOTPService(
+		repository.NewGormPhoneOTPRepository(db),
+		newTestTokenHasher(),
+		application.NewLogOnlySMSSender(logger, false),
+		logger,
+	)
+	secretBox, err := repository.NewSecretBox("test-mfa-key")
+	if err != nil {
+		t.Fatalf("NewSecretBox failed: %v", err)
+	}
+	loginThrottle := application.NewLoginThrottle(
+		repository.NewGormLoginFailureRepository(db),
+		application.NewLogOnlyAccountLockedNotifier(logger),
+		securityEvents,
+		logger,
+	)
+	mfaService := application.NewMFAService(
+		repository.NewGormTOTPFactorRepository(db, secretBox),
+		repository.NewGormMFAPolicyRepository(db),
+		userRepo,
+		loginThrottle,
+		securityEvents,
+		"Kilat Pet Test",
+		logger,
+	)
+	passkeyService := application.NewPasskeyService(
+		repository.NewGormPasskeyRepository(db),
+		repository.NewGormWebAuthnChallengeRepository(db),
+		userRepo,
+		webauthn.NewRelyingParty("localhost", "Kilat Pet Test", []string{"http://localhost:3000"}),
+		securityEvents,
+		logger,
+	)
+	passwordHasher := opts.hasher
+	if passwordHasher == nil {
+		bcryptHasher, err := passwords.NewBcryptHasher(bcrypt.DefaultCost)
+		if err != nil {
+			t.Fatalf("NewBcryptHasher failed: %v", err)
+		}
+		passwordHasher = bcryptHasher
+	}
+	passwordPolicy, err := passwords.NewPolicy(0, nil)
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+	socialLoginService := application.NewSocialLoginService(nil, repository.NewGormExternalIdentityRepository(db), userRepo, passwordHasher, securityEvents, logger)
+	tokenRevocations := application.NewTokenRevocationList(repository.NewGormTokenRevocationRepository(db), tokenManager.AccessExpiry(), logger)
+	registrationMode := opts.registrationMode
+	if registrationMode == "" {
+		registrationMode = application.RegistrationModeImmediate
+	}
+	return application.NewAuthService(userRepo, tokenRepo, sessionRepo, passwordResetRepo, repository.NewGormRequestCounterRepository(db), emailVerifications, otpService, mfaService, passkeyService, socialLoginService, loginThrottle, passwordHasher, passwordPolicy, &fakeResetNotifier{}, securityEvents, tokenManager, tokenRevocations, registrationMode, logger)
+}
+
 func TestResetPassword_ValidToken_Returns200_UpdatesHash(t *testing.T) {
 	db := setupIntegrationDB(t)
 
@@ -90,14 +179,8 @@ func TestResetPassword_ValidToken_Returns200_UpdatesHash(t *testing.T) {
 		db.Where("user_id = ?", userID).Delete(&repository.PasswordResetModel{})
 	})
 
-	userRepo := repository.NewGormUserRepository(db)
-	tokenRepo := repository.NewGormTokenRepository(db)
-	passwordResetRepo := repository.NewGormPasswordResetRepository(db)
-	notifier := &fakeResetNotifier{}
 	logger := zap.NewNop()
-
-	jwtManager := newTestJWTManager()
-	authService := application.NewAuthService(userRepo, tokenRepo, passwordResetRepo, notifier, jwtManager, logger)
+	authService := newIntegrationAuthService(t, db, integrationAuthOptions{})
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -128,7 +211,7 @@ func TestResetPassword_ValidToken_Returns200_UpdatesHash(t *testing.T) {
 
 	// Verify token marked as used
 	var resetModel repository.PasswordResetModel
-	if err := db.Where("token = ?", token).First(&resetModel).Error; err != nil {
+	if err := db.Where("token_hash = ?", newTestTokenHasher().Hash(token)).First(&resetModel).Error; err != nil {
 		t.Fatalf("failed to read password_reset after reset: %v", err)
 	}
 	if resetModel.UsedAt == nil {
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SessionService defines the application-layer contract the session handler depends on.
+type SessionService interface {
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]application.SessionDTO, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) error
+}
+
+// SessionHandler handles the per-device session management endpoints.
+type SessionHandler struct {
+	service SessionService
+	logger  *zap.Logger
+}
+
+// NewSessionHandler creates a new SessionHandler.
+func NewSessionHandler(service SessionService, logger *zap.Logger) *SessionHandler {
+	return &SessionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers session routes on the given router group.
+func (h *SessionHandler) RegisterRoutes(r *gin.RouterGroup, validator AccessTokenValidator) {
+	sessions := r.Group("/auth/sessions")
+	sessions.Use(authMiddleware(validator))
+	{
+		sessions.GET("", h.ListSessions)
+		sessions.DELETE("/:id", h.RevokeSession)
+		sessions.POST("/revoke-others", h.RevokeOtherSessions)
+	}
+}
+
+// ListSessions handles GET /auth/sessions.
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	currentID, _ := currentSessionID(c)
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), userID, currentID)
+	if err != nil {
+		h.logger.Error("list sessions failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession handles DELETE /auth/sessions/:id.
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid session ID")
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		h.logger.Warn("revoke session failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "session revoked"})
+}
+
+// RevokeOtherSessions handles POST /auth/sessions/revoke-others.
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.BadRequest(c, "user ID not found in context")
+		return
+	}
+	sessionID, ok := currentSessionID(c)
+	if !ok {
+		response.BadRequest(c, "session ID not found in token")
+		return
+	}
+
+	if err := h.service.RevokeOtherSessions(c.Request.Context(), userID, sessionID); err != nil {
+		h.logger.Warn("revoke other sessions failed", zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "other sessions revoked"})
+}
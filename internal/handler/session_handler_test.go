@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeSessionService struct {
+	sessions  []application.SessionDTO
+	revokeErr error
+	revokedID uuid.UUID
+	keptID    uuid.UUID
+}
+
+func (f *fakeSessionService) ListSessions(_ context.Context, _, _ uuid.UUID) ([]application.SessionDTO, error) {
+	return f.sessions, nil
+}
+
+func (f *fakeSessionService) RevokeSession(_ context.Context, _ uuid.UUID, sessionID uuid.UUID) error {
+	f.revokedID = sessionID
+	return f.revokeErr
+}
+
+func (f *fakeSessionService) RevokeOtherSessions(_ context.Context, _, currentSessionID uuid.UUID) error {
+	f.keptID = currentSessionID
+	return nil
+}
+
+func setupSessionRouter(t *testing.T, svc handler.SessionService) (*gin.Engine, string, uuid.UUID) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	r := gin.New()
+	apiV1 := r.Group("/api/v1")
+	h := handler.NewSessionHandler(svc, zap.NewNop())
+	h.RegisterRoutes(apiV1, tokenManager)
+
+	sessionID := uuid.New()
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "owner@kilat.my",
+		Role:      auth.RoleOwner,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	return r, token, sessionID
+}
+
+func TestListSessions_Authenticated_Returns200(t *testing.T) {
+	svc := &fakeSessionService{sessions: []application.SessionDTO{{ID: uuid.New(), DeviceName: "Pixel 8"}}}
+	r, token, _ := setupSessionRouter(t, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestListSessions_Unauthenticated_Returns401(t *testing.T) {
+	r, _, _ := setupSessionRouter(t, &fakeSessionService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", w.Code)
+	}
+}
+
+func TestRevokeSession_InvalidID_Returns400(t *testing.T) {
+	r, token, _ := setupSessionRouter(t, &fakeSessionService{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/auth/sessions/not-a-uuid", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid session ID, got %d", w.Code)
+	}
+}
+
+func TestRevokeSession_OtherUsersSession_Returns404(t *testing.T) {
+	sessionID := uuid.New()
+	svc := &fakeSessionService{revokeErr: domain.NewNotFoundError("Session", sessionID.String())}
+	r, token, _ := setupSessionRouter(t, svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/auth/sessions/"+sessionID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+	if svc.revokedID != sessionID {
+		t.Errorf("expected service to receive session %s, got %s", sessionID, svc.revokedID)
+	}
+}
+
+func TestRevokeOtherSessions_KeepsCurrentSession(t *testing.T) {
+	svc := &fakeSessionService{}
+	r, token, sessionID := setupSessionRouter(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sessions/revoke-others", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if svc.keptID != sessionID {
+		t.Errorf("expected current session %s to be kept, got %s", sessionID, svc.keptID)
+	}
+}
+
+// revokedSessions reports every token of the sessions it holds as revoked.
+type revokedSessions map[uuid.UUID]bool
+
+func (r revokedSessions) IsRevoked(claims *tokens.Claims) bool {
+	return r[claims.SessionID]
+}
+
+func TestListSessions_RevokedToken_Returns401(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokenManager := newTestTokenManager(t)
+	sessionID := uuid.New()
+	r := gin.New()
+	h := handler.NewSessionHandler(&fakeSessionService{}, zap.NewNop())
+	h.RegisterRoutes(r.Group("/api/v1"), handler.WithRevocationCheck(tokenManager, revokedSessions{sessionID: true}))
+
+	token, err := tokenManager.GenerateAccessToken(tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "owner@kilat.my",
+		Role:      auth.RoleOwner,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a revoked token, got %d — body: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/response"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SocialLoginService defines the application-layer contract the social sign-in handler depends on.
+type SocialLoginService interface {
+	LoginWithProvider(ctx context.Context, provider string, req application.SocialLoginRequest, client application.ClientInfo) (*application.AuthResponse, error)
+}
+
+// SocialLoginHandler handles sign-in with Google, Apple and other external identity providers.
+type SocialLoginHandler struct {
+	service SocialLoginService
+	logger  *zap.Logger
+}
+
+// NewSocialLoginHandler creates a new SocialLoginHandler.
+func NewSocialLoginHandler(service SocialLoginService, logger *zap.Logger) *SocialLoginHandler {
+	return &SocialLoginHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers social sign-in routes on the given router group.
+func (h *SocialLoginHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/auth/social/:provider", h.Login)
+}
+
+// Login handles POST /auth/social/:provider.
+func (h *SocialLoginHandler) Login(c *gin.Context) {
+	var req application.SocialLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	provider := c.Param("provider")
+	result, err := h.service.LoginWithProvider(c.Request.Context(), provider, req, clientInfo(c))
+	if err != nil {
+		h.logger.Warn("social login failed", zap.Error(err), zap.String("provider", provider))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,108 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/application"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/handler"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type fakeSocialLoginService struct {
+	loginErr error
+	provider string
+	req      application.SocialLoginRequest
+	client   application.ClientInfo
+}
+
+func (f *fakeSocialLoginService) LoginWithProvider(_ context.Context, provider string, req application.SocialLoginRequest, client application.ClientInfo) (*application.AuthResponse, error) {
+	f.provider = provider
+	f.req = req
+	f.client = client
+	if f.loginErr != nil {
+		return nil, f.loginErr
+	}
+	return &application.AuthResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func setupSocialLoginRouter(svc handler.SocialLoginService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler.NewSocialLoginHandler(svc, zap.NewNop()).RegisterRoutes(r.Group("/api/v1"))
+	return r
+}
+
+func TestSocialLogin_Valid_Returns200(t *testing.T) {
+	svc := &fakeSocialLoginService{}
+	r := setupSocialLoginRouter(svc)
+
+	body := bytes.NewBufferString(`{"id_token":"eyJhbGciOiJSUzI1NiJ9.e30.c2ln","nonce":"n-0S6_WzA2Mj","full_name":"Aida Owner"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/social/apple", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Device-Name", "Aida's iPhone")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"access_token":"access"`) {
+		t.Errorf("expected the token pair in the response, got %s", w.Body.String())
+	}
+	if svc.provider != "apple" || svc.req.Nonce != "n-0S6_WzA2Mj" || svc.req.FullName != "Aida Owner" {
+		t.Errorf("expected the request to reach the service, got provider %q and %+v", svc.provider, svc.req)
+	}
+	if svc.client.DeviceName != "Aida's iPhone" {
+		t.Errorf("expected device name to reach the service, got %q", svc.client.DeviceName)
+	}
+}
+
+func TestSocialLogin_MissingIDToken_Returns400(t *testing.T) {
+	r := setupSocialLoginRouter(&fakeSocialLoginService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/social/google", bytes.NewBufferString(`{"nonce":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestSocialLogin_Rejected_Returns401(t *testing.T) {
+	r := setupSocialLoginRouter(&fakeSocialLoginService{loginErr: domain.NewUnauthorizedError("sign-in with google failed")})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/social/google", bytes.NewBufferString(`{"id_token":"forged"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
+
+func TestSocialLogin_UnknownProvider_Returns404(t *testing.T) {
+	r := setupSocialLoginRouter(&fakeSocialLoginService{loginErr: domain.NewNotFoundError("IdentityProvider", "myspace")})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/social/myspace", bytes.NewBufferString(`{"id_token":"token"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
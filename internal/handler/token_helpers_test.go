@@ -0,0 +1,27 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+)
+
+// newTestTokenManager returns a token manager backed by a freshly generated Ed25519 key.
+func newTestTokenManager(t *testing.T) *tokens.Manager {
+	t.Helper()
+	key, err := tokens.GenerateSigningKey("test-key", tokens.AlgEdDSA)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	return tokens.NewManager(tokens.NewKeySet(key), "service-identity-test", 15*time.Minute, 7*24*time.Hour)
+}
+
+// staticMFAPolicy requires two-factor authentication from the roles it maps to true.
+type staticMFAPolicy map[auth.UserRole]bool
+
+func (p staticMFAPolicy) RequiresMFA(_ context.Context, role auth.UserRole) bool {
+	return p[role]
+}
@@ -0,0 +1,229 @@
+// Package passwords hashes and verifies user passwords with bcrypt or argon2id.
+// Hashes are self-describing strings: bcrypt's modular crypt format ("$2a$10$...")
+// and the PHC string format for argon2id ("$argon2id$v=19$m=...,t=...,p=...$salt$key").
+// Any supported hash verifies whichever algorithm is configured for new hashes, so
+// stored hashes of both kinds and of older parameters coexist and can be upgraded
+// one sign-in at a time.
+package passwords
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// AlgorithmBcrypt selects bcrypt for new hashes.
+	AlgorithmBcrypt = "bcrypt"
+	// AlgorithmArgon2id selects argon2id for new hashes.
+	AlgorithmArgon2id = "argon2id"
+)
+
+// ErrUnknownFormat is returned when a stored hash is not in any supported format.
+var ErrUnknownFormat = errors.New("unrecognized password hash format")
+
+// Argon2idParams are the cost parameters of argon2id hashes.
+type Argon2idParams struct {
+	// Memory is the memory cost in KiB.
+	Memory uint32
+	// Iterations is the number of passes over the memory.
+	Iterations uint32
+	// Parallelism is the number of lanes.
+	Parallelism uint8
+	// SaltLength is the length of the random salt in bytes.
+	SaltLength uint32
+	// KeyLength is the length of the derived key in bytes.
+	KeyLength uint32
+}
+
+// DefaultArgon2idParams are the OWASP recommended minimums: 19 MiB, 2 iterations, 1 lane.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// argon2idPrefix starts every argon2id hash in PHC string format.
+const argon2idPrefix = "$argon2id$"
+
+// BcryptHasher hashes new passwords with bcrypt at a fixed cost.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a BcryptHasher. A zero cost means bcrypt.DefaultCost.
+func NewBcryptHasher(cost int) (*BcryptHasher, error) {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost %d out of range [%d, %d]", cost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &BcryptHasher{cost: cost}, nil
+}
+
+// Hash returns the bcrypt hash of password.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether password matches a hash of any supported format.
+func (h *BcryptHasher) Verify(encoded, password string) (bool, error) {
+	return Verify(encoded, password)
+}
+
+// NeedsRehash reports whether encoded is not a bcrypt hash or has a lower cost than h.
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	if !isBcrypt(encoded) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.cost
+}
+
+// Argon2idHasher hashes new passwords with argon2id.
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher creates an Argon2idHasher. Zero fields take their value from
+// DefaultArgon2idParams.
+func NewArgon2idHasher(params Argon2idParams) (*Argon2idHasher, error) {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2idParams.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2idParams.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2idParams.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2idParams.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2idParams.KeyLength
+	}
+	if params.Memory < 8*uint32(params.Parallelism) {
+		return nil, fmt.Errorf("argon2id memory must be at least 8 KiB per lane, got %d KiB for %d lanes", params.Memory, params.Parallelism)
+	}
+	if params.SaltLength < 8 || params.KeyLength < 16 {
+		return nil, fmt.Errorf("argon2id salt must be at least 8 bytes and key at least 16 bytes")
+	}
+	return &Argon2idHasher{params: params}, nil
+}
+
+// Hash returns the argon2id hash of password in PHC string format.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	return encodeArgon2id(h.params, salt, key), nil
+}
+
+// Verify reports whether password matches a hash of any supported format.
+func (h *Argon2idHasher) Verify(encoded, password string) (bool, error) {
+	return Verify(encoded, password)
+}
+
+// NeedsRehash reports whether encoded is not an argon2id hash or was made with
+// cheaper parameters than h uses.
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	if !strings.HasPrefix(encoded, argon2idPrefix) {
+		return true
+	}
+	params, _, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory < h.params.Memory ||
+		params.Iterations < h.params.Iterations ||
+		uint32(len(key)) < h.params.KeyLength
+}
+
+// Verify reports whether password matches encoded, which may be a bcrypt or argon2id
+// hash. A mismatch is not an error; a hash in no supported format is ErrUnknownFormat.
+func Verify(encoded, password string) (bool, error) {
+	switch {
+	case isBcrypt(encoded):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(candidate, key) == 1, nil
+	default:
+		return false, ErrUnknownFormat
+	}
+}
+
+// isBcrypt reports whether encoded carries one of bcrypt's version prefixes.
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func encodeArgon2id(params Argon2idParams, salt, key []byte) string {
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2id(encoded string) (Argon2idParams, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return Argon2idParams{}, nil, nil, ErrUnknownFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("unsupported argon2id version %q", parts[2])
+	}
+
+	var params Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	if params.Iterations == 0 || params.Parallelism == 0 {
+		return Argon2idParams{}, nil, nil, errors.New("invalid argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2idParams{}, nil, nil, errors.New("invalid argon2id key")
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,93 @@
+package passwords_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/passwords"
+)
+
+// cheapArgon2id keeps the tests fast; production parameters are far higher.
+var cheapArgon2id = passwords.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestArgon2id_HashVerifiesAndIsSelfDescribing(t *testing.T) {
+	hasher, err := passwords.NewArgon2idHasher(cheapArgon2id)
+	if err != nil {
+		t.Fatalf("NewArgon2idHasher failed: %v", err)
+	}
+
+	hash, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("expected PHC string with parameters, got %q", hash)
+	}
+
+	if ok, err := hasher.Verify(hash, "correct horse"); err != nil || !ok {
+		t.Errorf("expected the password to verify, got %v, %v", ok, err)
+	}
+	if ok, err := hasher.Verify(hash, "wrong horse"); err != nil || ok {
+		t.Errorf("expected a mismatch without error, got %v, %v", ok, err)
+	}
+	if hasher.NeedsRehash(hash) {
+		t.Error("a hash made with the current parameters must not need a rehash")
+	}
+}
+
+func TestVerify_MixedHashesCoexist(t *testing.T) {
+	bcryptHasher, err := passwords.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher failed: %v", err)
+	}
+	argonHasher, err := passwords.NewArgon2idHasher(cheapArgon2id)
+	if err != nil {
+		t.Fatalf("NewArgon2idHasher failed: %v", err)
+	}
+
+	bcryptHash, err := bcryptHasher.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+
+	// A service switched to argon2id still verifies existing bcrypt hashes...
+	if ok, err := argonHasher.Verify(bcryptHash, "s3cret-pass"); err != nil || !ok {
+		t.Errorf("expected bcrypt hash to verify under argon2id hasher, got %v, %v", ok, err)
+	}
+	// ...and flags them for upgrade.
+	if !argonHasher.NeedsRehash(bcryptHash) {
+		t.Error("expected bcrypt hash to need a rehash under argon2id hasher")
+	}
+}
+
+func TestNeedsRehash_WeakerParameters(t *testing.T) {
+	weakBcrypt, _ := passwords.NewBcryptHasher(4)
+	strongBcrypt, _ := passwords.NewBcryptHasher(5)
+	hash, err := weakBcrypt.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !strongBcrypt.NeedsRehash(hash) {
+		t.Error("expected lower bcrypt cost to need a rehash")
+	}
+	if weakBcrypt.NeedsRehash(hash) {
+		t.Error("expected equal bcrypt cost not to need a rehash")
+	}
+
+	weakArgon, _ := passwords.NewArgon2idHasher(cheapArgon2id)
+	strongArgon, _ := passwords.NewArgon2idHasher(passwords.Argon2idParams{Memory: 128, Iterations: 1, Parallelism: 1})
+	argonHash, err := weakArgon.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if !strongArgon.NeedsRehash(argonHash) {
+		t.Error("expected lower argon2id memory to need a rehash")
+	}
+}
+
+func TestVerify_UnknownFormat(t *testing.T) {
+	if _, err := passwords.Verify("plaintext", "plaintext"); !errors.Is(err, passwords.ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
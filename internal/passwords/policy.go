@@ -0,0 +1,174 @@
+package passwords
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMinLength is the minimum password length when none is configured.
+	DefaultMinLength = 8
+	// MaxBytes is the longest password accepted. bcrypt ignores everything past 72
+	// bytes, so the limit keeps the two algorithms interchangeable.
+	MaxBytes = 72
+	// minPersonalPart is the shortest email or name fragment a password may not contain;
+	// shorter fragments would reject too many unrelated passwords.
+	minPersonalPart = 3
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// PolicyError lists every rule a password broke.
+type PolicyError struct {
+	Violations []string
+}
+
+// Error joins the violations into one message.
+func (e *PolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, "; ")
+}
+
+// Policy is the single set of rules every new password is checked against.
+type Policy struct {
+	minLength int
+	common    map[string]bool
+	breached  *BreachedList
+}
+
+// NewPolicy creates a Policy. A zero minLength means DefaultMinLength; a nil breached
+// list skips the breached-password check.
+func NewPolicy(minLength int, breached *BreachedList) (*Policy, error) {
+	if minLength == 0 {
+		minLength = DefaultMinLength
+	}
+	if minLength < 1 || minLength > MaxBytes {
+		return nil, fmt.Errorf("minimum password length %d out of range [1, %d]", minLength, MaxBytes)
+	}
+
+	common := make(map[string]bool)
+	for _, line := range strings.Split(commonPasswordList, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			common[line] = true
+		}
+	}
+	return &Policy{minLength: minLength, common: common, breached: breached}, nil
+}
+
+// Check returns a *PolicyError listing every rule password breaks, or nil. personal
+// holds facts about the account, such as its email and full name, that the password
+// must not contain.
+func (p *Policy) Check(password string, personal ...string) error {
+	var violations []string
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", p.minLength))
+	}
+	if len(password) > MaxBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes", MaxBytes))
+	}
+
+	lower := strings.ToLower(password)
+	if p.common[lower] {
+		violations = append(violations, "is too common")
+	}
+	for _, part := range personalParts(personal) {
+		if strings.Contains(lower, part) {
+			violations = append(violations, "must not contain your email address or name")
+			break
+		}
+	}
+	if p.breached != nil && p.breached.Contains(password) {
+		violations = append(violations, "has appeared in a data breach")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// personalParts splits emails into their local part and domain name and names into
+// words, lowercased, keeping only fragments long enough to be meaningful.
+func personalParts(personal []string) []string {
+	var parts []string
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if local, domain, ok := strings.Cut(value, "@"); ok {
+			value = local + " " + strings.SplitN(domain, ".", 2)[0]
+		}
+		for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if utf8.RuneCountInString(part) >= minPersonalPart {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// BreachedList is an offline set of passwords known from data breaches, stored as
+// prefixes of their uppercase hex SHA-1 digests.
+type BreachedList struct {
+	prefixLen int
+	prefixes  map[string]struct{}
+}
+
+// LoadBreachedList reads a breached-password file: one uppercase or lowercase hex
+// SHA-1 prefix per line, all of the same length (at least 10 characters), optionally
+// followed by ":count" as in the Have I Been Pwned downloads. Blank lines and lines
+// starting with # are skipped.
+func LoadBreachedList(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	list := &BreachedList{prefixes: make(map[string]struct{})}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prefix, _, _ := strings.Cut(line, ":")
+		prefix = strings.ToUpper(prefix)
+		if len(prefix) < 10 || len(prefix) > sha1.Size*2 || strings.Trim(prefix, "0123456789ABCDEF") != "" {
+			return nil, fmt.Errorf("breached password list line %d: not a SHA-1 prefix of 10 to 40 hex characters", lineNo)
+		}
+		if list.prefixLen == 0 {
+			list.prefixLen = len(prefix)
+		} else if len(prefix) != list.prefixLen {
+			return nil, fmt.Errorf("breached password list line %d: prefix length %d differs from %d", lineNo, len(prefix), list.prefixLen)
+		}
+		list.prefixes[prefix] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+	return list, nil
+}
+
+// Len returns the number of entries in the list.
+func (l *BreachedList) Len() int {
+	return len(l.prefixes)
+}
+
+// Contains reports whether password's SHA-1 digest starts with a listed prefix.
+func (l *BreachedList) Contains(password string) bool {
+	if l.prefixLen == 0 {
+		return false
+	}
+	sum := sha1.Sum([]byte(password))
+	_, ok := l.prefixes[strings.ToUpper(hex.EncodeToString(sum[:]))[:l.prefixLen]]
+	return ok
+}
@@ -0,0 +1,78 @@
+package passwords_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/passwords"
+)
+
+func TestPolicy_ListsEveryViolation(t *testing.T) {
+	policy, err := passwords.NewPolicy(10, nil)
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+
+	err = policy.Check("aida1234", "aida@kilat.my", "Aida Rahman")
+	var policyErr *passwords.PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected a PolicyError, got %v", err)
+	}
+	if len(policyErr.Violations) != 2 {
+		t.Fatalf("expected length and personal-info violations, got %v", policyErr.Violations)
+	}
+	if !strings.Contains(err.Error(), "at least 10 characters") || !strings.Contains(err.Error(), "email address or name") {
+		t.Errorf("expected message to list both rules, got %q", err.Error())
+	}
+}
+
+func TestPolicy_RejectsCommonPasswordsCaseInsensitively(t *testing.T) {
+	policy, _ := passwords.NewPolicy(0, nil)
+
+	if err := policy.Check("Password123"); err == nil || !strings.Contains(err.Error(), "too common") {
+		t.Errorf("expected a common password to be rejected, got %v", err)
+	}
+	if err := policy.Check("tabby-cat-on-a-mat"); err != nil {
+		t.Errorf("expected an uncommon password to pass, got %v", err)
+	}
+}
+
+func TestPolicy_RejectsBreachedPasswords(t *testing.T) {
+	sum := sha1.Sum([]byte("tabby-cat-on-a-mat"))
+	prefix := strings.ToUpper(hex.EncodeToString(sum[:]))[:20]
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte("# sample\n"+prefix+":42\n0123456789ABCDEF0123\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	list, err := passwords.LoadBreachedList(path)
+	if err != nil {
+		t.Fatalf("LoadBreachedList failed: %v", err)
+	}
+	if list.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", list.Len())
+	}
+
+	policy, _ := passwords.NewPolicy(0, list)
+	if err := policy.Check("tabby-cat-on-a-mat"); err == nil || !strings.Contains(err.Error(), "data breach") {
+		t.Errorf("expected a breached password to be rejected, got %v", err)
+	}
+	if err := policy.Check("calico-cat-on-a-mat"); err != nil {
+		t.Errorf("expected an unlisted password to pass, got %v", err)
+	}
+}
+
+func TestLoadBreachedList_RejectsMixedPrefixLengths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte("0123456789AB\n0123456789ABCD\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := passwords.LoadBreachedList(path); err == nil {
+		t.Error("expected an error for mixed prefix lengths")
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// AuthorizationCodeModel is the GORM model for the oauth_authorization_codes table.
+type AuthorizationCodeModel struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CodeHash      string         `gorm:"type:char(64);uniqueIndex;not null"`
+	ClientID      string         `gorm:"type:varchar(64);not null;index"`
+	UserID        uuid.UUID      `gorm:"type:uuid;not null;index"`
+	RedirectURI   string         `gorm:"type:text;not null"`
+	Scopes        pq.StringArray `gorm:"type:text[];not null;default:'{}'"`
+	Nonce         string         `gorm:"type:varchar(255);not null;default:''"`
+	CodeChallenge string         `gorm:"type:varchar(128);not null"`
+	AuthMethods   pq.StringArray `gorm:"type:text[];not null;default:'{}'"`
+	AuthTime      time.Time      `gorm:"not null"`
+	SessionID     *uuid.UUID     `gorm:"type:uuid"`
+	ExpiresAt     time.Time      `gorm:"not null"`
+	ConsumedAt    *time.Time     `gorm:""`
+	CreatedAt     time.Time      `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (AuthorizationCodeModel) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+// toDomain converts an AuthorizationCodeModel to a domain AuthorizationCode.
+func (m *AuthorizationCodeModel) toDomain() *identity.AuthorizationCode {
+	return identity.ReconstructAuthorizationCode(
+		m.ID,
+		m.ClientID,
+		m.UserID,
+		m.RedirectURI,
+		[]string(m.Scopes),
+		m.Nonce,
+		m.CodeChallenge,
+		[]string(m.AuthMethods),
+		m.AuthTime,
+		m.SessionID,
+		m.ExpiresAt,
+		m.ConsumedAt,
+		m.CreatedAt,
+	)
+}
+
+// GormAuthorizationCodeRepository is a GORM-based implementation of AuthorizationCodeRepository.
+// Codes are stored and looked up by their keyed hash only.
+type GormAuthorizationCodeRepository struct {
+	db     *gorm.DB
+	hasher *TokenHasher
+}
+
+// NewGormAuthorizationCodeRepository creates a new GormAuthorizationCodeRepository.
+func NewGormAuthorizationCodeRepository(db *gorm.DB, hasher *TokenHasher) *GormAuthorizationCodeRepository {
+	return &GormAuthorizationCodeRepository{db: db, hasher: hasher}
+}
+
+// Create persists a new authorization code with the hash of its raw code.
+func (r *GormAuthorizationCodeRepository) Create(ctx context.Context, code *identity.AuthorizationCode) error {
+	return r.db.WithContext(ctx).Create(&AuthorizationCodeModel{
+		ID:            code.ID(),
+		CodeHash:      r.hasher.Hash(code.Code()),
+		ClientID:      code.ClientID(),
+		UserID:        code.UserID(),
+		RedirectURI:   code.RedirectURI(),
+		Scopes:        pq.StringArray(code.Scopes()),
+		Nonce:         code.Nonce(),
+		CodeChallenge: code.CodeChallenge(),
+		AuthMethods:   pq.StringArray(code.AuthMethods()),
+		AuthTime:      code.AuthTime(),
+		SessionID:     code.SessionID(),
+		ExpiresAt:     code.ExpiresAt(),
+		ConsumedAt:    code.ConsumedAt(),
+		CreatedAt:     code.CreatedAt(),
+	}).Error
+}
+
+// FindByCode retrieves an authorization code by the hash of its raw code, whatever its state.
+func (r *GormAuthorizationCodeRepository) FindByCode(ctx context.Context, code string) (*identity.AuthorizationCode, error) {
+	var model AuthorizationCodeModel
+	err := r.db.WithContext(ctx).Where("code_hash = ?", r.hasher.Hash(code)).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// Consume marks a code redeemed. The guard makes redemption single-use under concurrency.
+func (r *GormAuthorizationCodeRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&AuthorizationCodeModel{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// AttachSession records the session started with a redeemed code.
+func (r *GormAuthorizationCodeRepository) AttachSession(ctx context.Context, id, sessionID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&AuthorizationCodeModel{}).
+		Where("id = ?", id).
+		Update("session_id", sessionID).
+		Error
+}
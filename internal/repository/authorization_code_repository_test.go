@@ -0,0 +1,72 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestAuthorizationCodeRepo_ConsumeIsSingleUse(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE oauth_authorization_codes").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	userID := seedTestUser(t, db)
+	hasher := repository.NewTokenHasher("test-pepper")
+	ctx := context.Background()
+
+	clientID := "svc_" + uuid.NewString()[:8]
+	client, err := identity.NewOAuthClient(clientID, "dashboard", "client-secret", []string{"openid"}, []string{"https://admin.kilat.my/callback"}, userID)
+	if err != nil {
+		t.Fatalf("NewOAuthClient failed: %v", err)
+	}
+	if err := repository.NewGormOAuthClientRepository(db, hasher).Create(ctx, client); err != nil {
+		t.Fatalf("Create client failed: %v", err)
+	}
+
+	repo := repository.NewGormAuthorizationCodeRepository(db, hasher)
+	now := time.Now().UTC()
+	code := identity.NewAuthorizationCode("code-"+uuid.NewString(), clientID, userID, "https://admin.kilat.my/callback",
+		[]string{"openid"}, "nonce", "challenge", []string{"pwd"}, now, now.Add(time.Minute))
+	if err := repo.Create(ctx, code); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	found, err := repo.FindByCode(ctx, code.Code())
+	if err != nil {
+		t.Fatalf("FindByCode failed: %v", err)
+	}
+	if found.ID() != code.ID() || found.RedirectURI() != "https://admin.kilat.my/callback" {
+		t.Errorf("unexpected code loaded: %+v", found)
+	}
+
+	if err := repo.Consume(ctx, code.ID()); err != nil {
+		t.Fatalf("first Consume failed: %v", err)
+	}
+	if err := repo.Consume(ctx, code.ID()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second Consume, got %v", err)
+	}
+
+	sessionID := uuid.New()
+	if err := repo.AttachSession(ctx, code.ID(), sessionID); err != nil {
+		t.Fatalf("AttachSession failed: %v", err)
+	}
+	found, err = repo.FindByCode(ctx, code.Code())
+	if err != nil {
+		t.Fatalf("FindByCode after consume failed: %v", err)
+	}
+	if !found.IsConsumed() {
+		t.Error("expected the code to be consumed")
+	}
+	if found.SessionID() == nil || *found.SessionID() != sessionID {
+		t.Errorf("expected session %s, got %v", sessionID, found.SessionID())
+	}
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailChangeModel is the GORM model for the email_changes table.
+type EmailChangeModel struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index"`
+	OldEmail         string     `gorm:"type:varchar(255);not null"`
+	NewEmail         string     `gorm:"type:varchar(255);not null"`
+	ConfirmTokenHash string     `gorm:"type:char(64);uniqueIndex;not null"`
+	CancelTokenHash  string     `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt        time.Time  `gorm:"not null"`
+	ConfirmedAt      *time.Time `gorm:""`
+	CancelledAt      *time.Time `gorm:""`
+	CreatedAt        time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (EmailChangeModel) TableName() string {
+	return "email_changes"
+}
+
+// toDomain converts an EmailChangeModel to a domain EmailChange. Only token hashes are
+// stored, so the caller supplies whichever raw token it looked the change up by.
+func (m *EmailChangeModel) toDomain(confirmToken, cancelToken string) *identity.EmailChange {
+	return identity.ReconstructEmailChange(
+		m.ID,
+		m.UserID,
+		m.OldEmail,
+		m.NewEmail,
+		confirmToken,
+		cancelToken,
+		m.ExpiresAt,
+		m.ConfirmedAt,
+		m.CancelledAt,
+		m.CreatedAt,
+	)
+}
+
+// GormEmailChangeRepository is a GORM-based implementation of EmailChangeRepository.
+// Tokens are stored and looked up by their keyed hash only.
+type GormEmailChangeRepository struct {
+	db     *gorm.DB
+	hasher *TokenHasher
+}
+
+// NewGormEmailChangeRepository creates a new GormEmailChangeRepository.
+func NewGormEmailChangeRepository(db *gorm.DB, hasher *TokenHasher) *GormEmailChangeRepository {
+	return &GormEmailChangeRepository{db: db, hasher: hasher}
+}
+
+// Create cancels the user's pending changes and stores the new one in a single transaction.
+func (r *GormEmailChangeRepository) Create(ctx context.Context, change *identity.EmailChange) error {
+	model := &EmailChangeModel{
+		ID:               change.ID(),
+		UserID:           change.UserID(),
+		OldEmail:         change.OldEmail(),
+		NewEmail:         change.NewEmail(),
+		ConfirmTokenHash: r.hasher.Hash(change.ConfirmToken()),
+		CancelTokenHash:  r.hasher.Hash(change.CancelToken()),
+		ExpiresAt:        change.ExpiresAt(),
+		CreatedAt:        change.CreatedAt(),
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&EmailChangeModel{}).
+			Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", change.UserID()).
+			Update("cancelled_at", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+}
+
+// FindByConfirmToken retrieves a change by the token sent to its new address, whatever its state.
+// Returns domain.ErrNotFound if no change has that token.
+func (r *GormEmailChangeRepository) FindByConfirmToken(ctx context.Context, token string) (*identity.EmailChange, error) {
+	model, err := r.findBy(ctx, "confirm_token_hash", token)
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain(token, ""), nil
+}
+
+// FindByCancelToken retrieves a change by the token sent to its old address, whatever its state.
+// Returns domain.ErrNotFound if no change has that token.
+func (r *GormEmailChangeRepository) FindByCancelToken(ctx context.Context, token string) (*identity.EmailChange, error) {
+	model, err := r.findBy(ctx, "cancel_token_hash", token)
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain("", token), nil
+}
+
+func (r *GormEmailChangeRepository) findBy(ctx context.Context, column, token string) (*EmailChangeModel, error) {
+	var model EmailChangeModel
+	err := r.db.WithContext(ctx).
+		Where(column+" = ?", r.hasher.Hash(token)).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &model, nil
+}
+
+// Confirm marks the change confirmed and swaps the user's address in a single transaction.
+// The pending guard makes the confirm token single-use, the old-address guard stops a stale
+// change from undoing a later one, and the unique index on users.email settles a race
+// with another account claiming the same address.
+func (r *GormEmailChangeRepository) Confirm(ctx context.Context, change *identity.EmailChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		result := tx.Model(&EmailChangeModel{}).
+			Where("id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL AND expires_at > ?", change.ID(), now).
+			Update("confirmed_at", change.ConfirmedAt())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return identity.ErrEmailChangeNotPending
+		}
+
+		result = tx.Model(&UserModel{}).
+			Where("id = ? AND email = ?", change.UserID(), change.OldEmail()).
+			Updates(map[string]interface{}{
+				"email":           change.NewEmail(),
+				"canonical_email": change.NewEmailVO().Canonical(),
+				"is_verified":     true,
+				"version":         gorm.Expr("version + 1"),
+				"updated_at":      now,
+			})
+		if result.Error != nil {
+			if isEmailDuplicateError(result.Error) {
+				return domain.NewAlreadyExistsError("User", "email", change.NewEmail())
+			}
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return identity.ErrEmailChangeNotPending
+		}
+		return nil
+	})
+}
+
+// Cancel marks a pending change cancelled.
+func (r *GormEmailChangeRepository) Cancel(ctx context.Context, change *identity.EmailChange) error {
+	result := r.db.WithContext(ctx).
+		Model(&EmailChangeModel{}).
+		Where("id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", change.ID()).
+		Update("cancelled_at", change.CancelledAt())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return identity.ErrEmailChangeNotPending
+	}
+	return nil
+}
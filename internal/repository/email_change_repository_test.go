@@ -0,0 +1,92 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestEmailChangeRepo_ConfirmSwapsAddressOnce(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormEmailChangeRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	var oldEmail string
+	if err := db.Model(&repository.UserModel{}).Where("id = ?", userID).Pluck("email", &oldEmail).Error; err != nil {
+		t.Fatalf("load email failed: %v", err)
+	}
+	newEmail := "changed-" + uuid.New().String() + "@kilat.my"
+
+	change, err := identity.NewEmailChange(userID, oldEmail, newEmail, "confirm-"+uuid.New().String(), "cancel-"+uuid.New().String(), time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewEmailChange failed: %v", err)
+	}
+	if err := repo.Create(ctx, change); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	found, err := repo.FindByConfirmToken(ctx, change.ConfirmToken())
+	if err != nil {
+		t.Fatalf("FindByConfirmToken failed: %v", err)
+	}
+	if err := found.Confirm(); err != nil {
+		t.Fatalf("Confirm (domain) failed: %v", err)
+	}
+	if err := repo.Confirm(ctx, found); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	if err := repo.Confirm(ctx, found); !errors.Is(err, identity.ErrEmailChangeNotPending) {
+		t.Errorf("expected ErrEmailChangeNotPending on second confirm, got %v", err)
+	}
+
+	var user repository.UserModel
+	if err := db.First(&user, "id = ?", userID).Error; err != nil {
+		t.Fatalf("load user failed: %v", err)
+	}
+	if user.Email != newEmail || !user.IsVerified {
+		t.Errorf("expected verified %s, got %s (verified %v)", newEmail, user.Email, user.IsVerified)
+	}
+}
+
+func TestEmailChangeRepo_ConfirmRefusesTakenAddress(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	otherID := seedTestUser(t, db)
+
+	repo := repository.NewGormEmailChangeRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	var oldEmail, takenEmail string
+	db.Model(&repository.UserModel{}).Where("id = ?", userID).Pluck("email", &oldEmail)
+	db.Model(&repository.UserModel{}).Where("id = ?", otherID).Pluck("email", &takenEmail)
+
+	change, err := identity.NewEmailChange(userID, oldEmail, takenEmail, "confirm-"+uuid.New().String(), "cancel-"+uuid.New().String(), time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewEmailChange failed: %v", err)
+	}
+	if err := repo.Create(ctx, change); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := change.Confirm(); err != nil {
+		t.Fatalf("Confirm (domain) failed: %v", err)
+	}
+
+	if err := repo.Confirm(ctx, change); err == nil {
+		t.Fatal("expected confirming a taken address to fail")
+	}
+
+	var email string
+	db.Model(&repository.UserModel{}).Where("id = ?", userID).Pluck("email", &email)
+	if email != oldEmail {
+		t.Errorf("expected the address to stay %s, got %s", oldEmail, email)
+	}
+}
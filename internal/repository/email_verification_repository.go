@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailVerificationModel is the GORM model for the email_verifications table.
+type EmailVerificationModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Email     string     `gorm:"type:varchar(255);not null"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:""`
+	CreatedAt time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (EmailVerificationModel) TableName() string {
+	return "email_verifications"
+}
+
+// toDomain converts an EmailVerificationModel to a domain EmailVerification.
+// Only the token hash is stored, so the caller supplies the raw token it looked up by.
+func (m *EmailVerificationModel) toDomain(token string) *identity.EmailVerification {
+	return identity.ReconstructEmailVerification(
+		m.ID,
+		m.UserID,
+		m.Email,
+		token,
+		m.ExpiresAt,
+		m.UsedAt,
+		m.CreatedAt,
+	)
+}
+
+// fromDomainEmailVerification converts a domain EmailVerification to an EmailVerificationModel.
+func fromDomainEmailVerification(v *identity.EmailVerification, tokenHash string) *EmailVerificationModel {
+	return &EmailVerificationModel{
+		ID:        v.ID(),
+		UserID:    v.UserID(),
+		Email:     v.Email(),
+		TokenHash: tokenHash,
+		ExpiresAt: v.ExpiresAt(),
+		UsedAt:    v.UsedAt(),
+		CreatedAt: v.CreatedAt(),
+	}
+}
+
+// GormEmailVerificationRepository is a GORM-based implementation of EmailVerificationRepository.
+// Tokens are stored and looked up by their keyed hash only.
+type GormEmailVerificationRepository struct {
+	db     *gorm.DB
+	hasher *TokenHasher
+}
+
+// NewGormEmailVerificationRepository creates a new GormEmailVerificationRepository.
+func NewGormEmailVerificationRepository(db *gorm.DB, hasher *TokenHasher) *GormEmailVerificationRepository {
+	return &GormEmailVerificationRepository{db: db, hasher: hasher}
+}
+
+// Create persists a new email verification token.
+func (r *GormEmailVerificationRepository) Create(ctx context.Context, verification *identity.EmailVerification) error {
+	model := fromDomainEmailVerification(verification, r.hasher.Hash(verification.Token()))
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+// FindAnyByToken retrieves a verification token by its token string without filtering by expiry or use.
+// Returns domain.ErrNotFound if the token does not exist at all.
+func (r *GormEmailVerificationRepository) FindAnyByToken(ctx context.Context, token string) (*identity.EmailVerification, error) {
+	var model EmailVerificationModel
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ?", r.hasher.Hash(token)).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(token), nil
+}
+
+// CountCreatedSince counts the verification tokens issued to a user since the given time.
+func (r *GormEmailVerificationRepository) CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&EmailVerificationModel{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkUsedAndVerifyUser consumes the token, invalidates the user's other unused tokens and
+// marks the user verified in a single transaction. The used_at guard makes the token single-use.
+func (r *GormEmailVerificationRepository) MarkUsedAndVerifyUser(ctx context.Context, tokenID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		result := tx.Model(&EmailVerificationModel{}).
+			Where("id = ? AND used_at IS NULL", tokenID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+
+		if err := tx.Model(&EmailVerificationModel{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		result = tx.Model(&UserModel{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"is_verified": true,
+				"updated_at":  now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return nil
+	})
+}
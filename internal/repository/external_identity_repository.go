@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ExternalIdentityModel is the GORM model for the user_identities table.
+type ExternalIdentityModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:uq_user_identities_user_provider"`
+	Provider   string     `gorm:"type:varchar(32);not null;uniqueIndex:uq_user_identities_provider_subject;uniqueIndex:uq_user_identities_user_provider"`
+	Subject    string     `gorm:"type:varchar(255);not null;uniqueIndex:uq_user_identities_provider_subject"`
+	Email      string     `gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()"`
+	LastUsedAt *time.Time `gorm:""`
+}
+
+// TableName specifies the table name for GORM.
+func (ExternalIdentityModel) TableName() string {
+	return "user_identities"
+}
+
+// toDomain converts an ExternalIdentityModel to a domain ExternalIdentity.
+func (m *ExternalIdentityModel) toDomain() *identity.ExternalIdentity {
+	return identity.ReconstructExternalIdentity(
+		m.ID,
+		m.UserID,
+		m.Provider,
+		m.Subject,
+		m.Email,
+		m.CreatedAt,
+		m.LastUsedAt,
+	)
+}
+
+// GormExternalIdentityRepository is a GORM-based implementation of ExternalIdentityRepository.
+type GormExternalIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewGormExternalIdentityRepository creates a new GormExternalIdentityRepository.
+func NewGormExternalIdentityRepository(db *gorm.DB) *GormExternalIdentityRepository {
+	return &GormExternalIdentityRepository{db: db}
+}
+
+// Create links a provider account to a user.
+func (r *GormExternalIdentityRepository) Create(ctx context.Context, link *identity.ExternalIdentity) error {
+	err := r.db.WithContext(ctx).Create(&ExternalIdentityModel{
+		ID:         link.ID(),
+		UserID:     link.UserID(),
+		Provider:   link.Provider(),
+		Subject:    link.Subject(),
+		Email:      link.Email(),
+		CreatedAt:  link.CreatedAt(),
+		LastUsedAt: link.LastUsedAt(),
+	}).Error
+	if isExternalIdentityDuplicateError(err) {
+		return identity.ErrExternalIdentityAlreadyLinked
+	}
+	return err
+}
+
+// FindByProviderSubject retrieves the link of a provider account.
+func (r *GormExternalIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*identity.ExternalIdentity, error) {
+	var model ExternalIdentityModel
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// RecordUse persists the link's last use and the email the provider reported.
+func (r *GormExternalIdentityRepository) RecordUse(ctx context.Context, link *identity.ExternalIdentity) error {
+	return r.db.WithContext(ctx).
+		Model(&ExternalIdentityModel{}).
+		Where("id = ?", link.ID()).
+		Updates(map[string]interface{}{
+			"email":        link.Email(),
+			"last_used_at": link.LastUsedAt(),
+		}).
+		Error
+}
+
+// isExternalIdentityDuplicateError reports whether err violates either unique
+// constraint of user_identities.
+func isExternalIdentityDuplicateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pqErr *pq.Error
+	if ok := isPqError(err, &pqErr); ok {
+		return pqErr.Code == "23505" && strings.Contains(pqErr.Constraint, "uq_user_identities")
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "duplicate key") && strings.Contains(msg, "uq_user_identities")
+}
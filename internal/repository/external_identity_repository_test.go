@@ -0,0 +1,83 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestExternalIdentityRepo_CreateFindAndRecordUse(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE user_identities").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	userID := seedTestUser(t, db)
+	repo := repository.NewGormExternalIdentityRepository(db)
+	ctx := context.Background()
+
+	subject := "google-" + uuid.NewString()
+	link := identity.NewExternalIdentity(userID, "google", subject, "owner@gmail.com")
+	if err := repo.Create(ctx, link); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	found, err := repo.FindByProviderSubject(ctx, "google", subject)
+	if err != nil {
+		t.Fatalf("FindByProviderSubject failed: %v", err)
+	}
+	if found.UserID() != userID || found.LastUsedAt() != nil {
+		t.Errorf("unexpected link loaded: %+v", found)
+	}
+	if _, err := repo.FindByProviderSubject(ctx, "apple", subject); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for another provider, got %v", err)
+	}
+
+	found.RecordUse("owner@googlemail.com")
+	if err := repo.RecordUse(ctx, found); err != nil {
+		t.Fatalf("RecordUse failed: %v", err)
+	}
+	found, err = repo.FindByProviderSubject(ctx, "google", subject)
+	if err != nil {
+		t.Fatalf("FindByProviderSubject after use failed: %v", err)
+	}
+	if found.LastUsedAt() == nil || found.Email() != "owner@googlemail.com" {
+		t.Errorf("expected the use to be recorded, got %+v", found)
+	}
+}
+
+func TestExternalIdentityRepo_CreateRejectsDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE user_identities").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	userID := seedTestUser(t, db)
+	otherUserID := seedTestUser(t, db)
+	repo := repository.NewGormExternalIdentityRepository(db)
+	ctx := context.Background()
+
+	subject := "google-" + uuid.NewString()
+	if err := repo.Create(ctx, identity.NewExternalIdentity(userID, "google", subject, "")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// The same provider account cannot be linked to a second user...
+	err := repo.Create(ctx, identity.NewExternalIdentity(otherUserID, "google", subject, ""))
+	if !errors.Is(err, identity.ErrExternalIdentityAlreadyLinked) {
+		t.Errorf("expected ErrExternalIdentityAlreadyLinked for a linked subject, got %v", err)
+	}
+	// ...and a user links at most one account per provider.
+	err = repo.Create(ctx, identity.NewExternalIdentity(userID, "google", "google-"+uuid.NewString(), ""))
+	if !errors.Is(err, identity.ErrExternalIdentityAlreadyLinked) {
+		t.Errorf("expected ErrExternalIdentityAlreadyLinked for a second account, got %v", err)
+	}
+	if err := repo.Create(ctx, identity.NewExternalIdentity(userID, "apple", subject, "")); err != nil {
+		t.Errorf("expected another provider to link, got %v", err)
+	}
+}
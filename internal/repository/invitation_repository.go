@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationModel is the GORM model for the invitations table.
+type InvitationModel struct {
+	ID             uuid.UUID     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Email          string        `gorm:"type:varchar(255);not null;index"`
+	Role           auth.UserRole `gorm:"type:varchar(20);not null"`
+	TokenHash      string        `gorm:"type:char(64);uniqueIndex;not null"`
+	InvitedBy      uuid.UUID     `gorm:"type:uuid;not null"`
+	ExpiresAt      time.Time     `gorm:"not null"`
+	AcceptedAt     *time.Time
+	AcceptedUserID *uuid.UUID `gorm:"type:uuid"`
+	RevokedAt      *time.Time
+	CreatedAt      time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (InvitationModel) TableName() string {
+	return "invitations"
+}
+
+// toDomain converts an InvitationModel to a domain Invitation.
+// Only the token hash is stored, so the caller supplies the raw token if it has one.
+func (m *InvitationModel) toDomain(token string) *identity.Invitation {
+	return identity.ReconstructInvitation(
+		m.ID,
+		m.Email,
+		m.Role,
+		token,
+		m.InvitedBy,
+		m.ExpiresAt,
+		m.AcceptedAt,
+		m.AcceptedUserID,
+		m.RevokedAt,
+		m.CreatedAt,
+	)
+}
+
+// fromDomainInvitation converts a domain Invitation to an InvitationModel.
+func fromDomainInvitation(i *identity.Invitation, tokenHash string) *InvitationModel {
+	return &InvitationModel{
+		ID:             i.ID(),
+		Email:          i.Email(),
+		Role:           i.Role(),
+		TokenHash:      tokenHash,
+		InvitedBy:      i.InvitedBy(),
+		ExpiresAt:      i.ExpiresAt(),
+		AcceptedAt:     i.AcceptedAt(),
+		AcceptedUserID: i.AcceptedUserID(),
+		RevokedAt:      i.RevokedAt(),
+		CreatedAt:      i.CreatedAt(),
+	}
+}
+
+// GormInvitationRepository is a GORM-based implementation of InvitationRepository.
+// Invitation tokens are stored and looked up by their keyed hash only.
+type GormInvitationRepository struct {
+	db     *gorm.DB
+	hasher *TokenHasher
+}
+
+// NewGormInvitationRepository creates a new GormInvitationRepository.
+func NewGormInvitationRepository(db *gorm.DB, hasher *TokenHasher) *GormInvitationRepository {
+	return &GormInvitationRepository{db: db, hasher: hasher}
+}
+
+// Save persists a new invitation.
+func (r *GormInvitationRepository) Save(ctx context.Context, invitation *identity.Invitation) error {
+	model := fromDomainInvitation(invitation, r.hasher.Hash(invitation.Token()))
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+// FindByID retrieves an invitation by its ID.
+func (r *GormInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*identity.Invitation, error) {
+	var model InvitationModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(""), nil
+}
+
+// FindByToken retrieves an invitation by its raw token, whatever its status.
+func (r *GormInvitationRepository) FindByToken(ctx context.Context, token string) (*identity.Invitation, error) {
+	var model InvitationModel
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", r.hasher.Hash(token)).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(token), nil
+}
+
+// FindPendingByEmail retrieves the pending invitation for an email address, if any.
+func (r *GormInvitationRepository) FindPendingByEmail(ctx context.Context, email identity.Email) (*identity.Invitation, error) {
+	var model InvitationModel
+	err := r.db.WithContext(ctx).
+		Where("email = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", email.String(), time.Now().UTC()).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(""), nil
+}
+
+// List returns a paginated list of invitations, newest first.
+func (r *GormInvitationRepository) List(ctx context.Context, page, limit int) ([]*identity.Invitation, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&InvitationModel{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []InvitationModel
+	offset := (page - 1) * limit
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	invitations := make([]*identity.Invitation, len(models))
+	for i := range models {
+		invitations[i] = models[i].toDomain("")
+	}
+	return invitations, total, nil
+}
+
+// Accept creates the invited user and marks the invitation accepted in a single transaction.
+// The pending guard in the UPDATE makes the invitation single-use even under concurrent
+// accepts; the loser's user insert is rolled back.
+func (r *GormInvitationRepository) Accept(ctx context.Context, invitation *identity.Invitation, user *identity.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(fromDomainUser(user)).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&InvitationModel{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", invitation.ID(), time.Now().UTC()).
+			Updates(map[string]interface{}{
+				"accepted_at":      invitation.AcceptedAt(),
+				"accepted_user_id": invitation.AcceptedUserID(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return identity.ErrInvitationNotPending
+		}
+		return nil
+	})
+}
+
+// Revoke marks a pending invitation revoked.
+func (r *GormInvitationRepository) Revoke(ctx context.Context, invitation *identity.Invitation) error {
+	result := r.db.WithContext(ctx).
+		Model(&InvitationModel{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.ID()).
+		Update("revoked_at", invitation.RevokedAt())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return identity.ErrInvitationNotPending
+	}
+	return nil
+}
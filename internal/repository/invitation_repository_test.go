@@ -0,0 +1,70 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestInvitationRepo_AcceptIsSingleUse(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE invitations").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	adminID := seedTestUser(t, db)
+
+	repo := repository.NewGormInvitationRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	email := "invitee-" + uuid.New().String() + "@kilat.my"
+	invitation, err := identity.NewInvitation(email, auth.RoleAdmin, adminID, "invite-token-"+uuid.New().String(), time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewInvitation failed: %v", err)
+	}
+	if err := repo.Save(ctx, invitation); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	found, err := repo.FindByToken(ctx, invitation.Token())
+	if err != nil {
+		t.Fatalf("FindByToken failed: %v", err)
+	}
+
+	user, err := identity.NewUser(email, "", "Invited Admin", "$2a$10$placeholder", found.Role())
+	if err != nil {
+		t.Fatalf("NewUser failed: %v", err)
+	}
+	if err := found.Accept(user.ID()); err != nil {
+		t.Fatalf("Accept (domain) failed: %v", err)
+	}
+	if err := repo.Accept(ctx, found, user); err != nil {
+		t.Fatalf("first Accept failed: %v", err)
+	}
+
+	// A stale copy of the still-pending invitation must not create a second account.
+	stale, err := identity.NewUser("second-"+email, "", "Second", "$2a$10$placeholder", found.Role())
+	if err != nil {
+		t.Fatalf("NewUser failed: %v", err)
+	}
+	replay := identity.ReconstructInvitation(found.ID(), email, found.Role(), found.Token(), adminID, found.ExpiresAt(), nil, nil, nil, found.CreatedAt())
+	if err := replay.Accept(stale.ID()); err != nil {
+		t.Fatalf("Accept (domain) on stale copy failed: %v", err)
+	}
+	if err := repo.Accept(ctx, replay, stale); !errors.Is(err, identity.ErrInvitationNotPending) {
+		t.Fatalf("expected ErrInvitationNotPending on second accept, got %v", err)
+	}
+
+	var count int64
+	db.Model(&repository.UserModel{}).Where("id = ?", stale.ID()).Count(&count)
+	if count != 0 {
+		t.Error("expected the second account insert to be rolled back")
+	}
+}
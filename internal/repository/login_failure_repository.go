@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"gorm.io/gorm"
+)
+
+// LoginFailureModel is the GORM model for the login_failures table.
+type LoginFailureModel struct {
+	Scope        identity.LoginFailureScope `gorm:"type:varchar(20);primaryKey"`
+	Key          string                     `gorm:"type:varchar(255);primaryKey"`
+	Failures     int                        `gorm:"not null;default:0"`
+	LastFailedAt time.Time                  `gorm:"not null;index"`
+	LockedUntil  *time.Time                 `gorm:""`
+}
+
+// TableName specifies the table name for GORM.
+func (LoginFailureModel) TableName() string {
+	return "login_failures"
+}
+
+// toDomain converts a LoginFailureModel to a domain LoginFailure.
+func (m *LoginFailureModel) toDomain() *identity.LoginFailure {
+	return identity.ReconstructLoginFailure(m.Scope, m.Key, m.Failures, m.LastFailedAt, m.LockedUntil)
+}
+
+// GormLoginFailureRepository is a GORM-based implementation of LoginFailureRepository.
+type GormLoginFailureRepository struct {
+	db *gorm.DB
+}
+
+// NewGormLoginFailureRepository creates a new GormLoginFailureRepository.
+func NewGormLoginFailureRepository(db *gorm.DB) *GormLoginFailureRepository {
+	return &GormLoginFailureRepository{db: db}
+}
+
+// Find returns the counter for the scope and key.
+func (r *GormLoginFailureRepository) Find(ctx context.Context, scope identity.LoginFailureScope, key string) (*identity.LoginFailure, error) {
+	var model LoginFailureModel
+	if err := r.db.WithContext(ctx).Where("scope = ? AND key = ?", scope, key).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// RecordFailure counts a failure with a single upsert, so concurrent failures on
+// different replicas are all counted.
+func (r *GormLoginFailureRepository) RecordFailure(ctx context.Context, scope identity.LoginFailureScope, key string, resetAfter time.Duration) (*identity.LoginFailure, error) {
+	now := time.Now().UTC()
+	var model LoginFailureModel
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO login_failures (scope, key, failures, last_failed_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE WHEN login_failures.last_failed_at < ? THEN 1 ELSE login_failures.failures + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING scope, key, failures, last_failed_at, locked_until`,
+		scope, key, now, now.Add(-resetAfter),
+	).Scan(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// Lock sets a lockout unless one is already in effect.
+func (r *GormLoginFailureRepository) Lock(ctx context.Context, scope identity.LoginFailureScope, key string, until time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&LoginFailureModel{}).
+		Where("scope = ? AND key = ? AND (locked_until IS NULL OR locked_until <= ?)", scope, key, time.Now().UTC()).
+		Update("locked_until", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Reset removes the counter.
+func (r *GormLoginFailureRepository) Reset(ctx context.Context, scope identity.LoginFailureScope, key string) error {
+	return r.db.WithContext(ctx).Where("scope = ? AND key = ?", scope, key).Delete(&LoginFailureModel{}).Error
+}
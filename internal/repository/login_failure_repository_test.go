@@ -0,0 +1,76 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+)
+
+func TestLoginFailureRepo_CountsAndLocksOnce(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE login_failures").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+
+	repo := repository.NewGormLoginFailureRepository(db)
+	ctx := context.Background()
+
+	var failure *identity.LoginFailure
+	for i := 0; i < 3; i++ {
+		var err error
+		failure, err = repo.RecordFailure(ctx, identity.LoginFailureScopeAccount, "aida@kilat.my", time.Hour)
+		if err != nil {
+			t.Fatalf("RecordFailure failed: %v", err)
+		}
+	}
+	if failure.Failures() != 3 {
+		t.Fatalf("expected 3 failures, got %d", failure.Failures())
+	}
+
+	until := time.Now().UTC().Add(time.Minute)
+	locked, err := repo.Lock(ctx, identity.LoginFailureScopeAccount, "aida@kilat.my", until)
+	if err != nil || !locked {
+		t.Fatalf("expected first Lock to succeed, got %v, %v", locked, err)
+	}
+	locked, err = repo.Lock(ctx, identity.LoginFailureScopeAccount, "aida@kilat.my", until)
+	if err != nil || locked {
+		t.Errorf("expected second Lock to be a no-op while locked, got %v, %v", locked, err)
+	}
+
+	if err := repo.Reset(ctx, identity.LoginFailureScopeAccount, "aida@kilat.my"); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if _, err := repo.Find(ctx, identity.LoginFailureScopeAccount, "aida@kilat.my"); err == nil {
+		t.Error("expected counter to be gone after Reset")
+	}
+}
+
+func TestLoginFailureRepo_StaleCounterStartsOver(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE login_failures").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+
+	repo := repository.NewGormLoginFailureRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.RecordFailure(ctx, identity.LoginFailureScopeIP, "203.0.113.7", time.Hour); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	if err := db.Exec("UPDATE login_failures SET last_failed_at = ?", time.Now().UTC().Add(-2*time.Hour)).Error; err != nil {
+		t.Fatalf("backdate failed: %v", err)
+	}
+
+	failure, err := repo.RecordFailure(ctx, identity.LoginFailureScopeIP, "203.0.113.7", time.Hour)
+	if err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	if failure.Failures() != 1 {
+		t.Errorf("expected counter to start over, got %d failures", failure.Failures())
+	}
+}
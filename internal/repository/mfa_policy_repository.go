@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MFAPolicyRoleModel is the GORM model for the mfa_required_roles table. Each
+// row names a role whose members must sign in with a second factor.
+type MFAPolicyRoleModel struct {
+	Role      string    `gorm:"type:varchar(20);primaryKey"`
+	UpdatedBy uuid.UUID `gorm:"type:uuid;not null"`
+	UpdatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (MFAPolicyRoleModel) TableName() string {
+	return "mfa_required_roles"
+}
+
+// GormMFAPolicyRepository is a GORM-based implementation of MFAPolicyRepository.
+type GormMFAPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormMFAPolicyRepository creates a new GormMFAPolicyRepository.
+func NewGormMFAPolicyRepository(db *gorm.DB) *GormMFAPolicyRepository {
+	return &GormMFAPolicyRepository{db: db}
+}
+
+// ListRequiredRoles returns the roles that must use two-factor authentication.
+func (r *GormMFAPolicyRepository) ListRequiredRoles(ctx context.Context) ([]auth.UserRole, error) {
+	var models []MFAPolicyRoleModel
+	if err := r.db.WithContext(ctx).Order("role").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	roles := make([]auth.UserRole, len(models))
+	for i := range models {
+		roles[i] = auth.UserRole(models[i].Role)
+	}
+	return roles, nil
+}
+
+// ReplaceRequiredRoles replaces the required roles in one transaction.
+func (r *GormMFAPolicyRepository) ReplaceRequiredRoles(ctx context.Context, roles []auth.UserRole, actorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&MFAPolicyRoleModel{}).Error; err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		for _, role := range roles {
+			if err := tx.Create(&MFAPolicyRoleModel{Role: string(role), UpdatedBy: actorID, UpdatedAt: now}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
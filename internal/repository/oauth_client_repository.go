@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// OAuthClientModel is the GORM model for the oauth_clients table.
+type OAuthClientModel struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ClientID        string         `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Name            string         `gorm:"type:varchar(100);not null"`
+	SecretHash      string         `gorm:"type:char(64);not null"`
+	Scopes          pq.StringArray `gorm:"type:text[];not null;default:'{}'"`
+	RedirectURIs    pq.StringArray `gorm:"type:text[];not null;default:'{}'"`
+	CreatedBy       uuid.UUID      `gorm:"type:uuid;not null"`
+	SecretRotatedAt time.Time      `gorm:"not null"`
+	DisabledAt      *time.Time
+	CreatedAt       time.Time `gorm:"not null;default:now()"`
+	UpdatedAt       time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (OAuthClientModel) TableName() string {
+	return "oauth_clients"
+}
+
+// toDomain converts an OAuthClientModel to a domain OAuthClient.
+func (m *OAuthClientModel) toDomain() *identity.OAuthClient {
+	return identity.ReconstructOAuthClient(
+		m.ID,
+		m.ClientID,
+		m.Name,
+		[]string(m.Scopes),
+		[]string(m.RedirectURIs),
+		m.CreatedBy,
+		m.SecretRotatedAt,
+		m.DisabledAt,
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+}
+
+// GormOAuthClientRepository is a GORM-based implementation of OAuthClientRepository.
+// Client secrets are stored and checked by their keyed hash only.
+type GormOAuthClientRepository struct {
+	db     *gorm.DB
+	hasher *TokenHasher
+}
+
+// NewGormOAuthClientRepository creates a new GormOAuthClientRepository.
+func NewGormOAuthClientRepository(db *gorm.DB, hasher *TokenHasher) *GormOAuthClientRepository {
+	return &GormOAuthClientRepository{db: db, hasher: hasher}
+}
+
+// Create persists a new client with the hash of its secret.
+func (r *GormOAuthClientRepository) Create(ctx context.Context, client *identity.OAuthClient) error {
+	return r.db.WithContext(ctx).Create(&OAuthClientModel{
+		ID:              client.ID(),
+		ClientID:        client.ClientID(),
+		Name:            client.Name(),
+		SecretHash:      r.hasher.Hash(client.Secret()),
+		Scopes:          pq.StringArray(client.Scopes()),
+		RedirectURIs:    pq.StringArray(client.RedirectURIs()),
+		CreatedBy:       client.CreatedBy(),
+		SecretRotatedAt: client.SecretRotatedAt(),
+		DisabledAt:      client.DisabledAt(),
+		CreatedAt:       client.CreatedAt(),
+		UpdatedAt:       client.UpdatedAt(),
+	}).Error
+}
+
+// FindByID retrieves a client by its internal ID.
+func (r *GormOAuthClientRepository) FindByID(ctx context.Context, id uuid.UUID) (*identity.OAuthClient, error) {
+	return r.findOne(ctx, r.db.Where("id = ?", id))
+}
+
+// FindByClientID retrieves a client by its public client ID.
+func (r *GormOAuthClientRepository) FindByClientID(ctx context.Context, clientID string) (*identity.OAuthClient, error) {
+	return r.findOne(ctx, r.db.Where("client_id = ?", clientID))
+}
+
+// FindByCredentials retrieves a client by its client ID and the hash of its secret.
+func (r *GormOAuthClientRepository) FindByCredentials(ctx context.Context, clientID, secret string) (*identity.OAuthClient, error) {
+	return r.findOne(ctx, r.db.Where("client_id = ? AND secret_hash = ?", clientID, r.hasher.Hash(secret)))
+}
+
+func (r *GormOAuthClientRepository) findOne(ctx context.Context, query *gorm.DB) (*identity.OAuthClient, error) {
+	var model OAuthClientModel
+	if err := query.WithContext(ctx).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List returns every client, newest first.
+func (r *GormOAuthClientRepository) List(ctx context.Context) ([]*identity.OAuthClient, error) {
+	var models []OAuthClientModel
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	clients := make([]*identity.OAuthClient, len(models))
+	for i := range models {
+		clients[i] = models[i].toDomain()
+	}
+	return clients, nil
+}
+
+// Update persists the client's secret rotation time and disabled state, and the hash
+// of its secret when it carries a newly rotated one.
+func (r *GormOAuthClientRepository) Update(ctx context.Context, client *identity.OAuthClient) error {
+	updates := map[string]interface{}{
+		"secret_rotated_at": client.SecretRotatedAt(),
+		"disabled_at":       client.DisabledAt(),
+		"updated_at":        client.UpdatedAt(),
+	}
+	if client.Secret() != "" {
+		updates["secret_hash"] = r.hasher.Hash(client.Secret())
+	}
+	result := r.db.WithContext(ctx).Model(&OAuthClientModel{}).Where("id = ?", client.ID()).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
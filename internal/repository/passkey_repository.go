@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PasskeyModel is the GORM model for the passkeys table.
+type PasskeyModel struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID       uuid.UUID      `gorm:"type:uuid;not null;index"`
+	CredentialID []byte         `gorm:"type:bytea;not null;uniqueIndex"`
+	PublicKey    []byte         `gorm:"type:bytea;not null"`
+	SignCount    int64          `gorm:"not null;default:0"`
+	Transports   pq.StringArray `gorm:"type:text[];not null;default:'{}'"`
+	Name         string         `gorm:"type:varchar(100);not null"`
+	CreatedAt    time.Time      `gorm:"not null;default:now()"`
+	LastUsedAt   *time.Time     `gorm:""`
+}
+
+// TableName specifies the table name for GORM.
+func (PasskeyModel) TableName() string {
+	return "passkeys"
+}
+
+// toDomain converts a PasskeyModel to a domain Passkey.
+func (m *PasskeyModel) toDomain() *identity.Passkey {
+	return identity.ReconstructPasskey(
+		m.ID,
+		m.UserID,
+		m.CredentialID,
+		m.PublicKey,
+		uint32(m.SignCount),
+		[]string(m.Transports),
+		m.Name,
+		m.CreatedAt,
+		m.LastUsedAt,
+	)
+}
+
+// GormPasskeyRepository is a GORM-based implementation of PasskeyRepository.
+type GormPasskeyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPasskeyRepository creates a new GormPasskeyRepository.
+func NewGormPasskeyRepository(db *gorm.DB) *GormPasskeyRepository {
+	return &GormPasskeyRepository{db: db}
+}
+
+// Save stores a new passkey.
+func (r *GormPasskeyRepository) Save(ctx context.Context, passkey *identity.Passkey) error {
+	transports := passkey.Transports()
+	if transports == nil {
+		transports = []string{}
+	}
+	err := r.db.WithContext(ctx).Create(&PasskeyModel{
+		ID:           passkey.ID(),
+		UserID:       passkey.UserID(),
+		CredentialID: passkey.CredentialID(),
+		PublicKey:    passkey.PublicKey(),
+		SignCount:    int64(passkey.SignCount()),
+		Transports:   pq.StringArray(transports),
+		Name:         passkey.Name(),
+		CreatedAt:    passkey.CreatedAt(),
+	}).Error
+	if isCredentialIDDuplicateError(err) {
+		return identity.ErrPasskeyAlreadyRegistered
+	}
+	return err
+}
+
+// FindByCredentialID retrieves a passkey by the authenticator-assigned credential ID.
+func (r *GormPasskeyRepository) FindByCredentialID(ctx context.Context, credentialID []byte) (*identity.Passkey, error) {
+	var model PasskeyModel
+	if err := r.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// ListForUser returns the user's passkeys, oldest first.
+func (r *GormPasskeyRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]*identity.Passkey, error) {
+	var models []PasskeyModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]*identity.Passkey, len(models))
+	for i := range models {
+		passkeys[i] = models[i].toDomain()
+	}
+	return passkeys, nil
+}
+
+// RecordUse persists the new signature counter. Authenticators without a counter
+// always report zero, so the guard only applies once a counter has been seen.
+func (r *GormPasskeyRepository) RecordUse(ctx context.Context, passkey *identity.Passkey) error {
+	signCount := int64(passkey.SignCount())
+	result := r.db.WithContext(ctx).
+		Model(&PasskeyModel{}).
+		Where("id = ? AND (sign_count < ? OR (sign_count = 0 AND ? = 0))", passkey.ID(), signCount, signCount).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"last_used_at": passkey.LastUsedAt(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return identity.ErrPasskeySignCountRegressed
+	}
+	return nil
+}
+
+// Delete removes one of the user's passkeys.
+func (r *GormPasskeyRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&PasskeyModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// isCredentialIDDuplicateError returns true if the error is a Postgres unique-constraint
+// violation on the credential_id column.
+func isCredentialIDDuplicateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pqErr *pq.Error
+	if ok := isPqError(err, &pqErr); ok {
+		return pqErr.Code == "23505" && strings.Contains(pqErr.Constraint, "credential_id")
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "duplicate key") && strings.Contains(msg, "credential_id")
+}
+
+// WebAuthnChallengeModel is the GORM model for the webauthn_challenges table.
+type WebAuthnChallengeModel struct {
+	ID        uuid.UUID                `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    *uuid.UUID               `gorm:"type:uuid"`
+	Challenge []byte                   `gorm:"type:bytea;not null"`
+	Purpose   identity.WebAuthnPurpose `gorm:"type:varchar(20);not null"`
+	ExpiresAt time.Time                `gorm:"not null;index"`
+	CreatedAt time.Time                `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (WebAuthnChallengeModel) TableName() string {
+	return "webauthn_challenges"
+}
+
+// GormWebAuthnChallengeRepository is a GORM-based implementation of WebAuthnChallengeRepository.
+type GormWebAuthnChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWebAuthnChallengeRepository creates a new GormWebAuthnChallengeRepository.
+func NewGormWebAuthnChallengeRepository(db *gorm.DB) *GormWebAuthnChallengeRepository {
+	return &GormWebAuthnChallengeRepository{db: db}
+}
+
+// Create stores a new challenge.
+func (r *GormWebAuthnChallengeRepository) Create(ctx context.Context, challenge *identity.WebAuthnChallenge) error {
+	return r.db.WithContext(ctx).Create(&WebAuthnChallengeModel{
+		ID:        challenge.ID(),
+		UserID:    challenge.UserID(),
+		Challenge: challenge.Challenge(),
+		Purpose:   challenge.Purpose(),
+		ExpiresAt: challenge.ExpiresAt(),
+		CreatedAt: challenge.CreatedAt(),
+	}).Error
+}
+
+// Consume deletes and returns a challenge. Of two concurrent callers only the one
+// whose delete removes the row gets it back.
+func (r *GormWebAuthnChallengeRepository) Consume(ctx context.Context, id uuid.UUID) (*identity.WebAuthnChallenge, error) {
+	var model WebAuthnChallengeModel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&WebAuthnChallengeModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return identity.ReconstructWebAuthnChallenge(model.ID, model.UserID, model.Challenge, model.Purpose, model.ExpiresAt, model.CreatedAt), nil
+}
+
+// DeleteExpired removes challenges that expired before the given time.
+func (r *GormWebAuthnChallengeRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&WebAuthnChallengeModel{}).Error
+}
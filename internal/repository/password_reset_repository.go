@@ -15,7 +15,7 @@ import (
 type PasswordResetModel struct {
 	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
-	Token     string     `gorm:"type:text;uniqueIndex;not null"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null"`
 	ExpiresAt time.Time  `gorm:"not null"`
 	UsedAt    *time.Time `gorm:""`
 	CreatedAt time.Time  `gorm:"not null;default:now()"`
@@ -27,11 +27,12 @@ func (PasswordResetModel) TableName() string {
 }
 
 // toDomain converts a PasswordResetModel to a domain PasswordReset.
-func (m *PasswordResetModel) toDomain() *identity.PasswordReset {
+// Only the token hash is stored, so the caller supplies the raw token it looked up by.
+func (m *PasswordResetModel) toDomain(token string) *identity.PasswordReset {
 	return identity.ReconstructPasswordReset(
 		m.ID,
 		m.UserID,
-		m.Token,
+		token,
 		m.ExpiresAt,
 		m.UsedAt,
 		m.CreatedAt,
@@ -39,11 +40,11 @@ func (m *PasswordResetModel) toDomain() *identity.PasswordReset {
 }
 
 // fromDomainPasswordReset converts a domain PasswordReset to a PasswordResetModel.
-func fromDomainPasswordReset(p *identity.PasswordReset) *PasswordResetModel {
+func fromDomainPasswordReset(p *identity.PasswordReset, tokenHash string) *PasswordResetModel {
 	return &PasswordResetModel{
 		ID:        p.ID(),
 		UserID:    p.UserID(),
-		Token:     p.Token(),
+		TokenHash: tokenHash,
 		ExpiresAt: p.ExpiresAt(),
 		UsedAt:    p.UsedAt(),
 		CreatedAt: p.CreatedAt(),
@@ -51,27 +52,40 @@ func fromDomainPasswordReset(p *identity.PasswordReset) *PasswordResetModel {
 }
 
 // GormPasswordResetRepository is a GORM-based implementation of PasswordResetRepository.
+// Tokens are stored and looked up by their keyed hash only.
 type GormPasswordResetRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	hasher *TokenHasher
 }
 
 // NewGormPasswordResetRepository creates a new GormPasswordResetRepository.
-func NewGormPasswordResetRepository(db *gorm.DB) *GormPasswordResetRepository {
-	return &GormPasswordResetRepository{db: db}
+func NewGormPasswordResetRepository(db *gorm.DB, hasher *TokenHasher) *GormPasswordResetRepository {
+	return &GormPasswordResetRepository{db: db, hasher: hasher}
 }
 
 // Create persists a new password reset token to the database.
 func (r *GormPasswordResetRepository) Create(ctx context.Context, reset *identity.PasswordReset) error {
-	model := fromDomainPasswordReset(reset)
+	model := fromDomainPasswordReset(reset, r.hasher.Hash(reset.Token()))
 	return r.db.WithContext(ctx).Create(model).Error
 }
 
+// ReplaceOutstanding stores reset and deletes the user's other unused reset tokens.
+func (r *GormPasswordResetRepository) ReplaceOutstanding(ctx context.Context, reset *identity.PasswordReset) error {
+	model := fromDomainPasswordReset(reset, r.hasher.Hash(reset.Token()))
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", reset.UserID()).Delete(&PasswordResetModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+}
+
 // FindByToken retrieves a non-expired password reset token by its token string.
 // Returns domain.ErrNotFound if the token does not exist or has expired.
 func (r *GormPasswordResetRepository) FindByToken(ctx context.Context, token string) (*identity.PasswordReset, error) {
 	var model PasswordResetModel
 	err := r.db.WithContext(ctx).
-		Where("token = ? AND expires_at > ?", token, time.Now().UTC()).
+		Where("token_hash = ? AND expires_at > ?", r.hasher.Hash(token), time.Now().UTC()).
 		First(&model).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -79,7 +93,7 @@ func (r *GormPasswordResetRepository) FindByToken(ctx context.Context, token str
 		}
 		return nil, err
 	}
-	return model.toDomain(), nil
+	return model.toDomain(token), nil
 }
 
 // FindAnyByToken retrieves a password reset token by its token string without filtering by expiry.
@@ -87,7 +101,7 @@ func (r *GormPasswordResetRepository) FindByToken(ctx context.Context, token str
 func (r *GormPasswordResetRepository) FindAnyByToken(ctx context.Context, token string) (*identity.PasswordReset, error) {
 	var model PasswordResetModel
 	err := r.db.WithContext(ctx).
-		Where("token = ?", token).
+		Where("token_hash = ?", r.hasher.Hash(token)).
 		First(&model).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -95,7 +109,7 @@ func (r *GormPasswordResetRepository) FindAnyByToken(ctx context.Context, token
 		}
 		return nil, err
 	}
-	return model.toDomain(), nil
+	return model.toDomain(token), nil
 }
 
 // MarkUsed sets the used_at timestamp on a password reset token.
@@ -114,13 +128,17 @@ func (r *GormPasswordResetRepository) MarkUsed(ctx context.Context, id uuid.UUID
 	return nil
 }
 
-// MarkUsedAndUpdatePassword atomically marks a reset token as used and updates the user's password hash.
-// Both updates are wrapped in a single Postgres transaction; a failure in either rolls back both.
+// MarkUsedAndUpdatePassword atomically marks a reset token as used, updates the user's password hash
+// and revokes every session and refresh token of the user, so whoever may have known the old
+// password is signed out. All writes share one Postgres transaction; a failure in any rolls back all.
+// Only an unused, unexpired token is consumed, so of two concurrent resets with one link the
+// second gets domain.ErrNotFound.
 func (r *GormPasswordResetRepository) MarkUsedAndUpdatePassword(ctx context.Context, tokenID uuid.UUID, userID uuid.UUID, newHash string) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
 		result := tx.Model(&PasswordResetModel{}).
-			Where("id = ?", tokenID).
-			Update("used_at", time.Now().UTC())
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", tokenID, now).
+			Update("used_at", now)
 		if result.Error != nil {
 			return result.Error
 		}
@@ -132,7 +150,7 @@ func (r *GormPasswordResetRepository) MarkUsedAndUpdatePassword(ctx context.Cont
 			Where("id = ?", userID).
 			Updates(map[string]interface{}{
 				"password_hash": newHash,
-				"updated_at":    time.Now().UTC(),
+				"updated_at":    now,
 			})
 		if result.Error != nil {
 			return result.Error
@@ -140,6 +158,6 @@ func (r *GormPasswordResetRepository) MarkUsedAndUpdatePassword(ctx context.Cont
 		if result.RowsAffected == 0 {
 			return domain.ErrNotFound
 		}
-		return nil
+		return revokeAllSessions(tx, userID)
 	})
 }
@@ -50,7 +50,7 @@ func TestPasswordResetRepo_CreateAndFindByToken(t *testing.T) {
 	db := setupTestDB(t)
 	userID := seedTestUser(t, db)
 
-	repo := repository.NewGormPasswordResetRepository(db)
+	repo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
 	ctx := context.Background()
 
 	reset := identity.NewPasswordReset(userID, "valid-token-abc123", time.Now().UTC().Add(time.Hour))
@@ -80,7 +80,7 @@ func TestPasswordResetRepo_FindByToken_ExpiredReturnsNil(t *testing.T) {
 	db := setupTestDB(t)
 	userID := seedTestUser(t, db)
 
-	repo := repository.NewGormPasswordResetRepository(db)
+	repo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
 	ctx := context.Background()
 
 	reset := identity.NewPasswordReset(userID, "expired-token-xyz", time.Now().UTC().Add(-time.Hour))
@@ -96,3 +96,102 @@ func TestPasswordResetRepo_FindByToken_ExpiredReturnsNil(t *testing.T) {
 		t.Errorf("expected domain.ErrNotFound, got %v", err)
 	}
 }
+
+func TestPasswordResetRepo_ReplaceOutstandingInvalidatesEarlierTokens(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	first := identity.NewPasswordReset(userID, "first-token", time.Now().UTC().Add(time.Hour))
+	if err := repo.ReplaceOutstanding(ctx, first); err != nil {
+		t.Fatalf("ReplaceOutstanding failed: %v", err)
+	}
+	second := identity.NewPasswordReset(userID, "second-token", time.Now().UTC().Add(time.Hour))
+	if err := repo.ReplaceOutstanding(ctx, second); err != nil {
+		t.Fatalf("ReplaceOutstanding failed: %v", err)
+	}
+
+	if _, err := repo.FindAnyByToken(ctx, "first-token"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected the earlier token to be gone, got %v", err)
+	}
+	if _, err := repo.FindByToken(ctx, "second-token"); err != nil {
+		t.Errorf("expected the newest token to work, got %v", err)
+	}
+}
+
+func TestPasswordResetRepo_MarkUsedAndUpdatePasswordRevokesSessions(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	sessionRepo := repository.NewGormSessionRepository(db)
+	repo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	session := identity.NewSession(userID, "Phone", "ios", "test-agent", "127.0.0.1", []string{"pwd"})
+	if err := sessionRepo.Save(ctx, session); err != nil {
+		t.Fatalf("Save session failed: %v", err)
+	}
+	reset := identity.NewPasswordReset(userID, "revoke-token", time.Now().UTC().Add(time.Hour))
+	if err := repo.Create(ctx, reset); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := repo.MarkUsedAndUpdatePassword(ctx, reset.ID(), userID, "$2a$10$newhash"); err != nil {
+		t.Fatalf("MarkUsedAndUpdatePassword failed: %v", err)
+	}
+
+	var revoked int64
+	if err := db.Model(&repository.SessionModel{}).Where("id = ? AND revoked_at IS NOT NULL", session.ID()).Count(&revoked).Error; err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if revoked != 1 {
+		t.Error("expected the user's session to be revoked by the reset")
+	}
+}
+
+func TestPasswordResetRepo_MarkUsedAndUpdatePassword_SameTokenTwice(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	reset := identity.NewPasswordReset(userID, "twice-token", time.Now().UTC().Add(time.Hour))
+	if err := repo.Create(ctx, reset); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := repo.MarkUsedAndUpdatePassword(ctx, reset.ID(), userID, "$2a$10$firsthash"); err != nil {
+		t.Fatalf("first MarkUsedAndUpdatePassword failed: %v", err)
+	}
+	if err := repo.MarkUsedAndUpdatePassword(ctx, reset.ID(), userID, "$2a$10$secondhash"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected domain.ErrNotFound for a used token, got %v", err)
+	}
+
+	var user repository.UserModel
+	if err := db.Where("id = ?", userID).First(&user).Error; err != nil {
+		t.Fatalf("failed to read user: %v", err)
+	}
+	if user.PasswordHash != "$2a$10$firsthash" {
+		t.Errorf("expected the second reset to leave the password alone, got %q", user.PasswordHash)
+	}
+}
+
+func TestPasswordResetRepo_MarkUsedAndUpdatePassword_ExpiredToken(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	reset := identity.NewPasswordReset(userID, "expired-consume-token", time.Now().UTC().Add(-time.Minute))
+	if err := repo.Create(ctx, reset); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := repo.MarkUsedAndUpdatePassword(ctx, reset.ID(), userID, "$2a$10$newhash"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected domain.ErrNotFound for an expired token, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PhoneOTPModel is the GORM model for the phone_otps table.
+type PhoneOTPModel struct {
+	ID          uuid.UUID           `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID      *uuid.UUID          `gorm:"type:uuid;index"`
+	Phone       string              `gorm:"type:varchar(20);not null"`
+	Purpose     identity.OTPPurpose `gorm:"type:varchar(30);not null"`
+	CodeHash    string              `gorm:"type:char(64);not null"`
+	Attempts    int                 `gorm:"not null;default:0"`
+	MaxAttempts int                 `gorm:"not null"`
+	ExpiresAt   time.Time           `gorm:"not null"`
+	ConsumedAt  *time.Time          `gorm:""`
+	CreatedAt   time.Time           `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (PhoneOTPModel) TableName() string {
+	return "phone_otps"
+}
+
+// toDomain converts a PhoneOTPModel to a domain PhoneOTP.
+func (m *PhoneOTPModel) toDomain() *identity.PhoneOTP {
+	return identity.ReconstructPhoneOTP(
+		m.ID,
+		m.UserID,
+		m.Phone,
+		m.Purpose,
+		m.CodeHash,
+		m.Attempts,
+		m.MaxAttempts,
+		m.ExpiresAt,
+		m.ConsumedAt,
+		m.CreatedAt,
+	)
+}
+
+// fromDomainPhoneOTP converts a domain PhoneOTP to a PhoneOTPModel.
+func fromDomainPhoneOTP(o *identity.PhoneOTP) *PhoneOTPModel {
+	return &PhoneOTPModel{
+		ID:          o.ID(),
+		UserID:      o.UserID(),
+		Phone:       o.Phone(),
+		Purpose:     o.Purpose(),
+		CodeHash:    o.CodeHash(),
+		Attempts:    o.Attempts(),
+		MaxAttempts: o.MaxAttempts(),
+		ExpiresAt:   o.ExpiresAt(),
+		ConsumedAt:  o.ConsumedAt(),
+		CreatedAt:   o.CreatedAt(),
+	}
+}
+
+// GormPhoneOTPRepository is a GORM-based implementation of PhoneOTPRepository.
+type GormPhoneOTPRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPhoneOTPRepository creates a new GormPhoneOTPRepository.
+func NewGormPhoneOTPRepository(db *gorm.DB) *GormPhoneOTPRepository {
+	return &GormPhoneOTPRepository{db: db}
+}
+
+// Create persists a new one-time code.
+func (r *GormPhoneOTPRepository) Create(ctx context.Context, otp *identity.PhoneOTP) error {
+	return r.db.WithContext(ctx).Create(fromDomainPhoneOTP(otp)).Error
+}
+
+// FindLatest returns the most recently issued code for the phone and purpose.
+func (r *GormPhoneOTPRepository) FindLatest(ctx context.Context, phone string, purpose identity.OTPPurpose) (*identity.PhoneOTP, error) {
+	var model PhoneOTPModel
+	err := r.db.WithContext(ctx).
+		Where("phone = ? AND purpose = ?", phone, purpose).
+		Order("created_at DESC").
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// CountCreatedSince counts the codes sent to a phone for a purpose since the given time.
+func (r *GormPhoneOTPRepository) CountCreatedSince(ctx context.Context, phone string, purpose identity.OTPPurpose, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&PhoneOTPModel{}).
+		Where("phone = ? AND purpose = ? AND created_at > ?", phone, purpose, since).
+		Count(&count).Error
+	return count, err
+}
+
+// RecordFailedAttempt increments the wrong-guess counter in a single statement so
+// concurrent guesses are all counted.
+func (r *GormPhoneOTPRepository) RecordFailedAttempt(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&PhoneOTPModel{}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).
+		Error
+}
+
+// Consume marks a code redeemed. The guard makes redemption single-use under concurrency.
+func (r *GormPhoneOTPRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&PhoneOTPModel{}).
+		Where("id = ? AND consumed_at IS NULL AND attempts < max_attempts", id).
+		Update("consumed_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
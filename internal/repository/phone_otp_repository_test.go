@@ -0,0 +1,69 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+)
+
+func TestPhoneOTPRepo_ExhaustedCodeCannotBeConsumed(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE phone_otps").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+
+	repo := repository.NewGormPhoneOTPRepository(db)
+	ctx := context.Background()
+
+	otp := identity.NewPhoneOTP(nil, "+60123456789", identity.OTPPurposePhoneVerification, "hash", 2, time.Now().UTC().Add(time.Minute))
+	if err := repo.Create(ctx, otp); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.RecordFailedAttempt(ctx, otp.ID()); err != nil {
+			t.Fatalf("RecordFailedAttempt failed: %v", err)
+		}
+	}
+
+	latest, err := repo.FindLatest(ctx, "+60123456789", identity.OTPPurposePhoneVerification)
+	if err != nil {
+		t.Fatalf("FindLatest failed: %v", err)
+	}
+	if !latest.AttemptsExhausted() {
+		t.Errorf("expected attempts to be exhausted, got %d of %d", latest.Attempts(), latest.MaxAttempts())
+	}
+
+	if err := repo.Consume(ctx, otp.ID()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound consuming an exhausted code, got %v", err)
+	}
+}
+
+func TestPhoneOTPRepo_ConsumeIsSingleUse(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE phone_otps").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+
+	repo := repository.NewGormPhoneOTPRepository(db)
+	ctx := context.Background()
+
+	otp := identity.NewPhoneOTP(nil, "+60198765432", identity.OTPPurposePhoneVerification, "hash", 5, time.Now().UTC().Add(time.Minute))
+	if err := repo.Create(ctx, otp); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := repo.Consume(ctx, otp.ID()); err != nil {
+		t.Fatalf("first Consume failed: %v", err)
+	}
+	if err := repo.Consume(ctx, otp.ID()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second Consume, got %v", err)
+	}
+}
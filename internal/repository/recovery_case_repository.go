@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecoveryCaseModel is the GORM model for the recovery_cases table.
+type RecoveryCaseModel struct {
+	ID           uuid.UUID               `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID       *uuid.UUID              `gorm:"type:uuid;index"`
+	ClaimedEmail string                  `gorm:"type:varchar(255);not null;index"`
+	FullName     string                  `gorm:"type:varchar(255);not null"`
+	Phone        string                  `gorm:"type:varchar(20)"`
+	ContactEmail string                  `gorm:"type:varchar(255);not null"`
+	Details      string                  `gorm:"type:text"`
+	Status       identity.RecoveryStatus `gorm:"type:varchar(20);not null;index"`
+	ReviewedBy   *uuid.UUID              `gorm:"type:uuid"`
+	ReviewedAt   *time.Time
+	ReviewNote   string     `gorm:"type:text"`
+	TokenHash    *string    `gorm:"type:char(64);uniqueIndex"`
+	ExpiresAt    *time.Time `gorm:""`
+	CompletedAt  *time.Time `gorm:""`
+	CreatedAt    time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (RecoveryCaseModel) TableName() string {
+	return "recovery_cases"
+}
+
+// toDomain converts a RecoveryCaseModel to a domain RecoveryCase.
+// Only the token hash is stored, so the caller supplies the raw token if it has one.
+func (m *RecoveryCaseModel) toDomain(token string) *identity.RecoveryCase {
+	return identity.ReconstructRecoveryCase(
+		m.ID,
+		m.UserID,
+		m.ClaimedEmail,
+		m.FullName,
+		m.Phone,
+		m.ContactEmail,
+		m.Details,
+		m.Status,
+		m.ReviewedBy,
+		m.ReviewedAt,
+		m.ReviewNote,
+		token,
+		m.ExpiresAt,
+		m.CompletedAt,
+		m.CreatedAt,
+	)
+}
+
+// RecoveryEventModel is the GORM model for the recovery_events table.
+type RecoveryEventModel struct {
+	ID        uuid.UUID               `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CaseID    uuid.UUID               `gorm:"type:uuid;not null;index"`
+	UserID    *uuid.UUID              `gorm:"type:uuid;index"`
+	Action    identity.RecoveryAction `gorm:"type:varchar(20);not null"`
+	ActorID   *uuid.UUID              `gorm:"type:uuid"`
+	Note      string                  `gorm:"type:text"`
+	CreatedAt time.Time               `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (RecoveryEventModel) TableName() string {
+	return "recovery_events"
+}
+
+// fromDomainRecoveryEvent converts a domain RecoveryEvent to a RecoveryEventModel.
+func fromDomainRecoveryEvent(e *identity.RecoveryEvent) *RecoveryEventModel {
+	return &RecoveryEventModel{
+		ID:        e.ID(),
+		CaseID:    e.CaseID(),
+		UserID:    e.UserID(),
+		Action:    e.Action(),
+		ActorID:   e.ActorID(),
+		Note:      e.Note(),
+		CreatedAt: e.CreatedAt(),
+	}
+}
+
+// GormRecoveryCaseRepository is a GORM-based implementation of RecoveryCaseRepository.
+type GormRecoveryCaseRepository struct {
+	db     *gorm.DB
+	hasher *TokenHasher
+}
+
+// NewGormRecoveryCaseRepository creates a new GormRecoveryCaseRepository.
+func NewGormRecoveryCaseRepository(db *gorm.DB, hasher *TokenHasher) *GormRecoveryCaseRepository {
+	return &GormRecoveryCaseRepository{db: db, hasher: hasher}
+}
+
+// Create persists a newly filed case together with its first event.
+func (r *GormRecoveryCaseRepository) Create(ctx context.Context, recoveryCase *identity.RecoveryCase, event *identity.RecoveryEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model := &RecoveryCaseModel{
+			ID:           recoveryCase.ID(),
+			UserID:       recoveryCase.UserID(),
+			ClaimedEmail: recoveryCase.ClaimedEmail(),
+			FullName:     recoveryCase.FullName(),
+			Phone:        recoveryCase.Phone(),
+			ContactEmail: recoveryCase.ContactEmail(),
+			Details:      recoveryCase.Details(),
+			Status:       recoveryCase.Status(),
+			CreatedAt:    recoveryCase.CreatedAt(),
+		}
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		return tx.Create(fromDomainRecoveryEvent(event)).Error
+	})
+}
+
+// FindByID retrieves a case by its ID.
+func (r *GormRecoveryCaseRepository) FindByID(ctx context.Context, id uuid.UUID) (*identity.RecoveryCase, error) {
+	var model RecoveryCaseModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(""), nil
+}
+
+// FindByToken retrieves a case by its raw recovery token, whatever its status.
+func (r *GormRecoveryCaseRepository) FindByToken(ctx context.Context, token string) (*identity.RecoveryCase, error) {
+	var model RecoveryCaseModel
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", r.hasher.Hash(token)).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(token), nil
+}
+
+// CountOpenForEmail counts the pending and approved cases claiming an email.
+func (r *GormRecoveryCaseRepository) CountOpenForEmail(ctx context.Context, claimedEmail identity.Email) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&RecoveryCaseModel{}).
+		Where("claimed_email = ? AND status IN ?", claimedEmail.String(), []identity.RecoveryStatus{identity.RecoveryPending, identity.RecoveryApproved}).
+		Count(&count).Error
+	return count, err
+}
+
+// List returns cases newest first, optionally filtered by status and user.
+func (r *GormRecoveryCaseRepository) List(ctx context.Context, status identity.RecoveryStatus, userID *uuid.UUID, page, limit int) ([]*identity.RecoveryCase, int64, error) {
+	query := r.db.WithContext(ctx).Model(&RecoveryCaseModel{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []RecoveryCaseModel
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	cases := make([]*identity.RecoveryCase, len(models))
+	for i := range models {
+		cases[i] = models[i].toDomain("")
+	}
+	return cases, total, nil
+}
+
+// ListEvents returns a case's history, oldest first.
+func (r *GormRecoveryCaseRepository) ListEvents(ctx context.Context, caseID uuid.UUID) ([]*identity.RecoveryEvent, error) {
+	var models []RecoveryEventModel
+	if err := r.db.WithContext(ctx).Where("case_id = ?", caseID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*identity.RecoveryEvent, len(models))
+	for i, m := range models {
+		events[i] = identity.ReconstructRecoveryEvent(m.ID, m.CaseID, m.UserID, m.Action, m.ActorID, m.Note, m.CreatedAt)
+	}
+	return events, nil
+}
+
+// Review persists an approval or rejection. The pending guard makes the review
+// single-shot under concurrency; approval ends the user's sessions in the same transaction.
+func (r *GormRecoveryCaseRepository) Review(ctx context.Context, recoveryCase *identity.RecoveryCase, event *identity.RecoveryEvent) error {
+	updates := map[string]interface{}{
+		"status":      recoveryCase.Status(),
+		"reviewed_by": recoveryCase.ReviewedBy(),
+		"reviewed_at": recoveryCase.ReviewedAt(),
+		"review_note": recoveryCase.ReviewNote(),
+	}
+	if recoveryCase.Token() != "" {
+		updates["token_hash"] = r.hasher.Hash(recoveryCase.Token())
+		updates["expires_at"] = recoveryCase.ExpiresAt()
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RecoveryCaseModel{}).
+			Where("id = ? AND status = ?", recoveryCase.ID(), identity.RecoveryPending).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return identity.ErrRecoveryCaseNotPending
+		}
+
+		if recoveryCase.Status() == identity.RecoveryApproved {
+			if err := revokeAllSessions(tx, *recoveryCase.UserID()); err != nil {
+				return err
+			}
+		}
+		return tx.Create(fromDomainRecoveryEvent(event)).Error
+	})
+}
+
+// Complete marks the case completed, moves the user to the confirmed contact email
+// with the new password and ends their sessions, all in one transaction.
+func (r *GormRecoveryCaseRepository) Complete(ctx context.Context, recoveryCase *identity.RecoveryCase, passwordHash string, event *identity.RecoveryEvent) error {
+	userID := *recoveryCase.UserID()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RecoveryCaseModel{}).
+			Where("id = ? AND status = ? AND expires_at > ?", recoveryCase.ID(), identity.RecoveryApproved, time.Now().UTC()).
+			Updates(map[string]interface{}{
+				"status":       recoveryCase.Status(),
+				"completed_at": recoveryCase.CompletedAt(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return identity.ErrRecoveryLinkInvalid
+		}
+
+		result = tx.Model(&UserModel{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"email":           recoveryCase.ContactEmail(),
+				"canonical_email": recoveryCase.ContactEmailVO().Canonical(),
+				"is_verified":     true,
+				"password_hash":   passwordHash,
+				"version":         gorm.Expr("version + 1"),
+				"updated_at":      time.Now().UTC(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+
+		if err := revokeAllSessions(tx, userID); err != nil {
+			return err
+		}
+		return tx.Create(fromDomainRecoveryEvent(event)).Error
+	})
+}
@@ -0,0 +1,90 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestRecoveryCaseRepo_ApproveRevokesSessionsAndCompleteIsSingleUse(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE recovery_cases CASCADE").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	userID := seedTestUser(t, db)
+	adminID := seedTestUser(t, db)
+
+	repo := repository.NewGormRecoveryCaseRepository(db, repository.NewTokenHasher("test-pepper"))
+	sessionRepo := repository.NewGormSessionRepository(db)
+	ctx := context.Background()
+
+	session := identity.NewSession(userID, "Pixel 8", "android", "test-agent", "127.0.0.1", []string{"pwd"})
+	if err := sessionRepo.Save(ctx, session); err != nil {
+		t.Fatalf("session Save failed: %v", err)
+	}
+
+	contact := "recovered-" + uuid.New().String() + "@example.com"
+	recoveryCase, err := identity.NewRecoveryCase(&userID, "lost@kilat.my", "Recovery Test User", "", contact, "details")
+	if err != nil {
+		t.Fatalf("NewRecoveryCase failed: %v", err)
+	}
+	if err := repo.Create(ctx, recoveryCase, identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionFiled, nil, "")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	token := "recovery-token-" + uuid.New().String()
+	if err := recoveryCase.Approve(adminID, "verified by phone", token, time.Now().UTC().Add(time.Hour)); err != nil {
+		t.Fatalf("Approve (domain) failed: %v", err)
+	}
+	if err := repo.Review(ctx, recoveryCase, identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionApproved, &adminID, "")); err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if err := repo.Review(ctx, recoveryCase, identity.NewRecoveryEvent(recoveryCase, identity.RecoveryActionApproved, &adminID, "")); !errors.Is(err, identity.ErrRecoveryCaseNotPending) {
+		t.Fatalf("expected ErrRecoveryCaseNotPending on second review, got %v", err)
+	}
+
+	active, err := sessionRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("expected approval to revoke all sessions, %d still active", len(active))
+	}
+
+	found, err := repo.FindByToken(ctx, token)
+	if err != nil {
+		t.Fatalf("FindByToken failed: %v", err)
+	}
+	if err := found.Complete(); err != nil {
+		t.Fatalf("Complete (domain) failed: %v", err)
+	}
+	if err := repo.Complete(ctx, found, "$2a$10$newhash", identity.NewRecoveryEvent(found, identity.RecoveryActionCompleted, nil, "")); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if err := repo.Complete(ctx, found, "$2a$10$otherhash", identity.NewRecoveryEvent(found, identity.RecoveryActionCompleted, nil, "")); !errors.Is(err, identity.ErrRecoveryLinkInvalid) {
+		t.Fatalf("expected ErrRecoveryLinkInvalid on reuse, got %v", err)
+	}
+
+	var user repository.UserModel
+	if err := db.Where("id = ?", userID).First(&user).Error; err != nil {
+		t.Fatalf("load user failed: %v", err)
+	}
+	if user.Email != contact || !user.IsVerified || user.PasswordHash != "$2a$10$newhash" {
+		t.Errorf("expected user moved to %s with new password, got email=%s verified=%v", contact, user.Email, user.IsVerified)
+	}
+
+	events, err := repo.ListEvents(ctx, recoveryCase.ID())
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("expected filed, approved and completed events, got %d", len(events))
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RequestCounterModel is the GORM model for the request_counters table.
+type RequestCounterModel struct {
+	Scope        string    `gorm:"type:varchar(40);primaryKey"`
+	Key          string    `gorm:"type:varchar(255);primaryKey"`
+	Count        int       `gorm:"not null;default:0"`
+	WindowEndsAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM.
+func (RequestCounterModel) TableName() string {
+	return "request_counters"
+}
+
+// GormRequestCounterRepository is a GORM-based implementation of RequestCounterRepository.
+type GormRequestCounterRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRequestCounterRepository creates a new GormRequestCounterRepository.
+func NewGormRequestCounterRepository(db *gorm.DB) *GormRequestCounterRepository {
+	return &GormRequestCounterRepository{db: db}
+}
+
+// Increment counts a request with a single upsert, so concurrent requests on
+// different replicas are all counted.
+func (r *GormRequestCounterRepository) Increment(ctx context.Context, scope, key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now().UTC()
+	var model RequestCounterModel
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO request_counters (scope, key, count, window_ends_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			count = CASE WHEN request_counters.window_ends_at <= ? THEN 1 ELSE request_counters.count + 1 END,
+			window_ends_at = CASE WHEN request_counters.window_ends_at <= ? THEN EXCLUDED.window_ends_at ELSE request_counters.window_ends_at END
+		RETURNING scope, key, count, window_ends_at`,
+		scope, key, now.Add(window), now, now,
+	).Scan(&model).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return model.Count, model.WindowEndsAt, nil
+}
@@ -0,0 +1,42 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+)
+
+func TestRequestCounterRepo_CountsWithinWindowAndStartsOver(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE request_counters").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+
+	repo := repository.NewGormRequestCounterRepository(db)
+	ctx := context.Background()
+
+	for want := 1; want <= 3; want++ {
+		count, _, err := repo.Increment(ctx, "forgot_password_ip", "203.0.113.7", time.Hour)
+		if err != nil {
+			t.Fatalf("Increment failed: %v", err)
+		}
+		if count != want {
+			t.Fatalf("expected count %d, got %d", want, count)
+		}
+	}
+
+	if err := db.Exec("UPDATE request_counters SET window_ends_at = ?", time.Now().UTC().Add(-time.Second)).Error; err != nil {
+		t.Fatalf("expire window failed: %v", err)
+	}
+	count, windowEndsAt, err := repo.Increment(ctx, "forgot_password_ip", "203.0.113.7", time.Hour)
+	if err != nil {
+		t.Fatalf("Increment failed: %v", err)
+	}
+	if count != 1 || !windowEndsAt.After(time.Now().UTC()) {
+		t.Errorf("expected a fresh window, got count %d ending %s", count, windowEndsAt)
+	}
+}
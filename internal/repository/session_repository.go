@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// SessionModel is the GORM model for the sessions table.
+type SessionModel struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID      uuid.UUID      `gorm:"type:uuid;not null;index"`
+	DeviceName  string         `gorm:"type:varchar(100)"`
+	Platform    string         `gorm:"type:varchar(30)"`
+	UserAgent   string         `gorm:"type:text"`
+	IPAddress   string         `gorm:"type:varchar(45)"`
+	AuthMethods pq.StringArray `gorm:"type:text[];not null;default:'{}'"`
+	CreatedAt   time.Time      `gorm:"not null;default:now()"`
+	LastSeenAt  time.Time      `gorm:"not null;default:now()"`
+	RevokedAt   *time.Time     `gorm:""`
+}
+
+// TableName specifies the table name for GORM.
+func (SessionModel) TableName() string {
+	return "sessions"
+}
+
+// toDomain converts a SessionModel to a domain Session.
+func (m *SessionModel) toDomain() *identity.Session {
+	return identity.ReconstructSession(
+		m.ID,
+		m.UserID,
+		m.DeviceName,
+		m.Platform,
+		m.UserAgent,
+		m.IPAddress,
+		[]string(m.AuthMethods),
+		m.CreatedAt,
+		m.LastSeenAt,
+		m.RevokedAt,
+	)
+}
+
+// fromDomainSession converts a domain Session to a SessionModel.
+func fromDomainSession(s *identity.Session) *SessionModel {
+	return &SessionModel{
+		ID:          s.ID(),
+		UserID:      s.UserID(),
+		DeviceName:  s.DeviceName(),
+		Platform:    s.Platform(),
+		UserAgent:   s.UserAgent(),
+		IPAddress:   s.IPAddress(),
+		AuthMethods: pq.StringArray(s.AuthMethods()),
+		CreatedAt:   s.CreatedAt(),
+		LastSeenAt:  s.LastSeenAt(),
+		RevokedAt:   s.RevokedAt(),
+	}
+}
+
+// GormSessionRepository is a GORM-based implementation of SessionRepository.
+type GormSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSessionRepository creates a new GormSessionRepository.
+func NewGormSessionRepository(db *gorm.DB) *GormSessionRepository {
+	return &GormSessionRepository{db: db}
+}
+
+// Save persists a new session to the database.
+func (r *GormSessionRepository) Save(ctx context.Context, session *identity.Session) error {
+	model := fromDomainSession(session)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+// FindByID retrieves a session by its ID.
+func (r *GormSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*identity.Session, error) {
+	var model SessionModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// ListActiveForUser returns the user's non-revoked sessions, most recently seen first.
+func (r *GormSessionRepository) ListActiveForUser(ctx context.Context, userID uuid.UUID) ([]*identity.Session, error) {
+	var models []SessionModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_seen_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*identity.Session, len(models))
+	for i := range models {
+		sessions[i] = models[i].toDomain()
+	}
+	return sessions, nil
+}
+
+// Touch records that the session was just used from the given client.
+func (r *GormSessionRepository) Touch(ctx context.Context, id uuid.UUID, userAgent, ipAddress string) error {
+	return r.db.WithContext(ctx).
+		Model(&SessionModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"user_agent":   userAgent,
+			"ip_address":   ipAddress,
+			"last_seen_at": time.Now().UTC(),
+		}).Error
+}
+
+// Revoke ends a single session and revokes its refresh token family in one transaction.
+func (r *GormSessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&SessionModel{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+		return tx.Model(&RefreshTokenModel{}).
+			Where("family_id = ? AND revoked = ?", id, false).
+			Update("revoked", true).Error
+	})
+}
+
+// RevokeAllForUser ends every session of the user and revokes all of their refresh tokens.
+func (r *GormSessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return revokeAllSessions(tx, userID)
+	})
+}
+
+// revokeAllSessions ends every session of the user and revokes their refresh tokens
+// inside the caller's transaction, for other repositories whose writes must end the
+// user's sessions atomically.
+func revokeAllSessions(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Model(&SessionModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now().UTC()).Error; err != nil {
+		return err
+	}
+	return tx.Model(&RefreshTokenModel{}).
+		Where("user_id = ? AND revoked = ?", userID, false).
+		Update("revoked", true).Error
+}
+
+// RevokeAllForUserExcept ends every session of the user other than keepID.
+func (r *GormSessionRepository) RevokeAllForUserExcept(ctx context.Context, userID, keepID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&SessionModel{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+			Update("revoked_at", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+		return tx.Model(&RefreshTokenModel{}).
+			Where("user_id = ? AND family_id <> ? AND revoked = ?", userID, keepID, false).
+			Update("revoked", true).Error
+	})
+}
@@ -0,0 +1,266 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// seedSession saves a session for the user with a refresh token in its family.
+func seedSession(t *testing.T, db *gorm.DB, userID uuid.UUID, deviceName string) (*identity.Session, *identity.RefreshToken) {
+	t.Helper()
+	ctx := context.Background()
+	session := identity.NewSession(userID, deviceName, "android", "test-agent", "127.0.0.1", []string{"pwd"})
+	if err := repository.NewGormSessionRepository(db).Save(ctx, session); err != nil {
+		t.Fatalf("session Save failed: %v", err)
+	}
+	token := identity.NewRefreshToken(userID, session.ID(), "session-test-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := repository.NewGormTokenRepository(db, repository.NewTokenHasher("test-pepper")).Save(ctx, token); err != nil {
+		t.Fatalf("token Save failed: %v", err)
+	}
+	return session, token
+}
+
+// refreshTokenRevoked reports whether the stored refresh token has been revoked.
+func refreshTokenRevoked(t *testing.T, db *gorm.DB, token *identity.RefreshToken) bool {
+	t.Helper()
+	found, err := repository.NewGormTokenRepository(db, repository.NewTokenHasher("test-pepper")).FindByToken(context.Background(), token.Token())
+	if err != nil {
+		t.Fatalf("FindByToken failed: %v", err)
+	}
+	return found.Revoked()
+}
+
+func TestSessionRepo_SaveAndListActive(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	repo := repository.NewGormSessionRepository(db)
+	ctx := context.Background()
+
+	older, _ := seedSession(t, db, userID, "Pixel 8")
+	newer, _ := seedSession(t, db, userID, "iPad")
+	if err := repo.Touch(ctx, newer.ID(), "Kilat/2.3", "198.51.100.4"); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, older.ID())
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if found.UserID() != userID || found.DeviceName() != "Pixel 8" || !found.IsActive() {
+		t.Errorf("unexpected session: user %s, device %q, active %v", found.UserID(), found.DeviceName(), found.IsActive())
+	}
+	if len(found.AuthMethods()) != 1 || found.AuthMethods()[0] != "pwd" {
+		t.Errorf("expected auth methods [pwd], got %v", found.AuthMethods())
+	}
+
+	active, err := repo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 2 {
+		t.Fatalf("expected 2 active sessions, got %d", len(active))
+	}
+	if active[0].ID() != newer.ID() {
+		t.Errorf("expected the most recently seen session first, got %s", active[0].DeviceName())
+	}
+}
+
+func TestSessionRepo_FindByID_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewGormSessionRepository(db)
+
+	if _, err := repo.FindByID(context.Background(), uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSessionRepo_Touch_UpdatesLastSeen(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	repo := repository.NewGormSessionRepository(db)
+	ctx := context.Background()
+
+	session, _ := seedSession(t, db, userID, "Pixel 8")
+	if err := repo.Touch(ctx, session.ID(), "Kilat/2.3", "198.51.100.4"); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, session.ID())
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if !found.LastSeenAt().After(session.LastSeenAt()) {
+		t.Errorf("expected last_seen_at to move past %s, got %s", session.LastSeenAt(), found.LastSeenAt())
+	}
+	if found.UserAgent() != "Kilat/2.3" || found.IPAddress() != "198.51.100.4" {
+		t.Errorf("expected the latest client to be recorded, got %q from %q", found.UserAgent(), found.IPAddress())
+	}
+}
+
+func TestSessionRepo_Revoke_EndsOneSessionAndItsTokens(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	repo := repository.NewGormSessionRepository(db)
+	ctx := context.Background()
+
+	revoked, revokedToken := seedSession(t, db, userID, "Pixel 8")
+	kept, keptToken := seedSession(t, db, userID, "iPad")
+
+	if err := repo.Revoke(ctx, revoked.ID()); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	active, err := repo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 1 || active[0].ID() != kept.ID() {
+		t.Errorf("expected only the other session to stay active, got %d active", len(active))
+	}
+	if !refreshTokenRevoked(t, db, revokedToken) {
+		t.Error("expected the revoked session's refresh token to be revoked")
+	}
+	if refreshTokenRevoked(t, db, keptToken) {
+		t.Error("expected the other session's refresh token to stay valid")
+	}
+}
+
+func TestSessionRepo_RevokeAllForUserExcept_KeepsCurrentSession(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	repo := repository.NewGormSessionRepository(db)
+	ctx := context.Background()
+
+	current, currentToken := seedSession(t, db, userID, "Pixel 8")
+	_, otherToken := seedSession(t, db, userID, "iPad")
+	_, thirdToken := seedSession(t, db, userID, "Chrome")
+
+	if err := repo.RevokeAllForUserExcept(ctx, userID, current.ID()); err != nil {
+		t.Fatalf("RevokeAllForUserExcept failed: %v", err)
+	}
+
+	active, err := repo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 1 || active[0].ID() != current.ID() {
+		t.Errorf("expected only the current session to stay active, got %d active", len(active))
+	}
+	if refreshTokenRevoked(t, db, currentToken) {
+		t.Error("expected the current session's refresh token to stay valid")
+	}
+	if !refreshTokenRevoked(t, db, otherToken) || !refreshTokenRevoked(t, db, thirdToken) {
+		t.Error("expected the other sessions' refresh tokens to be revoked")
+	}
+}
+
+func TestSessionRepo_RevokeAllForUser_LeavesOtherUsersAlone(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	otherUserID := seedTestUser(t, db)
+	repo := repository.NewGormSessionRepository(db)
+	ctx := context.Background()
+
+	_, token := seedSession(t, db, userID, "Pixel 8")
+	seedSession(t, db, userID, "iPad")
+	_, otherUsersToken := seedSession(t, db, otherUserID, "Galaxy S24")
+
+	if err := repo.RevokeAllForUser(ctx, userID); err != nil {
+		t.Fatalf("RevokeAllForUser failed: %v", err)
+	}
+
+	active, err := repo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("expected no active sessions, got %d", len(active))
+	}
+	if !refreshTokenRevoked(t, db, token) {
+		t.Error("expected the user's refresh tokens to be revoked")
+	}
+
+	othersActive, err := repo.ListActiveForUser(ctx, otherUserID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(othersActive) != 1 || refreshTokenRevoked(t, db, otherUsersToken) {
+		t.Error("expected another user's session and refresh token to be untouched")
+	}
+}
+
+// Password resets end the user's sessions through revokeAllSessions, in the same
+// transaction as the password change.
+func TestSessionRepo_RevokeAllSessions_InPasswordResetTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	sessionRepo := repository.NewGormSessionRepository(db)
+	resetRepo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	_, token := seedSession(t, db, userID, "Pixel 8")
+	reset := identity.NewPasswordReset(userID, "session-reset-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := resetRepo.Create(ctx, reset); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := resetRepo.MarkUsedAndUpdatePassword(ctx, reset.ID(), userID, "$2a$10$newplaceholder"); err != nil {
+		t.Fatalf("MarkUsedAndUpdatePassword failed: %v", err)
+	}
+
+	active, err := sessionRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("expected the reset to end every session, %d still active", len(active))
+	}
+	if !refreshTokenRevoked(t, db, token) {
+		t.Error("expected the reset to revoke the refresh tokens")
+	}
+}
+
+// A reset that fails inside the transaction must leave the sessions it would have ended.
+func TestSessionRepo_RevokeAllSessions_RolledBackWithFailedReset(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+	sessionRepo := repository.NewGormSessionRepository(db)
+	resetRepo := repository.NewGormPasswordResetRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	_, token := seedSession(t, db, userID, "Pixel 8")
+	reset := identity.NewPasswordReset(userID, "session-reset-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := resetRepo.Create(ctx, reset); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// The user update matches no row, so the transaction rolls back after marking the token used.
+	if err := resetRepo.MarkUsedAndUpdatePassword(ctx, reset.ID(), uuid.New(), "$2a$10$newplaceholder"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	active, err := sessionRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListActiveForUser failed: %v", err)
+	}
+	if len(active) != 1 || refreshTokenRevoked(t, db, token) {
+		t.Error("expected the session and refresh token to survive a failed reset")
+	}
+	found, err := resetRepo.FindByToken(ctx, reset.Token())
+	if err != nil {
+		t.Fatalf("FindByToken failed: %v", err)
+	}
+	if found.UsedAt() != nil {
+		t.Error("expected the reset token to stay unused after the rollback")
+	}
+}
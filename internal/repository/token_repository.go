@@ -13,12 +13,14 @@ import (
 
 // RefreshTokenModel is the GORM model for the refresh_tokens table.
 type RefreshTokenModel struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
-	Token     string    `gorm:"type:text;uniqueIndex;not null"`
-	ExpiresAt time.Time `gorm:"not null"`
-	Revoked   bool      `gorm:"default:false"`
-	CreatedAt time.Time `gorm:"not null;default:now()"`
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FamilyID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:""`
+	Revoked   bool       `gorm:"default:false"`
+	CreatedAt time.Time  `gorm:"not null;default:now()"`
 }
 
 // TableName specifies the table name for GORM.
@@ -27,55 +29,92 @@ func (RefreshTokenModel) TableName() string {
 }
 
 // toDomain converts a RefreshTokenModel to a domain RefreshToken.
-func (m *RefreshTokenModel) toDomain() *identity.RefreshToken {
+// Only the token hash is stored, so the caller supplies the raw token it looked up by.
+func (m *RefreshTokenModel) toDomain(token string) *identity.RefreshToken {
 	return identity.ReconstructRefreshToken(
 		m.ID,
 		m.UserID,
-		m.Token,
+		m.FamilyID,
+		token,
 		m.ExpiresAt,
+		m.UsedAt,
 		m.Revoked,
 		m.CreatedAt,
 	)
 }
 
 // fromDomainRefreshToken converts a domain RefreshToken to a RefreshTokenModel.
-func fromDomainRefreshToken(t *identity.RefreshToken) *RefreshTokenModel {
+func fromDomainRefreshToken(t *identity.RefreshToken, tokenHash string) *RefreshTokenModel {
 	return &RefreshTokenModel{
 		ID:        t.ID(),
 		UserID:    t.UserID(),
-		Token:     t.Token(),
+		FamilyID:  t.FamilyID(),
+		TokenHash: tokenHash,
 		ExpiresAt: t.ExpiresAt(),
+		UsedAt:    t.UsedAt(),
 		Revoked:   t.Revoked(),
 		CreatedAt: t.CreatedAt(),
 	}
 }
 
 // GormTokenRepository is a GORM-based implementation of TokenRepository.
+// Tokens are stored and looked up by their keyed hash only.
 type GormTokenRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	hasher *TokenHasher
 }
 
 // NewGormTokenRepository creates a new GormTokenRepository.
-func NewGormTokenRepository(db *gorm.DB) *GormTokenRepository {
-	return &GormTokenRepository{db: db}
+func NewGormTokenRepository(db *gorm.DB, hasher *TokenHasher) *GormTokenRepository {
+	return &GormTokenRepository{db: db, hasher: hasher}
 }
 
 // Save persists a new refresh token to the database.
 func (r *GormTokenRepository) Save(ctx context.Context, token *identity.RefreshToken) error {
-	model := fromDomainRefreshToken(token)
+	model := fromDomainRefreshToken(token, r.hasher.Hash(token.Token()))
 	return r.db.WithContext(ctx).Create(model).Error
 }
 
 // FindByToken retrieves a refresh token by its token string.
 func (r *GormTokenRepository) FindByToken(ctx context.Context, token string) (*identity.RefreshToken, error) {
 	var model RefreshTokenModel
-	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&model).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", r.hasher.Hash(token)).First(&model).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
-	return model.toDomain(), nil
+	return model.toDomain(token), nil
+}
+
+// Rotate marks the consumed token as used and inserts its successor in a single transaction.
+// The used_at guard in the UPDATE makes concurrent rotations of the same token race-safe:
+// only one caller can flip used_at, every other caller gets identity.ErrRefreshTokenReused.
+// A token revoked before it was used gets identity.ErrRefreshTokenRevoked instead.
+func (r *GormTokenRepository) Rotate(ctx context.Context, consumedID uuid.UUID, next *identity.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RefreshTokenModel{}).
+			Where("id = ? AND used_at IS NULL AND revoked = ?", consumedID, false).
+			Update("used_at", time.Now().UTC())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var consumed RefreshTokenModel
+			if err := tx.Select("used_at").Where("id = ?", consumedID).First(&consumed).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrNotFound
+				}
+				return err
+			}
+			if consumed.UsedAt != nil {
+				return identity.ErrRefreshTokenReused
+			}
+			return identity.ErrRefreshTokenRevoked
+		}
+
+		return tx.Create(fromDomainRefreshToken(next, r.hasher.Hash(next.Token()))).Error
+	})
 }
 
 // RevokeAllForUser revokes all refresh tokens belonging to a specific user.
@@ -0,0 +1,95 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestTokenRepo_Rotate_MarksConsumedAndSavesSuccessor(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormTokenRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	first := identity.NewRefreshToken(userID, uuid.New(), "rotate-first-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	next := first.Rotate("rotate-next-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := repo.Rotate(ctx, first.ID(), next); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	consumed, err := repo.FindByToken(ctx, first.Token())
+	if err != nil {
+		t.Fatalf("FindByToken(consumed) returned error: %v", err)
+	}
+	if !consumed.IsUsed() {
+		t.Error("expected consumed token to be marked used")
+	}
+
+	successor, err := repo.FindByToken(ctx, next.Token())
+	if err != nil {
+		t.Fatalf("FindByToken(successor) returned error: %v", err)
+	}
+	if successor.FamilyID() != first.FamilyID() {
+		t.Errorf("expected successor family %s, got %s", first.FamilyID(), successor.FamilyID())
+	}
+}
+
+func TestTokenRepo_Rotate_AlreadyUsedReturnsReused(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormTokenRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	first := identity.NewRefreshToken(userID, uuid.New(), "reuse-first-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := repo.Rotate(ctx, first.ID(), first.Rotate("reuse-a-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))); err != nil {
+		t.Fatalf("first Rotate failed: %v", err)
+	}
+
+	err := repo.Rotate(ctx, first.ID(), first.Rotate("reuse-b-"+uuid.NewString(), time.Now().UTC().Add(time.Hour)))
+	if !errors.Is(err, identity.ErrRefreshTokenReused) {
+		t.Fatalf("expected identity.ErrRefreshTokenReused, got %v", err)
+	}
+}
+
+// A logout that lands between the lookup and the rotation is not a replay.
+func TestTokenRepo_Rotate_RevokedReturnsRevoked(t *testing.T) {
+	db := setupTestDB(t)
+	userID := seedTestUser(t, db)
+
+	repo := repository.NewGormTokenRepository(db, repository.NewTokenHasher("test-pepper"))
+	ctx := context.Background()
+
+	first := identity.NewRefreshToken(userID, uuid.New(), "revoked-first-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := repo.RevokeAllForUser(ctx, userID); err != nil {
+		t.Fatalf("RevokeAllForUser failed: %v", err)
+	}
+
+	next := first.Rotate("revoked-next-"+uuid.NewString(), time.Now().UTC().Add(time.Hour))
+	err := repo.Rotate(ctx, first.ID(), next)
+	if !errors.Is(err, identity.ErrRefreshTokenRevoked) {
+		t.Fatalf("expected identity.ErrRefreshTokenRevoked, got %v", err)
+	}
+	if _, err := repo.FindByToken(ctx, next.Token()); err == nil {
+		t.Error("expected no successor to be saved for a revoked token")
+	}
+}
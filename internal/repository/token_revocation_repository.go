@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TokenRevocationModel is the GORM model for the token_revocations table.
+type TokenRevocationModel struct {
+	Scope     identity.TokenRevocationScope `gorm:"type:varchar(20);primaryKey"`
+	Key       string                        `gorm:"type:varchar(64);primaryKey"`
+	UserID    uuid.UUID                     `gorm:"type:uuid;not null;index"`
+	RevokedAt time.Time                     `gorm:"not null"`
+	ExpiresAt time.Time                     `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM.
+func (TokenRevocationModel) TableName() string {
+	return "token_revocations"
+}
+
+// GormTokenRevocationRepository is a GORM-based implementation of TokenRevocationRepository.
+type GormTokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTokenRevocationRepository creates a new GormTokenRevocationRepository.
+func NewGormTokenRevocationRepository(db *gorm.DB) *GormTokenRevocationRepository {
+	return &GormTokenRevocationRepository{db: db}
+}
+
+// Create stores a revocation with a single upsert; revoking the same scope and key
+// again keeps the later revocation and expiry times.
+func (r *GormTokenRevocationRepository) Create(ctx context.Context, revocation *identity.TokenRevocation) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO token_revocations (scope, key, user_id, revoked_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			revoked_at = GREATEST(token_revocations.revoked_at, EXCLUDED.revoked_at),
+			expires_at = GREATEST(token_revocations.expires_at, EXCLUDED.expires_at)`,
+		revocation.Scope(), revocation.Key(), revocation.UserID(), revocation.RevokedAt(), revocation.ExpiresAt(),
+	).Error
+}
+
+// ListActive returns the revocations that have not expired at the given time.
+func (r *GormTokenRevocationRepository) ListActive(ctx context.Context, now time.Time) ([]*identity.TokenRevocation, error) {
+	var models []TokenRevocationModel
+	if err := r.db.WithContext(ctx).Where("expires_at > ?", now).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	revocations := make([]*identity.TokenRevocation, len(models))
+	for i, m := range models {
+		revocations[i] = identity.ReconstructTokenRevocation(m.Scope, m.Key, m.UserID, m.RevokedAt, m.ExpiresAt)
+	}
+	return revocations, nil
+}
+
+// DeleteExpired removes revocations that expired before the given time.
+func (r *GormTokenRevocationRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&TokenRevocationModel{}).Error
+}
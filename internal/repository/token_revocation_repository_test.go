@@ -0,0 +1,55 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestTokenRevocationRepo_ListsActiveAndDeletesExpired(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("TRUNCATE TABLE token_revocations").Error; err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+
+	repo := repository.NewGormTokenRevocationRepository(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	active := identity.NewTokenRevocation(identity.TokenRevocationScopeToken, uuid.New().String(), userID, time.Hour)
+	if err := repo.Create(ctx, active); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Revoking the same key twice must not fail.
+	if err := repo.Create(ctx, active); err != nil {
+		t.Fatalf("second Create failed: %v", err)
+	}
+	expired := identity.ReconstructTokenRevocation(identity.TokenRevocationScopeUser, userID.String(), userID,
+		time.Now().UTC().Add(-2*time.Hour), time.Now().UTC().Add(-time.Hour))
+	if err := repo.Create(ctx, expired); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	list, err := repo.ListActive(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("ListActive failed: %v", err)
+	}
+	if len(list) != 1 || list[0].Key() != active.Key() {
+		t.Fatalf("expected only the active revocation, got %d", len(list))
+	}
+
+	if err := repo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+		t.Fatalf("DeleteExpired failed: %v", err)
+	}
+	var count int64
+	db.Model(&repository.TokenRevocationModel{}).Count(&count)
+	if count != 1 {
+		t.Errorf("expected 1 revocation left, got %d", count)
+	}
+}
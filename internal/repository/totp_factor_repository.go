@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/domain"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TOTPFactorModel is the GORM model for the totp_factors table.
+type TOTPFactorModel struct {
+	UserID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	SecretEncrypted string     `gorm:"type:text;not null"`
+	ConfirmedAt     *time.Time `gorm:""`
+	LastUsedStep    int64      `gorm:"not null;default:0"`
+	CreatedAt       time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName specifies the table name for GORM.
+func (TOTPFactorModel) TableName() string {
+	return "totp_factors"
+}
+
+// GormTOTPFactorRepository is a GORM-based implementation of TOTPFactorRepository.
+// Secrets are sealed with a SecretBox before they are written.
+type GormTOTPFactorRepository struct {
+	db  *gorm.DB
+	box *SecretBox
+}
+
+// NewGormTOTPFactorRepository creates a new GormTOTPFactorRepository.
+func NewGormTOTPFactorRepository(db *gorm.DB, box *SecretBox) *GormTOTPFactorRepository {
+	return &GormTOTPFactorRepository{db: db, box: box}
+}
+
+// Save stores a pending enrollment, replacing any earlier pending one.
+func (r *GormTOTPFactorRepository) Save(ctx context.Context, factor *identity.TOTPFactor) error {
+	sealed, err := r.box.Seal(factor.Secret())
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var confirmed int64
+		if err := tx.Model(&TOTPFactorModel{}).
+			Where("user_id = ? AND confirmed_at IS NOT NULL", factor.UserID()).
+			Count(&confirmed).Error; err != nil {
+			return err
+		}
+		if confirmed > 0 {
+			return identity.ErrTOTPAlreadyEnabled
+		}
+
+		if err := tx.Where("user_id = ?", factor.UserID()).Delete(&TOTPFactorModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&TOTPFactorModel{
+			UserID:          factor.UserID(),
+			SecretEncrypted: sealed,
+			LastUsedStep:    factor.LastUsedStep(),
+			CreatedAt:       factor.CreatedAt(),
+		}).Error
+	})
+}
+
+// FindByUserID retrieves the user's enrollment, pending or confirmed.
+func (r *GormTOTPFactorRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*identity.TOTPFactor, error) {
+	var model TOTPFactorModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	secret, err := r.box.Open(model.SecretEncrypted)
+	if err != nil {
+		return nil, err
+	}
+	return identity.ReconstructTOTPFactor(model.UserID, secret, model.ConfirmedAt, model.LastUsedStep, model.CreatedAt), nil
+}
+
+// Confirm persists a confirmed enrollment. The guard keeps a concurrent
+// confirmation from being applied twice.
+func (r *GormTOTPFactorRepository) Confirm(ctx context.Context, factor *identity.TOTPFactor) error {
+	result := r.db.WithContext(ctx).
+		Model(&TOTPFactorModel{}).
+		Where("user_id = ? AND confirmed_at IS NULL", factor.UserID()).
+		Updates(map[string]interface{}{
+			"confirmed_at":   factor.ConfirmedAt(),
+			"last_used_step": factor.LastUsedStep(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return identity.ErrTOTPAlreadyEnabled
+	}
+	return nil
+}
+
+// RecordUse persists the last used step. The guard makes each step single-use
+// when the same code is presented concurrently.
+func (r *GormTOTPFactorRepository) RecordUse(ctx context.Context, factor *identity.TOTPFactor) error {
+	result := r.db.WithContext(ctx).
+		Model(&TOTPFactorModel{}).
+		Where("user_id = ? AND last_used_step < ?", factor.UserID(), factor.LastUsedStep()).
+		Update("last_used_step", factor.LastUsedStep())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return identity.ErrTOTPCodeReused
+	}
+	return nil
+}
+
+// Delete removes the user's enrollment, pending or confirmed.
+func (r *GormTOTPFactorRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&TOTPFactorModel{}).Error
+}
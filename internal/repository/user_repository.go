@@ -3,28 +3,37 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/Kilat-Pet-Delivery/lib-common/auth"
 	"github.com/Kilat-Pet-Delivery/lib-common/domain"
 	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 // UserModel is the GORM model for the users table.
 type UserModel struct {
-	ID           uuid.UUID     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Email        string        `gorm:"type:varchar(255);uniqueIndex;not null"`
-	Phone        string        `gorm:"type:varchar(20)"`
-	PasswordHash string        `gorm:"type:varchar(255);not null"`
-	FullName     string        `gorm:"type:varchar(255);not null"`
-	Role         auth.UserRole `gorm:"type:varchar(20);not null"`
-	IsVerified   bool          `gorm:"default:false"`
-	AvatarURL    string        `gorm:"type:text"`
-	Version      int64         `gorm:"not null;default:1"`
-	CreatedAt    time.Time     `gorm:"not null;default:now()"`
-	UpdatedAt    time.Time     `gorm:"not null;default:now()"`
+	ID              uuid.UUID              `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Email           string                 `gorm:"type:varchar(255);uniqueIndex;index:idx_users_email_lower,unique,expression:lower(email);not null"`
+	CanonicalEmail  string                 `gorm:"type:varchar(255);index"`
+	Phone           string                 `gorm:"type:varchar(20);index:idx_users_verified_phone,unique,where:phone_verified"`
+	PasswordHash    string                 `gorm:"type:varchar(255);not null"`
+	FullName        string                 `gorm:"type:varchar(255);not null"`
+	Role            auth.UserRole          `gorm:"type:varchar(20);not null"`
+	IsVerified      bool                   `gorm:"default:false"`
+	PhoneVerified   bool                   `gorm:"not null;default:false"`
+	AvatarURL       string                 `gorm:"type:text"`
+	Status          identity.AccountStatus `gorm:"type:varchar(20);not null;default:'active';index"`
+	StatusReason    string                 `gorm:"type:text"`
+	StatusChangedBy *uuid.UUID             `gorm:"type:uuid"`
+	StatusChangedAt time.Time              `gorm:"not null;default:now()"`
+	StatusExpiresAt *time.Time
+	Version         int64     `gorm:"not null;default:1"`
+	CreatedAt       time.Time `gorm:"not null;default:now()"`
+	UpdatedAt       time.Time `gorm:"not null;default:now()"`
 }
 
 // TableName specifies the table name for GORM.
@@ -42,7 +51,9 @@ func (m *UserModel) toDomain() *identity.User {
 		m.FullName,
 		m.Role,
 		m.IsVerified,
+		m.PhoneVerified,
 		m.AvatarURL,
+		identity.ReconstructAccountStanding(m.Status, m.StatusReason, m.StatusChangedBy, m.StatusChangedAt, m.StatusExpiresAt),
 		m.Version,
 		m.CreatedAt,
 		m.UpdatedAt,
@@ -51,18 +62,26 @@ func (m *UserModel) toDomain() *identity.User {
 
 // fromDomainUser converts a domain User to a UserModel.
 func fromDomainUser(u *identity.User) *UserModel {
+	standing := u.Standing()
 	return &UserModel{
-		ID:           u.ID(),
-		Email:        u.Email(),
-		Phone:        u.Phone(),
-		PasswordHash: u.PasswordHash(),
-		FullName:     u.FullName(),
-		Role:         u.Role(),
-		IsVerified:   u.IsVerified(),
-		AvatarURL:    u.AvatarURL(),
-		Version:      u.Version(),
-		CreatedAt:    u.CreatedAt(),
-		UpdatedAt:    u.UpdatedAt(),
+		ID:              u.ID(),
+		Email:           u.Email(),
+		CanonicalEmail:  u.EmailVO().Canonical(),
+		Phone:           u.Phone(),
+		PasswordHash:    u.PasswordHash(),
+		FullName:        u.FullName(),
+		Role:            u.Role(),
+		IsVerified:      u.IsVerified(),
+		PhoneVerified:   u.PhoneVerified(),
+		AvatarURL:       u.AvatarURL(),
+		Status:          standing.StoredStatus(),
+		StatusReason:    standing.Reason(),
+		StatusChangedBy: standing.ChangedBy(),
+		StatusChangedAt: standing.ChangedAt(),
+		StatusExpiresAt: standing.ExpiresAt(),
+		Version:         u.Version(),
+		CreatedAt:       u.CreatedAt(),
+		UpdatedAt:       u.UpdatedAt(),
 	}
 }
 
@@ -88,10 +107,12 @@ func (r *GormUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*ident
 	return model.toDomain(), nil
 }
 
-// FindByEmail retrieves a user by their email address.
-func (r *GormUserRepository) FindByEmail(ctx context.Context, email string) (*identity.User, error) {
+// FindByEmail retrieves a user by their email address, ignoring case. The lower(email)
+// expression matches the unique index, so it also finds rows stored before addresses
+// were lowercased.
+func (r *GormUserRepository) FindByEmail(ctx context.Context, email identity.Email) (*identity.User, error) {
 	var model UserModel
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&model).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("lower(email) = ?", email.String()).First(&model).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
 		}
@@ -100,18 +121,51 @@ func (r *GormUserRepository) FindByEmail(ctx context.Context, email string) (*id
 	return model.toDomain(), nil
 }
 
-// Save persists a new user to the database.
+// CountByCanonicalEmail counts the users sharing email's canonical form.
+func (r *GormUserRepository) CountByCanonicalEmail(ctx context.Context, email identity.Email) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&UserModel{}).
+		Where("canonical_email = ?", email.Canonical()).
+		Count(&count).Error
+	return count, err
+}
+
+// FindByVerifiedPhone retrieves the user whose verified phone number matches.
+// At most one user can hold a given verified number.
+func (r *GormUserRepository) FindByVerifiedPhone(ctx context.Context, phone string) (*identity.User, error) {
+	var model UserModel
+	if err := r.db.WithContext(ctx).Where("phone = ? AND phone_verified = ?", phone, true).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// Save persists a new user to the database. Losing a race for the email address
+// returns an already-exists error.
 func (r *GormUserRepository) Save(ctx context.Context, user *identity.User) error {
 	model := fromDomainUser(user)
-	return r.db.WithContext(ctx).Create(model).Error
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		if isEmailDuplicateError(err) {
+			return domain.NewAlreadyExistsError("User", "email", user.Email())
+		}
+		return err
+	}
+	return nil
 }
 
 // Update persists changes to an existing user with optimistic locking.
+// All columns are written so that cleared values (e.g. a lifted suspension's expiry) are persisted.
 func (r *GormUserRepository) Update(ctx context.Context, user *identity.User) error {
 	model := fromDomainUser(user)
 	result := r.db.WithContext(ctx).
 		Model(&UserModel{}).
 		Where("id = ? AND version = ?", model.ID, model.Version-1).
+		Select("*").
+		Omit("id", "created_at").
 		Updates(model)
 
 	if result.Error != nil {
@@ -159,6 +213,24 @@ func (r *GormUserRepository) UpdatePasswordHash(ctx context.Context, userID uuid
 	return nil
 }
 
+// ReplacePasswordHash updates the password hash only if it is still oldHash.
+func (r *GormUserRepository) ReplacePasswordHash(ctx context.Context, userID uuid.UUID, oldHash, newHash string) error {
+	result := r.db.WithContext(ctx).
+		Model(&UserModel{}).
+		Where("id = ? AND password_hash = ?", userID, oldHash).
+		Updates(map[string]interface{}{
+			"password_hash": newHash,
+			"updated_at":    time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 // CountByRole returns user counts grouped by role.
 func (r *GormUserRepository) CountByRole(ctx context.Context) (map[string]int64, error) {
 	type roleCount struct {
@@ -179,3 +251,17 @@ func (r *GormUserRepository) CountByRole(ctx context.Context) (map[string]int64,
 	}
 	return counts, nil
 }
+
+// isEmailDuplicateError returns true if the error is a Postgres unique-constraint
+// violation on the users email column.
+func isEmailDuplicateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pqErr *pq.Error
+	if ok := isPqError(err, &pqErr); ok {
+		return pqErr.Code == "23505" && strings.Contains(pqErr.Constraint, "email")
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "duplicate key") && strings.Contains(msg, "email")
+}
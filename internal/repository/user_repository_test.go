@@ -0,0 +1,55 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/domain/identity"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestUserRepo_EmailIsCaseInsensitiveAndCanonicalized(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewGormUserRepository(db)
+	ctx := context.Background()
+
+	local := "Case.Test." + strings.ReplaceAll(uuid.New().String(), "-", "")
+	user, err := identity.NewUser(local+"+signup@Gmail.com", "", "Case Test User", "$2a$10$placeholder", auth.RoleOwner)
+	if err != nil {
+		t.Fatalf("NewUser failed: %v", err)
+	}
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	lookup, _ := identity.NewEmail(strings.ToUpper(local) + "+SIGNUP@gmail.com")
+	found, err := repo.FindByEmail(ctx, lookup)
+	if err != nil {
+		t.Fatalf("FindByEmail with different case failed: %v", err)
+	}
+	if found.ID() != user.ID() {
+		t.Errorf("expected user %s, got %s", user.ID(), found.ID())
+	}
+
+	alias, _ := identity.NewEmail(strings.ReplaceAll(local, ".", "") + "@googlemail.com")
+	shared, err := repo.CountByCanonicalEmail(ctx, alias)
+	if err != nil {
+		t.Fatalf("CountByCanonicalEmail failed: %v", err)
+	}
+	if shared != 1 {
+		t.Errorf("expected the Gmail alias to share a canonical email with 1 account, got %d", shared)
+	}
+
+	twin, err := identity.NewUser(strings.ToUpper(local)+"+signup@gmail.com", "", "Case Test Twin", "$2a$10$placeholder", auth.RoleOwner)
+	if err != nil {
+		t.Fatalf("NewUser failed: %v", err)
+	}
+	if err := repo.Save(ctx, twin); err == nil {
+		t.Error("expected saving an address that differs only by case to fail")
+	}
+}
@@ -0,0 +1,239 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWS signing algorithms.
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// rsaKeyBits is the modulus size used when generating RSA signing keys.
+const rsaKeyBits = 2048
+
+// SigningKey is a private key used to sign tokens, identified by its key ID (kid).
+type SigningKey struct {
+	id        string
+	algorithm string
+	private   crypto.Signer
+}
+
+// NewSigningKey wraps a private key, inferring the algorithm from its type.
+func NewSigningKey(id string, private crypto.Signer) (*SigningKey, error) {
+	if id == "" {
+		return nil, errors.New("signing key ID is required")
+	}
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{id: id, algorithm: AlgRS256, private: private}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{id: id, algorithm: AlgEdDSA, private: private}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", private)
+	}
+}
+
+// GenerateSigningKey creates a fresh private key for the given algorithm.
+func GenerateSigningKey(id, algorithm string) (*SigningKey, error) {
+	switch algorithm {
+	case AlgRS256:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		return NewSigningKey(id, private)
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+		}
+		return NewSigningKey(id, private)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+// ID returns the key ID published as the JWT "kid" header.
+func (k *SigningKey) ID() string { return k.id }
+
+// Algorithm returns the JWS algorithm name of the key.
+func (k *SigningKey) Algorithm() string { return k.algorithm }
+
+// Public returns the public half of the key.
+func (k *SigningKey) Public() crypto.PublicKey { return k.private.Public() }
+
+// method returns the jwt signing method matching the key's algorithm.
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.algorithm == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// KeySet holds the active signing key plus retiring keys that are no longer used to
+// sign but are still accepted for verification and published in the JWKS, so tokens
+// minted before a rotation remain valid until they expire.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewKeySet creates a KeySet that signs with active and also verifies with retiring.
+func NewKeySet(active *SigningKey, retiring ...*SigningKey) *KeySet {
+	keys := map[string]*SigningKey{active.ID(): active}
+	for _, k := range retiring {
+		keys[k.ID()] = k
+	}
+	return &KeySet{active: active, keys: keys}
+}
+
+// Active returns the key new tokens are signed with.
+func (s *KeySet) Active() *SigningKey { return s.active }
+
+// Lookup returns the key with the given ID, whether active or retiring.
+func (s *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// Keys returns every key in the set ordered by key ID.
+func (s *KeySet) Keys() []*SigningKey {
+	keys := make([]*SigningKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID() < keys[j].ID() })
+	return keys
+}
+
+// LoadKeySet reads every <kid>.pem private key in dir. The key named activeKID signs new
+// tokens; all other keys are treated as retiring. activeKID may be empty when dir holds
+// exactly one key.
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+
+	var keys []*SigningKey
+	for _, path := range paths {
+		k, err := readSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if activeKID == "" {
+		if len(keys) > 1 {
+			return nil, fmt.Errorf("%d signing keys found in %s but no active key ID configured", len(keys), dir)
+		}
+		return NewKeySet(keys[0]), nil
+	}
+
+	var active *SigningKey
+	var retiring []*SigningKey
+	for _, k := range keys {
+		if k.ID() == activeKID {
+			active = k
+		} else {
+			retiring = append(retiring, k)
+		}
+	}
+	if active == nil {
+		return nil, fmt.Errorf("active signing key %q not found in %s", activeKID, dir)
+	}
+	return NewKeySet(active, retiring...), nil
+}
+
+// LoadOrGenerateKeySet behaves like LoadKeySet but, when dir holds no keys yet, generates
+// one with the given algorithm and writes it to dir first. Intended for development.
+func LoadOrGenerateKeySet(dir, activeKID, algorithm string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	if len(paths) > 0 {
+		return LoadKeySet(dir, activeKID)
+	}
+
+	kid := activeKID
+	if kid == "" {
+		kid = "dev-" + strings.ToLower(algorithm)
+	}
+	k, err := GenerateSigningKey(kid, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeSigningKey(dir, k); err != nil {
+		return nil, err
+	}
+	return NewKeySet(k), nil
+}
+
+// readSigningKey parses a PEM-encoded PKCS#8 or PKCS#1 private key named <kid>.pem.
+func readSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in signing key %s", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in signing key %s", block.Type, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not a private key", path)
+	}
+	kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return NewSigningKey(kid, signer)
+}
+
+// writeSigningKey stores the key as PKCS#8 PEM in dir/<kid>.pem, readable only by the owner.
+func writeSigningKey(dir string, k *SigningKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create signing key directory: %w", err)
+	}
+	path := filepath.Join(dir, k.ID()+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write signing key %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// accessTokenType is the JOSE "typ" header of access tokens (RFC 9068). It keeps
+// other JWTs signed with the same keys from being accepted as access tokens.
+const accessTokenType = "at+jwt"
+
+// refreshTokenBytes is the amount of randomness in an opaque refresh token.
+const refreshTokenBytes = 32
+
+// Authentication method references (RFC 8176) carried in the "amr" claim.
+const (
+	// AMRPassword marks a sign-in with a password.
+	AMRPassword = "pwd"
+	// AMRSMS marks a sign-in with a code texted to a phone.
+	AMRSMS = "sms"
+	// AMROTP marks a sign-in with an authenticator app code.
+	AMROTP = "otp"
+	// AMRMFA marks a sign-in that used more than one factor.
+	AMRMFA = "mfa"
+	// AMRHardwareKey marks a sign-in with a passkey, a key held by an authenticator.
+	AMRHardwareKey = "hwk"
+	// AMRFederated marks a sign-in through an external identity provider such as
+	// Google. RFC 8176 has no value for it; "fed" is the one in common use.
+	AMRFederated = "fed"
+)
+
+// Subject types carried in the "sub_type" claim, so a token issued to a service can
+// never be mistaken for one issued to a user.
+const (
+	// SubjectTypeUser marks tokens whose subject is a user ID.
+	SubjectTypeUser = "user"
+	// SubjectTypeClient marks tokens whose subject is an OAuth client ID.
+	SubjectTypeClient = "client"
+)
+
+// ErrInvalidToken is returned when a token fails signature, type or claim validation.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Subject describes the user an access token is issued to.
+type Subject struct {
+	UserID        uuid.UUID
+	Email         string
+	EmailVerified bool
+	PhoneVerified bool
+	Role          auth.UserRole
+	SessionID     uuid.UUID
+	AuthMethods   []string
+}
+
+// Claims are the claims carried by access tokens issued by this service.
+type Claims struct {
+	UserID        uuid.UUID     `json:"user_id"`
+	Email         string        `json:"email"`
+	EmailVerified bool          `json:"email_verified"`
+	PhoneVerified bool          `json:"phone_verified"`
+	Role          auth.UserRole `json:"role"`
+	SessionID     uuid.UUID     `json:"sid"`
+	AuthMethods   []string      `json:"amr,omitempty"`
+	SubjectType   string        `json:"sub_type,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IsClient reports whether the token was issued to an OAuth client rather than a user.
+// Tokens issued before the claim existed have no subject type and are user tokens.
+func (c *Claims) IsClient() bool {
+	return c.SubjectType == SubjectTypeClient
+}
+
+// HasAuthMethod reports whether the token was issued for a sign-in that used method.
+func (c *Claims) HasAuthMethod(method string) bool {
+	for _, m := range c.AuthMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Manager issues and validates tokens signed with the active key of a KeySet.
+type Manager struct {
+	keys          *KeySet
+	issuer        string
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+}
+
+// NewManager creates a new Manager.
+func NewManager(keys *KeySet, issuer string, accessExpiry, refreshExpiry time.Duration) *Manager {
+	return &Manager{
+		keys:          keys,
+		issuer:        issuer,
+		accessExpiry:  accessExpiry,
+		refreshExpiry: refreshExpiry,
+	}
+}
+
+// JWKS returns the public key set verifiers should use for tokens issued by this Manager.
+func (m *Manager) JWKS() JWKS { return m.keys.JWKS() }
+
+// Issuer returns the "iss" claim value of issued tokens.
+func (m *Manager) Issuer() string { return m.issuer }
+
+// AccessExpiry returns the lifetime of access tokens.
+func (m *Manager) AccessExpiry() time.Duration { return m.accessExpiry }
+
+// SigningAlgorithm returns the algorithm of the key new tokens are signed with.
+func (m *Manager) SigningAlgorithm() string { return m.keys.Active().Algorithm() }
+
+// GenerateAccessToken issues a signed access token for the subject. Each token gets a
+// unique "jti" so it can be revoked on its own.
+func (m *Manager) GenerateAccessToken(subject Subject) (string, error) {
+	now := time.Now().UTC()
+	claims := Claims{
+		UserID:        subject.UserID,
+		Email:         subject.Email,
+		EmailVerified: subject.EmailVerified,
+		PhoneVerified: subject.PhoneVerified,
+		Role:          subject.Role,
+		SessionID:     subject.SessionID,
+		AuthMethods:   subject.AuthMethods,
+		SubjectType:   SubjectTypeUser,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    m.issuer,
+			Subject:   subject.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessExpiry)),
+		},
+	}
+	return m.sign(accessTokenType, claims)
+}
+
+// ValidateAccessToken verifies a user access token's signature, type, issuer and
+// expiry. Tokens issued to OAuth clients are rejected.
+func (m *Manager) ValidateAccessToken(tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+	if err := m.parse(tokenStr, accessTokenType, claims); err != nil {
+		return nil, err
+	}
+	if claims.IsClient() {
+		return nil, fmt.Errorf("%w: issued to a client", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// GenerateRefreshToken returns a new opaque refresh token and its expiry. Refresh tokens
+// are random bearer secrets rather than JWTs; they are only ever checked against storage.
+func (m *Manager) GenerateRefreshToken() (string, time.Time, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), time.Now().UTC().Add(m.refreshExpiry), nil
+}
+
+// sign serializes the claims as a JWT signed with the active key, setting the kid and typ headers.
+func (m *Manager) sign(tokenType string, claims jwt.Claims) (string, error) {
+	key := m.keys.Active()
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID()
+	token.Header["typ"] = tokenType
+
+	signed, err := token.SignedString(key.private)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// parse verifies a JWT against the key named by its kid header and decodes it into claims.
+func (m *Manager) parse(tokenStr, tokenType string, claims jwt.Claims, opts ...jwt.ParserOption) error {
+	opts = append(opts,
+		jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != tokenType {
+			return nil, fmt.Errorf("unexpected token type %q", typ)
+		}
+		kid, _ := t.Header["kid"].(string)
+		key, ok := m.keys.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if t.Method.Alg() != key.Algorithm() {
+			return nil, fmt.Errorf("algorithm %s does not match key %q", t.Method.Alg(), kid)
+		}
+		return key.Public(), nil
+	}, opts...)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if !token.Valid {
+		return ErrInvalidToken
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package tokens_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/lib-common/auth"
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/tokens"
+	"github.com/google/uuid"
+)
+
+func mustGenerateKey(t *testing.T, kid, alg string) *tokens.SigningKey {
+	t.Helper()
+	key, err := tokens.GenerateSigningKey(kid, alg)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey(%s, %s) failed: %v", kid, alg, err)
+	}
+	return key
+}
+
+func testSubject() tokens.Subject {
+	return tokens.Subject{
+		UserID:    uuid.New(),
+		Email:     "runner@kilat.my",
+		Role:      auth.RoleRunner,
+		SessionID: uuid.New(),
+	}
+}
+
+func TestManager_AccessTokenRoundTrip(t *testing.T) {
+	for _, alg := range []string{tokens.AlgRS256, tokens.AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			m := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", alg)), "test-issuer", time.Minute, time.Hour)
+			subject := testSubject()
+
+			signed, err := m.GenerateAccessToken(subject)
+			if err != nil {
+				t.Fatalf("GenerateAccessToken failed: %v", err)
+			}
+
+			claims, err := m.ValidateAccessToken(signed)
+			if err != nil {
+				t.Fatalf("ValidateAccessToken failed: %v", err)
+			}
+			if claims.UserID != subject.UserID || claims.SessionID != subject.SessionID || claims.Role != subject.Role {
+				t.Errorf("claims do not match subject: %+v", claims)
+			}
+			if claims.Subject != subject.UserID.String() {
+				t.Errorf("expected sub %s, got %s", subject.UserID, claims.Subject)
+			}
+		})
+	}
+}
+
+func TestManager_AccessTokensHaveUniqueIDs(t *testing.T) {
+	m := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", tokens.AlgEdDSA)), "test-issuer", time.Minute, time.Hour)
+	subject := testSubject()
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		signed, err := m.GenerateAccessToken(subject)
+		if err != nil {
+			t.Fatalf("GenerateAccessToken failed: %v", err)
+		}
+		claims, err := m.ValidateAccessToken(signed)
+		if err != nil {
+			t.Fatalf("ValidateAccessToken failed: %v", err)
+		}
+		if claims.ID == "" || seen[claims.ID] {
+			t.Fatalf("expected a new jti, got %q", claims.ID)
+		}
+		seen[claims.ID] = true
+	}
+}
+
+func TestManager_ClientTokensAreNotUserTokens(t *testing.T) {
+	m := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", tokens.AlgEdDSA)), "test-issuer", time.Minute, time.Hour)
+
+	clientToken, _, err := m.GenerateClientToken("svc_booking", []string{"bookings:read", "users:read"})
+	if err != nil {
+		t.Fatalf("GenerateClientToken failed: %v", err)
+	}
+	claims, err := m.ValidateClientToken(clientToken)
+	if err != nil {
+		t.Fatalf("ValidateClientToken failed: %v", err)
+	}
+	if claims.Subject != "svc_booking" || !claims.HasScope("users:read") || claims.HasScope("admin") {
+		t.Errorf("unexpected client claims: %+v", claims)
+	}
+	if _, err := m.ValidateAccessToken(clientToken); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected a client token to be rejected as a user token, got %v", err)
+	}
+
+	userToken, err := m.GenerateAccessToken(testSubject())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+	if _, err := m.ValidateClientToken(userToken); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected a user token to be rejected as a client token, got %v", err)
+	}
+}
+
+func TestManager_RetiringKeyStillVerifies(t *testing.T) {
+	oldKey := mustGenerateKey(t, "2026-01", tokens.AlgRS256)
+	newKey := mustGenerateKey(t, "2026-02", tokens.AlgEdDSA)
+
+	before := tokens.NewManager(tokens.NewKeySet(oldKey), "test-issuer", time.Minute, time.Hour)
+	signed, err := before.GenerateAccessToken(testSubject())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+
+	after := tokens.NewManager(tokens.NewKeySet(newKey, oldKey), "test-issuer", time.Minute, time.Hour)
+	if _, err := after.ValidateAccessToken(signed); err != nil {
+		t.Errorf("expected token signed by retiring key to validate, got %v", err)
+	}
+
+	removed := tokens.NewManager(tokens.NewKeySet(newKey), "test-issuer", time.Minute, time.Hour)
+	if _, err := removed.ValidateAccessToken(signed); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken once the old key is removed, got %v", err)
+	}
+}
+
+func TestManager_RejectsExpiredAndForeignTokens(t *testing.T) {
+	m := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", tokens.AlgEdDSA)), "test-issuer", -time.Minute, time.Hour)
+	expired, err := m.GenerateAccessToken(testSubject())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+	if _, err := m.ValidateAccessToken(expired); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected expired token to be rejected, got %v", err)
+	}
+
+	other := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", tokens.AlgEdDSA)), "test-issuer", time.Minute, time.Hour)
+	foreign, err := other.GenerateAccessToken(testSubject())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+	if _, err := m.ValidateAccessToken(foreign); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected token signed by a different key with the same kid to be rejected, got %v", err)
+	}
+}
+
+func TestManager_MFAChallengeIsNotAnAccessToken(t *testing.T) {
+	m := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", tokens.AlgEdDSA)), "test-issuer", time.Minute, time.Hour)
+	userID := uuid.New()
+
+	challenge, err := m.GenerateMFAChallenge(userID, []string{tokens.AMRPassword})
+	if err != nil {
+		t.Fatalf("GenerateMFAChallenge failed: %v", err)
+	}
+	verified, err := m.ValidateMFAChallenge(challenge)
+	if err != nil {
+		t.Fatalf("ValidateMFAChallenge failed: %v", err)
+	}
+	if verified.UserID != userID || len(verified.AuthMethods) != 1 || verified.AuthMethods[0] != tokens.AMRPassword {
+		t.Errorf("unexpected challenge: %+v", verified)
+	}
+
+	if _, err := m.ValidateAccessToken(challenge); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected challenge to be rejected as an access token, got %v", err)
+	}
+	access, err := m.GenerateAccessToken(testSubject())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+	if _, err := m.ValidateMFAChallenge(access); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected access token to be rejected as a challenge, got %v", err)
+	}
+}
+
+func TestManager_IDTokenIsNotAnAccessToken(t *testing.T) {
+	m := tokens.NewManager(tokens.NewKeySet(mustGenerateKey(t, "k1", tokens.AlgEdDSA)), "test-issuer", time.Minute, time.Hour)
+
+	idToken, err := m.GenerateIDToken("test-issuer", tokens.IDTokenSubject{
+		UserID:    uuid.New(),
+		ClientID:  "svc_dashboard",
+		SessionID: uuid.New(),
+		Nonce:     "n-0S6_WzA2Mj",
+		AuthTime:  time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("GenerateIDToken failed: %v", err)
+	}
+	if _, err := m.ValidateAccessToken(idToken); !errors.Is(err, tokens.ErrInvalidToken) {
+		t.Errorf("expected ID token to be rejected as an access token, got %v", err)
+	}
+}
+
+func TestKeySet_JWKSPublishesEveryKey(t *testing.T) {
+	set := tokens.NewKeySet(mustGenerateKey(t, "b-ed", tokens.AlgEdDSA), mustGenerateKey(t, "a-rsa", tokens.AlgRS256))
+
+	jwks := set.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(jwks.Keys))
+	}
+	rsaKey, edKey := jwks.Keys[0], jwks.Keys[1]
+	if rsaKey.KeyID != "a-rsa" || rsaKey.KeyType != "RSA" || rsaKey.N == "" || rsaKey.E != "AQAB" {
+		t.Errorf("unexpected RSA JWK: %+v", rsaKey)
+	}
+	if edKey.KeyID != "b-ed" || edKey.KeyType != "OKP" || edKey.Curve != "Ed25519" || edKey.X == "" {
+		t.Errorf("unexpected Ed25519 JWK: %+v", edKey)
+	}
+}
+
+func TestLoadOrGenerateKeySet_PersistsGeneratedKey(t *testing.T) {
+	dir := t.TempDir()
+
+	generated, err := tokens.LoadOrGenerateKeySet(dir, "", tokens.AlgEdDSA)
+	if err != nil {
+		t.Fatalf("LoadOrGenerateKeySet failed: %v", err)
+	}
+
+	loaded, err := tokens.LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatalf("LoadKeySet failed: %v", err)
+	}
+	if loaded.Active().ID() != generated.Active().ID() {
+		t.Errorf("expected reloaded active kid %s, got %s", generated.Active().ID(), loaded.Active().ID())
+	}
+	if loaded.Active().Algorithm() != tokens.AlgEdDSA {
+		t.Errorf("expected EdDSA, got %s", loaded.Active().Algorithm())
+	}
+}
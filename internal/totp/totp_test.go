@@ -0,0 +1,87 @@
+package totp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/totp"
+)
+
+// rfcSecret is the SHA1 seed "12345678901234567890" from RFC 6238 appendix B, base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits.
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, v := range vectors {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) failed: %v", v.unix, err)
+		}
+		if code != v.code {
+			t.Errorf("Code(%d) = %s, want %s", v.unix, code, v.code)
+		}
+	}
+}
+
+func TestValidate_AcceptsAdjacentStepOnly(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, _ := totp.Code(rfcSecret, totp.Step(now)-1)
+	stale, _ := totp.Code(rfcSecret, totp.Step(now)-2)
+
+	step, ok := totp.Validate(rfcSecret, previous, now, 1)
+	if !ok || step != totp.Step(now)-1 {
+		t.Errorf("expected previous step to validate as %d, got %d (ok=%v)", totp.Step(now)-1, step, ok)
+	}
+	if _, ok := totp.Validate(rfcSecret, stale, now, 1); ok {
+		t.Error("expected a code two steps old to be rejected")
+	}
+	if _, ok := totp.Validate(rfcSecret, "12345", now, 1); ok {
+		t.Error("expected a short code to be rejected")
+	}
+}
+
+func TestGenerateSecret_RoundTripsThroughCode(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("expected 32 base32 characters for a 160-bit secret, got %d", len(secret))
+	}
+	now := time.Now()
+	code, err := totp.Code(secret, totp.Step(now))
+	if err != nil {
+		t.Fatalf("Code failed: %v", err)
+	}
+	if _, ok := totp.Validate(secret, code, now, 0); !ok {
+		t.Error("expected freshly generated code to validate")
+	}
+}
+
+func TestURI_ContainsSecretAndIssuer(t *testing.T) {
+	uri := totp.URI("Kilat Pet", "admin@kilat.my", rfcSecret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Kilat%20Pet:admin@kilat.my?") {
+		t.Fatalf("unexpected label in %s", uri)
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("URI is not a valid URL: %v", err)
+	}
+	q := parsed.Query()
+	if q.Get("secret") != rfcSecret || q.Get("issuer") != "Kilat Pet" || q.Get("digits") != "6" || q.Get("period") != "30" {
+		t.Errorf("unexpected query parameters: %v", q)
+	}
+}
@@ -0,0 +1,115 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers (RFC 9053) accepted for credentials.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms lists the accepted algorithms in order of preference.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key parameters (RFC 9052 section 7 and RFC 9053 section 7).
+const (
+	coseKeyType int64 = 1
+	coseKeyAlg  int64 = 3
+	coseCurve   int64 = -1
+	coseX       int64 = -2
+	coseY       int64 = -3
+	coseRSAN    int64 = -1
+	coseRSAE    int64 = -2
+	coseKtyOKP  int64 = 1
+	coseKtyEC2  int64 = 2
+	coseKtyRSA  int64 = 3
+	coseP256    int64 = 1
+	coseEd25519 int64 = 6
+	minRSABits        = 2048
+)
+
+// publicKey is a credential public key decoded from its COSE_Key form.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parsePublicKey decodes a COSE_Key into a public key of a supported algorithm.
+func parsePublicKey(coseKey []byte) (*publicKey, error) {
+	item, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("invalid public key: trailing data")
+	}
+	params, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("invalid public key: not a map")
+	}
+
+	kty, _ := params[coseKeyType].(int64)
+	alg, _ := params[coseKeyAlg].(int64)
+
+	switch {
+	case alg == AlgES256 && kty == coseKtyEC2:
+		crv, _ := params[coseCurve].(int64)
+		x, _ := params[coseX].([]byte)
+		y, _ := params[coseY].([]byte)
+		if crv != coseP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid public key: malformed P-256 key")
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid public key: point is not on the curve")
+		}
+		return &publicKey{alg: alg, key: key}, nil
+	case alg == AlgEdDSA && kty == coseKtyOKP:
+		crv, _ := params[coseCurve].(int64)
+		x, _ := params[coseX].([]byte)
+		if crv != coseEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key: malformed Ed25519 key")
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case alg == AlgRS256 && kty == coseKtyRSA:
+		n, _ := params[coseRSAN].([]byte)
+		e, _ := params[coseRSAE].([]byte)
+		if len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid public key: malformed RSA exponent")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < minRSABits {
+			return nil, errors.New("invalid public key: RSA key too short")
+		}
+		return &publicKey{alg: alg, key: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key algorithm %d", alg)
+	}
+}
+
+// verify checks sig over data with the key's algorithm.
+func (k *publicKey) verify(data, sig []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, digest[:], sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, sig)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	default:
+		return false
+	}
+}
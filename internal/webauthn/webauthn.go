@@ -0,0 +1,372 @@
+// Package webauthn implements the relying party side of the W3C Web Authentication
+// ceremonies used for passkeys: building the options passed to
+// navigator.credentials.create/get and verifying what the authenticator returns.
+//
+// Attestation is not requested and attestation statements are not verified; a
+// passkey is trusted because the user registered it while signed in, not because
+// of who made the authenticator.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// ChallengeSize is the length of generated challenges; the spec asks for at least 16 bytes.
+	ChallengeSize = 32
+	// Timeout is how long the browser lets the user complete a ceremony.
+	Timeout = 5 * time.Minute
+)
+
+// Authenticator data flags (WebAuthn section 6.1).
+const (
+	flagUserPresent      byte = 0x01
+	flagUserVerified     byte = 0x04
+	flagAttestedCredData byte = 0x40
+)
+
+var (
+	// ErrInvalidResponse is returned when a ceremony response fails verification.
+	ErrInvalidResponse = errors.New("invalid webauthn response")
+	// ErrSignCountRegression is returned when an authenticator reports a signature
+	// counter that did not increase, which suggests a cloned credential.
+	ErrSignCountRegression = errors.New("authenticator signature counter did not increase")
+)
+
+// URLEncoded is a byte slice that travels in JSON as unpadded base64url, the encoding
+// WebAuthn JSON serializations use for binary fields.
+type URLEncoded []byte
+
+// MarshalJSON encodes the bytes as an unpadded base64url string.
+func (u URLEncoded) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(u))
+}
+
+// UnmarshalJSON decodes a base64url string, with or without padding.
+func (u *URLEncoded) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return fmt.Errorf("invalid base64url value: %w", err)
+	}
+	*u = decoded
+	return nil
+}
+
+// RelyingParty identifies this service to authenticators.
+type RelyingParty struct {
+	// ID is the registrable domain credentials are scoped to, e.g. "kilat.my".
+	ID string
+	// Name is shown to the user by the authenticator.
+	Name string
+	// Origins lists the exact origins ceremonies may run on, e.g. "https://app.kilat.my".
+	Origins []string
+}
+
+// NewRelyingParty creates a RelyingParty.
+func NewRelyingParty(id, name string, origins []string) *RelyingParty {
+	return &RelyingParty{ID: id, Name: name, Origins: origins}
+}
+
+// GenerateChallenge returns a new random challenge.
+func GenerateChallenge() ([]byte, error) {
+	b := make([]byte, ChallengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate webauthn challenge: %w", err)
+	}
+	return b, nil
+}
+
+// User describes the account a credential is created for.
+type User struct {
+	ID          URLEncoded `json:"id"`
+	Name        string     `json:"name"`
+	DisplayName string     `json:"displayName"`
+}
+
+// CredentialDescriptor identifies an existing credential.
+type CredentialDescriptor struct {
+	Type       string     `json:"type"`
+	ID         URLEncoded `json:"id"`
+	Transports []string   `json:"transports,omitempty"`
+}
+
+// CredentialParameter names an acceptable key type.
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// RelyingPartyEntity is the relying party as described in creation options.
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuthenticatorSelection states what kind of authenticator is wanted.
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions is the publicKey member of navigator.credentials.create options.
+type CreationOptions struct {
+	Challenge              URLEncoded             `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   User                   `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions is the publicKey member of navigator.credentials.get options.
+type RequestOptions struct {
+	Challenge        URLEncoded             `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	Timeout          int64                  `json:"timeout"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// CreationOptions builds registration options for a discoverable credential that
+// requires user verification. Credentials in exclude are not registered twice.
+func (rp *RelyingParty) CreationOptions(challenge []byte, user User, exclude []CredentialDescriptor) CreationOptions {
+	params := make([]CredentialParameter, len(SupportedAlgorithms))
+	for i, alg := range SupportedAlgorithms {
+		params[i] = CredentialParameter{Type: "public-key", Alg: alg}
+	}
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+	return CreationOptions{
+		Challenge:              challenge,
+		RP:                     RelyingPartyEntity{ID: rp.ID, Name: rp.Name},
+		User:                   user,
+		PubKeyCredParams:       params,
+		Timeout:                Timeout.Milliseconds(),
+		ExcludeCredentials:     exclude,
+		AuthenticatorSelection: AuthenticatorSelection{ResidentKey: "required", UserVerification: "required"},
+		Attestation:            "none",
+	}
+}
+
+// RequestOptions builds sign-in options. With no allowed credentials the browser
+// offers every passkey it holds for the relying party.
+func (rp *RelyingParty) RequestOptions(challenge []byte, allow []CredentialDescriptor) RequestOptions {
+	if allow == nil {
+		allow = []CredentialDescriptor{}
+	}
+	return RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.ID,
+		Timeout:          Timeout.Milliseconds(),
+		AllowCredentials: allow,
+		UserVerification: "required",
+	}
+}
+
+// AttestationResponse is the JSON serialization of the PublicKeyCredential returned
+// by navigator.credentials.create.
+type AttestationResponse struct {
+	ID       string     `json:"id"`
+	RawID    URLEncoded `json:"rawId"`
+	Type     string     `json:"type"`
+	Response struct {
+		ClientDataJSON    URLEncoded `json:"clientDataJSON"`
+		AttestationObject URLEncoded `json:"attestationObject"`
+		Transports        []string   `json:"transports"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON serialization of the PublicKeyCredential returned
+// by navigator.credentials.get.
+type AssertionResponse struct {
+	ID       string     `json:"id"`
+	RawID    URLEncoded `json:"rawId"`
+	Type     string     `json:"type"`
+	Response struct {
+		ClientDataJSON    URLEncoded `json:"clientDataJSON"`
+		AuthenticatorData URLEncoded `json:"authenticatorData"`
+		Signature         URLEncoded `json:"signature"`
+		UserHandle        URLEncoded `json:"userHandle"`
+	} `json:"response"`
+}
+
+// Credential is a newly registered credential, ready to be stored.
+type Credential struct {
+	ID         []byte
+	PublicKey  []byte
+	SignCount  uint32
+	Transports []string
+}
+
+// VerifyRegistration checks a registration response against the challenge that was
+// issued for it and returns the credential to store.
+func (rp *RelyingParty) VerifyRegistration(challenge []byte, resp *AttestationResponse) (*Credential, error) {
+	if resp.Type != "public-key" {
+		return nil, fmt.Errorf("%w: unexpected credential type", ErrInvalidResponse)
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	item, _, err := decodeCBOR(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestation object: %v", ErrInvalidResponse, err)
+	}
+	attestation, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation object is not a map", ErrInvalidResponse)
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation object has no authenticator data", ErrInvalidResponse)
+	}
+
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredData == 0 || authData.credentialID == nil {
+		return nil, fmt.Errorf("%w: no attested credential data", ErrInvalidResponse)
+	}
+	if !bytes.Equal(authData.credentialID, resp.RawID) {
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrInvalidResponse)
+	}
+	if _, err := parsePublicKey(authData.publicKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	return &Credential{
+		ID:         authData.credentialID,
+		PublicKey:  authData.publicKey,
+		SignCount:  authData.signCount,
+		Transports: resp.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion checks a sign-in response against the challenge that was issued for
+// it and the stored credential, and returns the authenticator's new signature counter.
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, resp *AssertionResponse, credentialPublicKey []byte, storedSignCount uint32) (uint32, error) {
+	if resp.Type != "public-key" {
+		return 0, fmt.Errorf("%w: unexpected credential type", ErrInvalidResponse)
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := rp.parseAuthenticatorData(resp.Response.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(credentialPublicKey)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	clientDataHash := sha256.Sum256(resp.Response.ClientDataJSON)
+	signed := append(append([]byte{}, resp.Response.AuthenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, resp.Response.Signature) {
+		return 0, fmt.Errorf("%w: bad signature", ErrInvalidResponse)
+	}
+
+	// Authenticators that do not implement a counter always report zero.
+	if (authData.signCount != 0 || storedSignCount != 0) && authData.signCount <= storedSignCount {
+		return 0, ErrSignCountRegression
+	}
+	return authData.signCount, nil
+}
+
+// clientData is the subset of CollectedClientData that is checked.
+type clientData struct {
+	Type      string     `json:"type"`
+	Challenge URLEncoded `json:"challenge"`
+	Origin    string     `json:"origin"`
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("%w: client data: %v", ErrInvalidResponse, err)
+	}
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: unexpected ceremony %q", ErrInvalidResponse, data.Type)
+	}
+	if len(challenge) == 0 || subtle.ConstantTimeCompare(data.Challenge, challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidResponse)
+	}
+	for _, origin := range rp.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: origin %q is not allowed", ErrInvalidResponse, data.Origin)
+}
+
+// authenticatorData is parsed authenticator data (WebAuthn section 6.1).
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+// parseAuthenticatorData parses authenticator data and checks the relying party ID
+// hash and that the user was both present and verified.
+func (rp *RelyingParty) parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(raw[:32], rpIDHash[:]) != 1 {
+		return nil, fmt.Errorf("%w: relying party ID mismatch", ErrInvalidResponse)
+	}
+
+	data := &authenticatorData{
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	if data.flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrInvalidResponse)
+	}
+	if data.flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("%w: user not verified", ErrInvalidResponse)
+	}
+
+	if data.flags&flagAttestedCredData != 0 {
+		rest := raw[37:]
+		// 16 byte AAGUID, then a 2 byte credential ID length.
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+		}
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: malformed credential ID", ErrInvalidResponse)
+		}
+		data.credentialID = append([]byte(nil), rest[:idLen]...)
+		rest = rest[idLen:]
+
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: credential public key: %v", ErrInvalidResponse, err)
+		}
+		data.publicKey = append([]byte(nil), rest[:len(rest)-len(after)]...)
+	}
+	return data, nil
+}
@@ -0,0 +1,269 @@
+package webauthn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Kilat-Pet-Delivery/service-identity/internal/webauthn"
+)
+
+const testOrigin = "https://app.kilat.my"
+
+func testRelyingParty() *webauthn.RelyingParty {
+	return webauthn.NewRelyingParty("kilat.my", "Kilat Pet", []string{testOrigin})
+}
+
+// cborPair is a map entry for encodeCBOR; a slice keeps the encoding deterministic.
+type cborPair struct {
+	key, value interface{}
+}
+
+// encodeCBOR encodes the handful of types the fake authenticator needs.
+func encodeCBOR(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 1<<8:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			b := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(b[1:], uint16(n))
+			return b
+		}
+	}
+	switch v := v.(type) {
+	case int:
+		if v >= 0 {
+			return head(0, uint64(v))
+		}
+		return head(1, uint64(-1-v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case []cborPair:
+		out := head(5, uint64(len(v)))
+		for _, p := range v {
+			out = append(out, encodeCBOR(p.key)...)
+			out = append(out, encodeCBOR(p.value)...)
+		}
+		return out
+	default:
+		panic("unsupported type")
+	}
+}
+
+// fakeAuthenticator is a software passkey holding a single P-256 or Ed25519 key.
+type fakeAuthenticator struct {
+	credentialID []byte
+	ecKey        *ecdsa.PrivateKey
+	edKey        ed25519.PrivateKey
+	signCount    uint32
+}
+
+func newFakeAuthenticator(t *testing.T, ed bool) *fakeAuthenticator {
+	t.Helper()
+	a := &fakeAuthenticator{credentialID: []byte("credential-" + t.Name())}
+	var err error
+	if ed {
+		_, a.edKey, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		a.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return a
+}
+
+func (a *fakeAuthenticator) coseKey() []byte {
+	if a.edKey != nil {
+		return encodeCBOR([]cborPair{
+			{1, 1}, {3, -8}, {-1, 6}, {-2, []byte(a.edKey.Public().(ed25519.PublicKey))},
+		})
+	}
+	x := a.ecKey.PublicKey.X.FillBytes(make([]byte, 32))
+	y := a.ecKey.PublicKey.Y.FillBytes(make([]byte, 32))
+	return encodeCBOR([]cborPair{{1, 2}, {3, -7}, {-1, 1}, {-2, x}, {-3, y}})
+}
+
+func (a *fakeAuthenticator) authData(rpID string, flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	out := append([]byte{}, rpIDHash[:]...)
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, a.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credentialID)))
+		out = append(out, a.credentialID...)
+		out = append(out, a.coseKey()...)
+	}
+	return out
+}
+
+func clientDataJSON(ceremony string, challenge []byte, origin string) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    origin,
+	})
+	return data
+}
+
+func (a *fakeAuthenticator) register(challenge []byte, origin string) *webauthn.AttestationResponse {
+	resp := &webauthn.AttestationResponse{ID: base64.RawURLEncoding.EncodeToString(a.credentialID), RawID: a.credentialID, Type: "public-key"}
+	resp.Response.ClientDataJSON = clientDataJSON("webauthn.create", challenge, origin)
+	resp.Response.AttestationObject = encodeCBOR([]cborPair{
+		{"fmt", "none"},
+		{"attStmt", []cborPair{}},
+		{"authData", a.authData("kilat.my", 0x45, true)},
+	})
+	resp.Response.Transports = []string{"internal", "hybrid"}
+	return resp
+}
+
+func (a *fakeAuthenticator) assert(t *testing.T, challenge []byte, origin string, flags byte) *webauthn.AssertionResponse {
+	t.Helper()
+	a.signCount++
+	resp := &webauthn.AssertionResponse{ID: base64.RawURLEncoding.EncodeToString(a.credentialID), RawID: a.credentialID, Type: "public-key"}
+	resp.Response.ClientDataJSON = clientDataJSON("webauthn.get", challenge, origin)
+	resp.Response.AuthenticatorData = a.authData("kilat.my", flags, false)
+
+	clientDataHash := sha256.Sum256(resp.Response.ClientDataJSON)
+	signed := append(append([]byte{}, resp.Response.AuthenticatorData...), clientDataHash[:]...)
+	if a.edKey != nil {
+		resp.Response.Signature = ed25519.Sign(a.edKey, signed)
+	} else {
+		digest := sha256.Sum256(signed)
+		sig, err := ecdsa.SignASN1(rand.Reader, a.ecKey, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		resp.Response.Signature = sig
+	}
+	return resp
+}
+
+func mustChallenge(t *testing.T) []byte {
+	t.Helper()
+	challenge, err := webauthn.GenerateChallenge()
+	if err != nil {
+		t.Fatalf("GenerateChallenge failed: %v", err)
+	}
+	return challenge
+}
+
+func TestRegisterAndAssert_RoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ed   bool
+	}{{"ES256", false}, {"EdDSA", true}} {
+		t.Run(tc.name, func(t *testing.T) {
+			rp := testRelyingParty()
+			authenticator := newFakeAuthenticator(t, tc.ed)
+
+			challenge := mustChallenge(t)
+			credential, err := rp.VerifyRegistration(challenge, authenticator.register(challenge, testOrigin))
+			if err != nil {
+				t.Fatalf("VerifyRegistration failed: %v", err)
+			}
+			if string(credential.ID) != string(authenticator.credentialID) {
+				t.Errorf("expected credential ID %q, got %q", authenticator.credentialID, credential.ID)
+			}
+			if len(credential.Transports) != 2 {
+				t.Errorf("expected transports to be kept, got %v", credential.Transports)
+			}
+
+			challenge = mustChallenge(t)
+			signCount, err := rp.VerifyAssertion(challenge, authenticator.assert(t, challenge, testOrigin, 0x05), credential.PublicKey, credential.SignCount)
+			if err != nil {
+				t.Fatalf("VerifyAssertion failed: %v", err)
+			}
+			if signCount != 1 {
+				t.Errorf("expected sign count 1, got %d", signCount)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistration_WrongOrigin(t *testing.T) {
+	rp := testRelyingParty()
+	challenge := mustChallenge(t)
+
+	_, err := rp.VerifyRegistration(challenge, newFakeAuthenticator(t, false).register(challenge, "https://kilat.my.evil.example"))
+	if !errors.Is(err, webauthn.ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+}
+
+func TestVerifyRegistration_WrongChallenge(t *testing.T) {
+	rp := testRelyingParty()
+
+	_, err := rp.VerifyRegistration(mustChallenge(t), newFakeAuthenticator(t, false).register(mustChallenge(t), testOrigin))
+	if !errors.Is(err, webauthn.ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+}
+
+func TestVerifyAssertion_Rejections(t *testing.T) {
+	rp := testRelyingParty()
+	authenticator := newFakeAuthenticator(t, false)
+	challenge := mustChallenge(t)
+	credential, err := rp.VerifyRegistration(challenge, authenticator.register(challenge, testOrigin))
+	if err != nil {
+		t.Fatalf("VerifyRegistration failed: %v", err)
+	}
+
+	t.Run("tampered signature", func(t *testing.T) {
+		challenge := mustChallenge(t)
+		resp := authenticator.assert(t, challenge, testOrigin, 0x05)
+		resp.Response.Signature[len(resp.Response.Signature)-1] ^= 0xff
+		if _, err := rp.VerifyAssertion(challenge, resp, credential.PublicKey, 0); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+
+	t.Run("user not verified", func(t *testing.T) {
+		challenge := mustChallenge(t)
+		resp := authenticator.assert(t, challenge, testOrigin, 0x01)
+		if _, err := rp.VerifyAssertion(challenge, resp, credential.PublicKey, 0); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+
+	t.Run("registration response replayed as assertion", func(t *testing.T) {
+		challenge := mustChallenge(t)
+		resp := authenticator.assert(t, challenge, testOrigin, 0x05)
+		resp.Response.ClientDataJSON = clientDataJSON("webauthn.create", challenge, testOrigin)
+		if _, err := rp.VerifyAssertion(challenge, resp, credential.PublicKey, 0); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+
+	t.Run("sign count regression", func(t *testing.T) {
+		challenge := mustChallenge(t)
+		resp := authenticator.assert(t, challenge, testOrigin, 0x05)
+		if _, err := rp.VerifyAssertion(challenge, resp, credential.PublicKey, 100); !errors.Is(err, webauthn.ErrSignCountRegression) {
+			t.Errorf("expected ErrSignCountRegression, got %v", err)
+		}
+	})
+}
+
+func TestURLEncoded_AcceptsPadding(t *testing.T) {
+	var v webauthn.URLEncoded
+	if err := json.Unmarshal([]byte(`"aGk="`), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if string(v) != "hi" {
+		t.Errorf("expected %q, got %q", "hi", v)
+	}
+}
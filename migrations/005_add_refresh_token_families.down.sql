@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_family;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS used_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
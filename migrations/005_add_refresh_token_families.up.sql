@@ -0,0 +1,8 @@
+ALTER TABLE refresh_tokens ADD COLUMN family_id UUID;
+ALTER TABLE refresh_tokens ADD COLUMN used_at TIMESTAMPTZ;
+
+-- Existing tokens each become the sole member of their own family.
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
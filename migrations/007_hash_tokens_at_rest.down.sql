@@ -0,0 +1,11 @@
+-- Hashed tokens cannot be turned back into plaintext; outstanding tokens are dropped.
+DELETE FROM password_resets;
+ALTER TABLE password_resets DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE password_resets ADD COLUMN token TEXT UNIQUE NOT NULL;
+CREATE INDEX idx_password_resets_token ON password_resets(token);
+
+DELETE FROM refresh_tokens;
+UPDATE sessions SET revoked_at = NOW() WHERE revoked_at IS NULL;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE refresh_tokens ADD COLUMN token TEXT UNIQUE NOT NULL;
+CREATE INDEX idx_refresh_tokens_token ON refresh_tokens(token);
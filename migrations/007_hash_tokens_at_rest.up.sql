@@ -0,0 +1,16 @@
+-- Refresh and password reset tokens are now stored as HMAC-SHA256 digests keyed with
+-- TOKEN_PEPPER. The pepper is not available to SQL, so existing plaintext tokens cannot
+-- be rehashed here; they are invalidated instead. Every user has to sign in again and
+-- any outstanding password reset link stops working.
+DELETE FROM refresh_tokens;
+UPDATE sessions SET revoked_at = NOW() WHERE revoked_at IS NULL;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+ALTER TABLE refresh_tokens DROP COLUMN token;
+ALTER TABLE refresh_tokens ADD COLUMN token_hash CHAR(64) UNIQUE NOT NULL;
+
+DELETE FROM password_resets;
+
+DROP INDEX IF EXISTS idx_password_resets_token;
+ALTER TABLE password_resets DROP COLUMN token;
+ALTER TABLE password_resets ADD COLUMN token_hash CHAR(64) UNIQUE NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'banned', 'deleted')),
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN status_changed_by UUID,
+    ADD COLUMN status_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN status_expires_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_status ON users(status);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_invitations_email;
+DROP TABLE IF EXISTS invitations;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('owner', 'runner', 'admin'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_phone_otps_user;
+DROP INDEX IF EXISTS idx_phone_otps_phone_purpose_created;
+DROP TABLE IF EXISTS phone_otps;
+
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified;
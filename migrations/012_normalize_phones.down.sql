@@ -0,0 +1,2 @@
+-- Phone normalization is not reversed.
+DROP INDEX IF EXISTS idx_users_verified_phone;
@@ -0,0 +1,16 @@
+-- Bring stored numbers to the E.164 form identity.NewPhone now produces, assuming
+-- Malaysia (+60) for numbers without a country code.
+UPDATE users SET phone = regexp_replace(phone, '[^0-9+]', '', 'g') WHERE phone IS NOT NULL AND phone <> '';
+UPDATE users SET phone = '+' || substr(phone, 3) WHERE phone LIKE '00%';
+UPDATE users SET phone = '+60' || substr(phone, 2) WHERE phone LIKE '0%';
+UPDATE users SET phone = '+' || phone WHERE phone <> '' AND phone NOT LIKE '+%' AND phone LIKE '60%';
+UPDATE users SET phone = '+60' || phone WHERE phone <> '' AND phone NOT LIKE '+%';
+
+-- A verified number must identify one account; keep the most recently updated claim.
+UPDATE users u SET phone_verified = FALSE
+FROM users o
+WHERE u.phone = o.phone
+  AND u.phone_verified AND o.phone_verified
+  AND (o.updated_at > u.updated_at OR (o.updated_at = u.updated_at AND o.id > u.id));
+
+CREATE UNIQUE INDEX idx_users_verified_phone ON users(phone) WHERE phone_verified;
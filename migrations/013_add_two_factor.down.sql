@@ -0,0 +1,3 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS auth_methods;
+DROP TABLE IF EXISTS mfa_required_roles;
+DROP TABLE IF EXISTS totp_factors;
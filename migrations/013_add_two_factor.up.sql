@@ -0,0 +1,17 @@
+CREATE TABLE totp_factors (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_encrypted TEXT NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE mfa_required_roles (
+    role VARCHAR(20) PRIMARY KEY CHECK (role IN ('owner', 'runner', 'admin', 'shop')),
+    updated_by UUID NOT NULL REFERENCES users(id),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Authentication methods (RFC 8176 "amr") each session was signed in with;
+-- existing sessions predate the column and carry none.
+ALTER TABLE sessions ADD COLUMN auth_methods TEXT[] NOT NULL DEFAULT '{}';
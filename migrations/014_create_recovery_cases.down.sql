@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recovery_events;
+DROP TABLE IF EXISTS recovery_cases;
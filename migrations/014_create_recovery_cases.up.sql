@@ -0,0 +1,34 @@
+CREATE TABLE recovery_cases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    claimed_email VARCHAR(255) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    phone VARCHAR(20),
+    contact_email VARCHAR(255) NOT NULL,
+    details TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'approved', 'rejected', 'completed')),
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT,
+    token_hash CHAR(64) UNIQUE,
+    expires_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_recovery_cases_user ON recovery_cases(user_id);
+CREATE INDEX idx_recovery_cases_claimed_email ON recovery_cases(claimed_email);
+CREATE INDEX idx_recovery_cases_status ON recovery_cases(status);
+
+CREATE TABLE recovery_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    case_id UUID NOT NULL REFERENCES recovery_cases(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    actor_id UUID REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_recovery_events_case ON recovery_events(case_id);
+CREATE INDEX idx_recovery_events_user ON recovery_events(user_id);
@@ -0,0 +1,12 @@
+-- Consecutive failed sign-ins per account (keyed by normalized email) and per client IP,
+-- and the temporary lockout they earned. Rows are removed on a successful sign-in or unlock.
+CREATE TABLE login_failures (
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('account', 'ip')),
+    key VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_login_failures_last_failed_at ON login_failures(last_failed_at);
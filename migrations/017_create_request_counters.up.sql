@@ -0,0 +1,11 @@
+-- Fixed-window request counts per scope and key (an email or client IP), used to
+-- throttle endpoints such as forgot-password whose callers need not have an account.
+CREATE TABLE request_counters (
+    scope VARCHAR(40) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    window_ends_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_request_counters_window_ends_at ON request_counters(window_ends_at);
@@ -0,0 +1,4 @@
+-- Lowercasing of stored emails is not reversed.
+DROP INDEX IF EXISTS idx_users_canonical_email;
+ALTER TABLE users DROP COLUMN IF EXISTS canonical_email;
+DROP INDEX IF EXISTS idx_users_email_lower;
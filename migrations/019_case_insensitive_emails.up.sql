@@ -0,0 +1,59 @@
+-- Emails are compared case-insensitively and stored in the lowercase form
+-- identity.NewEmail produces. Accounts whose addresses differ only by case cannot be
+-- merged automatically, so any such collision is reported and stops the migration;
+-- resolve each group (rename or delete the extra accounts) and run it again.
+DO $$
+DECLARE
+    report TEXT;
+BEGIN
+    SELECT string_agg(format('  %s: %s', lower_email, accounts), E'\n' ORDER BY lower_email)
+      INTO report
+      FROM (
+        SELECT lower(email) AS lower_email,
+               string_agg(format('%s <%s>', id, email), ', ' ORDER BY created_at) AS accounts
+          FROM users
+         GROUP BY lower(email)
+        HAVING count(*) > 1
+      ) collisions;
+
+    IF report IS NOT NULL THEN
+        RAISE EXCEPTION 'users whose emails differ only by case must be resolved first:%', E'\n' || report;
+    END IF;
+END $$;
+
+UPDATE users SET email = lower(email) WHERE email <> lower(email);
+UPDATE invitations SET email = lower(email) WHERE email <> lower(email);
+UPDATE recovery_cases SET claimed_email = lower(claimed_email) WHERE claimed_email <> lower(claimed_email);
+
+CREATE UNIQUE INDEX idx_users_email_lower ON users(lower(email));
+
+-- The canonical form (Email.Canonical) drops "+tag" suffixes and, for Gmail, dots in
+-- the local part. It is only a matching key for spotting one person behind several
+-- accounts, so it is indexed but not unique.
+ALTER TABLE users ADD COLUMN canonical_email VARCHAR(255);
+
+UPDATE users SET canonical_email =
+    CASE
+        WHEN split_part(email, '@', 2) IN ('gmail.com', 'googlemail.com') THEN
+            replace(COALESCE(NULLIF(split_part(split_part(email, '@', 1), '+', 1), ''), split_part(email, '@', 1)), '.', '') || '@gmail.com'
+        ELSE
+            COALESCE(NULLIF(split_part(split_part(email, '@', 1), '+', 1), ''), split_part(email, '@', 1)) || '@' || split_part(email, '@', 2)
+    END;
+
+CREATE INDEX idx_users_canonical_email ON users(canonical_email);
+
+-- Report, without failing, mailboxes that already hold more than one account.
+DO $$
+DECLARE
+    shared RECORD;
+BEGIN
+    FOR shared IN
+        SELECT canonical_email, count(*) AS accounts
+          FROM users
+         GROUP BY canonical_email
+        HAVING count(*) > 1
+         ORDER BY count(*) DESC, canonical_email
+    LOOP
+        RAISE NOTICE 'canonical email % is shared by % accounts', shared.canonical_email, shared.accounts;
+    END LOOP;
+END $$;
@@ -0,0 +1,14 @@
+-- Access tokens withdrawn before they expire: one token (by jti), every token of a
+-- session (by sid) or every token of a user issued up to revoked_at. A row can be
+-- deleted once expires_at passes, as every token it covers has expired by then.
+CREATE TABLE token_revocations (
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('token', 'session', 'user')),
+    key VARCHAR(64) NOT NULL,
+    user_id UUID NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_token_revocations_user_id ON token_revocations(user_id);
+CREATE INDEX idx_token_revocations_expires_at ON token_revocations(expires_at);
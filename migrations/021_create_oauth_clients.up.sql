@@ -0,0 +1,14 @@
+-- Services registered to obtain their own access tokens with the OAuth2 client
+-- credentials grant. Secrets are stored as keyed hashes (HMAC-SHA256 with the token pepper).
+CREATE TABLE oauth_clients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    client_id VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    secret_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NOT NULL REFERENCES users(id),
+    secret_rotated_at TIMESTAMPTZ NOT NULL,
+    disabled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS oauth_authorization_codes;
+ALTER TABLE oauth_clients DROP COLUMN IF EXISTS redirect_uris;
@@ -0,0 +1,26 @@
+-- OpenID Connect clients send users back to exactly one of their registered redirect URIs.
+ALTER TABLE oauth_clients ADD COLUMN redirect_uris TEXT[] NOT NULL DEFAULT '{}';
+
+-- Single-use authorization codes of the OpenID Connect authorization code flow, bound
+-- to the client, the redirect URI and a PKCE S256 challenge. Codes are stored as keyed
+-- hashes (HMAC-SHA256 with the token pepper). session_id records the session a code
+-- was redeemed for, which is ended if the code is presented again.
+CREATE TABLE oauth_authorization_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    client_id VARCHAR(64) NOT NULL REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    nonce VARCHAR(255) NOT NULL DEFAULT '',
+    code_challenge VARCHAR(128) NOT NULL,
+    auth_methods TEXT[] NOT NULL DEFAULT '{}',
+    auth_time TIMESTAMPTZ NOT NULL,
+    session_id UUID,
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_oauth_authorization_codes_client_id ON oauth_authorization_codes(client_id);
+CREATE INDEX idx_oauth_authorization_codes_user_id ON oauth_authorization_codes(user_id);
@@ -0,0 +1,12 @@
+-- Accounts at external identity providers (Google, Apple) that users sign in with.
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    CONSTRAINT uq_user_identities_provider_subject UNIQUE (provider, subject),
+    CONSTRAINT uq_user_identities_user_provider UNIQUE (user_id, provider)
+);